@@ -10,7 +10,9 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/busyster996/dagflow/cmd/api"
+	"github.com/busyster996/dagflow/cmd/migrate"
 	"github.com/busyster996/dagflow/cmd/standalone"
+	"github.com/busyster996/dagflow/cmd/validate"
 	"github.com/busyster996/dagflow/cmd/worker"
 	"github.com/busyster996/dagflow/internal/utility"
 	"github.com/busyster996/dagflow/pkg/info"
@@ -59,11 +61,22 @@ func main() {
 	cmd.PersistentFlags().String("self_url", "https://oss.yfdou.com/tools/dagflow", "self Update URL")
 	cmd.PersistentFlags().String("mq_url", "inmemory://localhost", "message queue url. [inmemory,amqp]")
 	cmd.PersistentFlags().String("db_url", "sqlite://localhost", "database type. [sqlite,mysql,postgres,sqlserver]")
+	cmd.PersistentFlags().String("storage_engine", "gorm", "storage engine of tasks and steps. [gorm,ent]")
+	cmd.PersistentFlags().String("step_log_storage", "database", "step log storage. [database,file]")
+	cmd.PersistentFlags().Duration("gc_interval", time.Hour, "interval of the janitor, 0 disables it")
+	cmd.PersistentFlags().Duration("gc_task_ttl", 0, "remove finished tasks older than this, 0 keeps them")
+	cmd.PersistentFlags().Bool("gc_task_archive", false, "archive tasks to <root_dir>/archive before removing them")
+	cmd.PersistentFlags().Int("gc_task_runs", 0, "number of previous runs kept per task, 0 keeps all")
+	cmd.PersistentFlags().Int("gc_build_keep", 0, "number of builds kept per pipeline, 0 keeps all")
+	cmd.PersistentFlags().Int64("gc_step_log_lines", 0, "number of log lines kept per step of finished tasks, 0 keeps all")
+	cmd.PersistentFlags().Duration("gc_upload_ttl", 24*time.Hour, "remove upload files older than this, 0 keeps them")
 
 	cmd.AddCommand(
 		standalone.New(),
 		api.New(),
 		worker.New(),
+		validate.New(),
+		migrate.New(),
 		&cobra.Command{
 			Use:   "version",
 			Short: "print version information and quit",
@@ -0,0 +1,45 @@
+package migrate
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/busyster996/dagflow/internal/config"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+func New() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "copy tasks, steps and step logs from the gorm tables into the ent tables",
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			_ = viper.BindPFlags(cmd.PersistentFlags())
+			_ = viper.BindPFlags(cmd.Flags())
+			// 一次性执行, 不需要自更新
+			viper.Set("enable_self_update", false)
+
+			if err := config.Init(); err != nil {
+				logx.Errorln(err)
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			defer func() {
+				_ = config.CloseDB()
+			}()
+			migrated, err := storage.MigrateToEnt(cmd.Context())
+			fmt.Fprintf(cmd.OutOrStdout(), "%d tasks migrated\n", migrated)
+			return err
+		},
+	}
+	return cmd
+}
@@ -66,6 +66,7 @@ func New() *cobra.Command {
 
 	cmd.Flags().String("node_name", "dagflow01", "node name")
 	cmd.Flags().Int("pool_size", runtime.NumCPU()*2, "set the size of the execution work pool.")
+	cmd.Flags().Int("step_budget", 0, "set the number of step slots shared by all tasks on the node, 0 is unlimited.")
 	return cmd
 }
 
@@ -76,6 +77,7 @@ type standaloneService struct {
 func (a *standaloneService) Start(s service.Service) error {
 	// 调整工作池的大小
 	worker.SetSize(viper.GetInt("pool_size"))
+	worker.SetBudget(viper.GetInt("step_budget"))
 	if err := worker.Start(a.ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,57 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/internal/server/types"
+)
+
+func New() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "validate <file>",
+		Short: "validate a task file and print the execution plan",
+		Args:  cobra.ExactArgs(1),
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			_ = viper.BindPFlags(cmd.PersistentFlags())
+			_ = viper.BindPFlags(cmd.Flags())
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			// json是yaml的子集, 同时支持两种格式及dump导出的内容
+			var task = new(types.STaskReq)
+			if err = yaml.Unmarshal(data, task); err != nil {
+				return err
+			}
+			plan := service.Validate(task)
+			out, err := yaml.Marshal(plan)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), string(out))
+			if !plan.Valid {
+				return errors.Errorf("%s is invalid", args[0])
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Duration("exec_timeout", 24*time.Hour, "set the task exec command expire time")
+
+	return cmd
+}
@@ -41,3 +41,37 @@ const (
 	KindDag      = "dag"
 	KindStrategy = "strategy"
 )
+
+// 步骤执行条件, 其他值作为expr表达式
+const (
+	// WhenSuccess 所有上游步骤成功时执行(默认)
+	WhenSuccess = "on_success"
+	// WhenFailure 任一上游步骤失败时执行
+	WhenFailure = "on_failure"
+	// WhenAlways 总是执行
+	WhenAlways = "always"
+)
+
+// 任务处理步骤, 主流程结束后按声明顺序执行
+const (
+	// HandlerSuccess 主流程成功时执行
+	HandlerSuccess = "on_success"
+	// HandlerFailure 主流程失败, 被终止或超时时执行
+	HandlerFailure = "on_failure"
+	// HandlerFinally 总是执行
+	HandlerFinally = "finally"
+)
+
+// WhenConvert 规范化执行条件, 非关键字时原样返回表达式
+func WhenConvert(when string) string {
+	switch strings.ToLower(strings.TrimSpace(when)) {
+	case "", "success", WhenSuccess:
+		return WhenSuccess
+	case "failure", WhenFailure:
+		return WhenFailure
+	case WhenAlways:
+		return WhenAlways
+	default:
+		return when
+	}
+}
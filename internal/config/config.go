@@ -35,6 +35,7 @@ func Init() error {
 	viper.Set("log_dir", filepath.Join(viper.GetString("root_dir"), "logs"))
 	viper.Set("script_dir", filepath.Join(viper.GetString("root_dir"), "scripts"))
 	viper.Set("workspace_dir", filepath.Join(viper.GetString("root_dir"), "workspace"))
+	viper.Set("step_log_dir", filepath.Join(viper.GetString("root_dir"), "steplogs"))
 	if viper.GetString("node_name") == "" {
 		viper.Set("node_name", fmt.Sprintf("%d-%d", viper.GetInt64("kind_id"), viper.GetInt64("node_id")))
 	}
@@ -56,12 +57,16 @@ func Init() error {
 		"log":       viper.GetString("log_dir"),
 		"script":    viper.GetString("script_dir"),
 		"workspace": viper.GetString("workspace_dir"),
+		"step log":  viper.GetString("step_log_dir"),
 	}
 
 	for name, dir := range dirs {
 		if name == "log" && viper.GetString("log_output") != "file" {
 			continue
 		}
+		if name == "step log" && viper.GetString("step_log_storage") != storage.LogStorageFile {
+			continue
+		}
 		if err = utility.EnsureDirExist(dir); err != nil {
 			return fmt.Errorf("failed to ensure directory %s: %v", dir, err)
 		}
@@ -174,6 +179,14 @@ func initStorage() (err error) {
 		logx.Errorln(err)
 		return err
 	}
+	if err = storage.SetEngine(viper.GetString("storage_engine")); err != nil {
+		logx.Errorln(err)
+		return err
+	}
+	if err = storage.SetLogStorage(viper.GetString("step_log_storage"), viper.GetString("step_log_dir")); err != nil {
+		logx.Errorln(err)
+		return err
+	}
 	return
 }
 
@@ -192,6 +205,11 @@ func ClosePubsub(ctx context.Context) {
 	pubsub.Shutdown(ctx)
 }
 func CloseDB() error {
+	// 写入缓冲中的步骤日志
+	storage.FlushLogs()
+	if err := storage.CloseEngine(); err != nil {
+		logx.Errorln(err)
+	}
 	sqlDB, err := db.DB()
 	if err != nil {
 		logx.Errorln(err)
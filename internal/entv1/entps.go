@@ -4,7 +4,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 
-	"modernc.org/sqlite"
+	sqlite "github.com/glebarez/go-sqlite"
 )
 
 func init() {
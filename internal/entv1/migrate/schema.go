@@ -45,6 +45,15 @@ var (
 		{Name: "timeout", Type: field.TypeInt64, Nullable: true, Default: 86400000000000},
 		{Name: "action", Type: field.TypeString, Nullable: true},
 		{Name: "rule", Type: field.TypeString, Nullable: true},
+		{Name: "run_when", Type: field.TypeString, Nullable: true},
+		{Name: "handler", Type: field.TypeString, Nullable: true},
+		{Name: "priority", Type: field.TypeInt, Nullable: true, Default: 0},
+		{Name: "weight", Type: field.TypeInt64, Nullable: true, Default: 1},
+		{Name: "mutex", Type: field.TypeString, Nullable: true},
+		{Name: "allow_failure", Type: field.TypeBool, Nullable: true, Default: false},
+		{Name: "idempotent", Type: field.TypeBool, Nullable: true, Default: false},
+		{Name: "matrix", Type: field.TypeJSON, Nullable: true},
+		{Name: "foreach", Type: field.TypeJSON, Nullable: true},
 		{Name: "retry_policy", Type: field.TypeJSON, Nullable: true},
 		{Name: "metadata", Type: field.TypeJSON, Nullable: true},
 	}
@@ -159,8 +168,8 @@ var (
 		{Name: "updated_by", Type: field.TypeString, Nullable: true},
 		{Name: "disabled", Type: field.TypeBool, Nullable: true, Default: false},
 		{Name: "message", Type: field.TypeString, Nullable: true, Size: 2147483647},
-		{Name: "state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped"}, Default: "pending"},
-		{Name: "previous_state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped"}, Default: "pending"},
+		{Name: "state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped", "waiting", "queued"}, Default: "pending"},
+		{Name: "previous_state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped", "waiting", "queued"}, Default: "pending"},
 		{Name: "start_time", Type: field.TypeTime, Nullable: true},
 		{Name: "end_time", Type: field.TypeTime, Nullable: true},
 		{Name: "name", Type: field.TypeString, Unique: true},
@@ -169,6 +178,10 @@ var (
 		{Name: "node", Type: field.TypeString, Nullable: true},
 		{Name: "timeout", Type: field.TypeInt64, Nullable: true, Default: 86400000000000},
 		{Name: "retry_policy", Type: field.TypeJSON, Nullable: true},
+		{Name: "fail_fast", Type: field.TypeBool, Nullable: true, Default: false},
+		{Name: "concurrency_group", Type: field.TypeString, Nullable: true},
+		{Name: "concurrency_policy", Type: field.TypeString, Nullable: true},
+		{Name: "metadata", Type: field.TypeJSON, Nullable: true},
 		{Name: "is_tpl", Type: field.TypeBool, Nullable: true, Default: false},
 	}
 	// TasksTable holds the schema information for the "tasks" table.
@@ -190,7 +203,7 @@ var (
 			{
 				Name:    "task_is_tpl",
 				Unique:  false,
-				Columns: []*schema.Column{TasksColumns[17]},
+				Columns: []*schema.Column{TasksColumns[21]},
 			},
 			{
 				Name:    "task_start_time",
@@ -207,6 +220,11 @@ var (
 				Unique:  false,
 				Columns: []*schema.Column{TasksColumns[7], TasksColumns[9]},
 			},
+			{
+				Name:    "task_concurrency_group_state",
+				Unique:  false,
+				Columns: []*schema.Column{TasksColumns[18], TasksColumns[7]},
+			},
 		},
 	}
 	// TaskParamsColumns holds the columns for the "task_params" table.
@@ -255,8 +273,8 @@ var (
 		{Name: "updated_by", Type: field.TypeString, Nullable: true},
 		{Name: "disabled", Type: field.TypeBool, Nullable: true, Default: false},
 		{Name: "message", Type: field.TypeString, Nullable: true, Size: 2147483647},
-		{Name: "state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped"}, Default: "pending"},
-		{Name: "previous_state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped"}, Default: "pending"},
+		{Name: "state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped", "waiting", "queued"}, Default: "pending"},
+		{Name: "previous_state", Type: field.TypeEnum, Nullable: true, Enums: []string{"unknown", "stopped", "running", "failed", "pending", "paused", "skipped", "waiting", "queued"}, Default: "pending"},
 		{Name: "start_time", Type: field.TypeTime, Nullable: true},
 		{Name: "end_time", Type: field.TypeTime, Nullable: true},
 		{Name: "seq_no", Type: field.TypeInt64, Nullable: true, Default: 0},
@@ -328,6 +346,7 @@ var (
 		{Name: "updated_at", Type: field.TypeTime, Nullable: true},
 		{Name: "created_by", Type: field.TypeString, Nullable: true},
 		{Name: "updated_by", Type: field.TypeString, Nullable: true},
+		{Name: "line", Type: field.TypeInt64, Default: 0},
 		{Name: "timestamp", Type: field.TypeInt64, Nullable: true},
 		{Name: "content", Type: field.TypeString, Nullable: true, Size: 2147483647},
 		{Name: "exec_id", Type: field.TypeUint64},
@@ -340,7 +359,7 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "task_step_outputs_task_steps_outputs",
-				Columns:    []*schema.Column{TaskStepOutputsColumns[7]},
+				Columns:    []*schema.Column{TaskStepOutputsColumns[8]},
 				RefColumns: []*schema.Column{TaskStepsColumns[0]},
 				OnDelete:   schema.Cascade,
 			},
@@ -349,12 +368,17 @@ var (
 			{
 				Name:    "taskstepoutput_timestamp",
 				Unique:  false,
-				Columns: []*schema.Column{TaskStepOutputsColumns[5]},
+				Columns: []*schema.Column{TaskStepOutputsColumns[6]},
 			},
 			{
 				Name:    "taskstepoutput_exec_id_timestamp",
 				Unique:  false,
-				Columns: []*schema.Column{TaskStepOutputsColumns[7], TaskStepOutputsColumns[5]},
+				Columns: []*schema.Column{TaskStepOutputsColumns[8], TaskStepOutputsColumns[6]},
+			},
+			{
+				Name:    "taskstepoutput_exec_id_line",
+				Unique:  true,
+				Columns: []*schema.Column{TaskStepOutputsColumns[8], TaskStepOutputsColumns[5]},
 			},
 		},
 	}
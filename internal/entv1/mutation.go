@@ -1102,6 +1102,17 @@ type StepMutation struct {
 	addtimeout             *int64
 	action                 *string
 	rule                   *string
+	run_when               *string
+	handler                *string
+	priority               *int
+	addpriority            *int
+	weight                 *int64
+	addweight              *int64
+	mutex                  *string
+	allow_failure          *bool
+	idempotent             *bool
+	matrix                 **schema.Matrix
+	foreach                **schema.Foreach
 	retry_policy           **schema.RetryPolicy
 	metadata               *map[string]interface{}
 	clearedFields          map[string]struct{}
@@ -1785,6 +1796,489 @@ func (m *StepMutation) ResetRule() {
 	delete(m.clearedFields, step.FieldRule)
 }
 
+// SetRunWhen sets the "run_when" field.
+func (m *StepMutation) SetRunWhen(s string) {
+	m.run_when = &s
+}
+
+// RunWhen returns the value of the "run_when" field in the mutation.
+func (m *StepMutation) RunWhen() (r string, exists bool) {
+	v := m.run_when
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRunWhen returns the old "run_when" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldRunWhen(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRunWhen is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRunWhen requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRunWhen: %w", err)
+	}
+	return oldValue.RunWhen, nil
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (m *StepMutation) ClearRunWhen() {
+	m.run_when = nil
+	m.clearedFields[step.FieldRunWhen] = struct{}{}
+}
+
+// RunWhenCleared returns if the "run_when" field was cleared in this mutation.
+func (m *StepMutation) RunWhenCleared() bool {
+	_, ok := m.clearedFields[step.FieldRunWhen]
+	return ok
+}
+
+// ResetRunWhen resets all changes to the "run_when" field.
+func (m *StepMutation) ResetRunWhen() {
+	m.run_when = nil
+	delete(m.clearedFields, step.FieldRunWhen)
+}
+
+// SetHandler sets the "handler" field.
+func (m *StepMutation) SetHandler(s string) {
+	m.handler = &s
+}
+
+// Handler returns the value of the "handler" field in the mutation.
+func (m *StepMutation) Handler() (r string, exists bool) {
+	v := m.handler
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHandler returns the old "handler" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldHandler(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHandler is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHandler requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHandler: %w", err)
+	}
+	return oldValue.Handler, nil
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (m *StepMutation) ClearHandler() {
+	m.handler = nil
+	m.clearedFields[step.FieldHandler] = struct{}{}
+}
+
+// HandlerCleared returns if the "handler" field was cleared in this mutation.
+func (m *StepMutation) HandlerCleared() bool {
+	_, ok := m.clearedFields[step.FieldHandler]
+	return ok
+}
+
+// ResetHandler resets all changes to the "handler" field.
+func (m *StepMutation) ResetHandler() {
+	m.handler = nil
+	delete(m.clearedFields, step.FieldHandler)
+}
+
+// SetPriority sets the "priority" field.
+func (m *StepMutation) SetPriority(i int) {
+	m.priority = &i
+	m.addpriority = nil
+}
+
+// Priority returns the value of the "priority" field in the mutation.
+func (m *StepMutation) Priority() (r int, exists bool) {
+	v := m.priority
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPriority returns the old "priority" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldPriority(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPriority is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPriority requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPriority: %w", err)
+	}
+	return oldValue.Priority, nil
+}
+
+// AddPriority adds i to the "priority" field.
+func (m *StepMutation) AddPriority(i int) {
+	if m.addpriority != nil {
+		*m.addpriority += i
+	} else {
+		m.addpriority = &i
+	}
+}
+
+// AddedPriority returns the value that was added to the "priority" field in this mutation.
+func (m *StepMutation) AddedPriority() (r int, exists bool) {
+	v := m.addpriority
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (m *StepMutation) ClearPriority() {
+	m.priority = nil
+	m.addpriority = nil
+	m.clearedFields[step.FieldPriority] = struct{}{}
+}
+
+// PriorityCleared returns if the "priority" field was cleared in this mutation.
+func (m *StepMutation) PriorityCleared() bool {
+	_, ok := m.clearedFields[step.FieldPriority]
+	return ok
+}
+
+// ResetPriority resets all changes to the "priority" field.
+func (m *StepMutation) ResetPriority() {
+	m.priority = nil
+	m.addpriority = nil
+	delete(m.clearedFields, step.FieldPriority)
+}
+
+// SetWeight sets the "weight" field.
+func (m *StepMutation) SetWeight(i int64) {
+	m.weight = &i
+	m.addweight = nil
+}
+
+// Weight returns the value of the "weight" field in the mutation.
+func (m *StepMutation) Weight() (r int64, exists bool) {
+	v := m.weight
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldWeight returns the old "weight" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldWeight(ctx context.Context) (v *int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldWeight is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldWeight requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldWeight: %w", err)
+	}
+	return oldValue.Weight, nil
+}
+
+// AddWeight adds i to the "weight" field.
+func (m *StepMutation) AddWeight(i int64) {
+	if m.addweight != nil {
+		*m.addweight += i
+	} else {
+		m.addweight = &i
+	}
+}
+
+// AddedWeight returns the value that was added to the "weight" field in this mutation.
+func (m *StepMutation) AddedWeight() (r int64, exists bool) {
+	v := m.addweight
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (m *StepMutation) ClearWeight() {
+	m.weight = nil
+	m.addweight = nil
+	m.clearedFields[step.FieldWeight] = struct{}{}
+}
+
+// WeightCleared returns if the "weight" field was cleared in this mutation.
+func (m *StepMutation) WeightCleared() bool {
+	_, ok := m.clearedFields[step.FieldWeight]
+	return ok
+}
+
+// ResetWeight resets all changes to the "weight" field.
+func (m *StepMutation) ResetWeight() {
+	m.weight = nil
+	m.addweight = nil
+	delete(m.clearedFields, step.FieldWeight)
+}
+
+// SetMutex sets the "mutex" field.
+func (m *StepMutation) SetMutex(s string) {
+	m.mutex = &s
+}
+
+// Mutex returns the value of the "mutex" field in the mutation.
+func (m *StepMutation) Mutex() (r string, exists bool) {
+	v := m.mutex
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMutex returns the old "mutex" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldMutex(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMutex is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMutex requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMutex: %w", err)
+	}
+	return oldValue.Mutex, nil
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (m *StepMutation) ClearMutex() {
+	m.mutex = nil
+	m.clearedFields[step.FieldMutex] = struct{}{}
+}
+
+// MutexCleared returns if the "mutex" field was cleared in this mutation.
+func (m *StepMutation) MutexCleared() bool {
+	_, ok := m.clearedFields[step.FieldMutex]
+	return ok
+}
+
+// ResetMutex resets all changes to the "mutex" field.
+func (m *StepMutation) ResetMutex() {
+	m.mutex = nil
+	delete(m.clearedFields, step.FieldMutex)
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (m *StepMutation) SetAllowFailure(b bool) {
+	m.allow_failure = &b
+}
+
+// AllowFailure returns the value of the "allow_failure" field in the mutation.
+func (m *StepMutation) AllowFailure() (r bool, exists bool) {
+	v := m.allow_failure
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAllowFailure returns the old "allow_failure" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldAllowFailure(ctx context.Context) (v *bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAllowFailure is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAllowFailure requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAllowFailure: %w", err)
+	}
+	return oldValue.AllowFailure, nil
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (m *StepMutation) ClearAllowFailure() {
+	m.allow_failure = nil
+	m.clearedFields[step.FieldAllowFailure] = struct{}{}
+}
+
+// AllowFailureCleared returns if the "allow_failure" field was cleared in this mutation.
+func (m *StepMutation) AllowFailureCleared() bool {
+	_, ok := m.clearedFields[step.FieldAllowFailure]
+	return ok
+}
+
+// ResetAllowFailure resets all changes to the "allow_failure" field.
+func (m *StepMutation) ResetAllowFailure() {
+	m.allow_failure = nil
+	delete(m.clearedFields, step.FieldAllowFailure)
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (m *StepMutation) SetIdempotent(b bool) {
+	m.idempotent = &b
+}
+
+// Idempotent returns the value of the "idempotent" field in the mutation.
+func (m *StepMutation) Idempotent() (r bool, exists bool) {
+	v := m.idempotent
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldIdempotent returns the old "idempotent" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldIdempotent(ctx context.Context) (v *bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldIdempotent is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldIdempotent requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldIdempotent: %w", err)
+	}
+	return oldValue.Idempotent, nil
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (m *StepMutation) ClearIdempotent() {
+	m.idempotent = nil
+	m.clearedFields[step.FieldIdempotent] = struct{}{}
+}
+
+// IdempotentCleared returns if the "idempotent" field was cleared in this mutation.
+func (m *StepMutation) IdempotentCleared() bool {
+	_, ok := m.clearedFields[step.FieldIdempotent]
+	return ok
+}
+
+// ResetIdempotent resets all changes to the "idempotent" field.
+func (m *StepMutation) ResetIdempotent() {
+	m.idempotent = nil
+	delete(m.clearedFields, step.FieldIdempotent)
+}
+
+// SetMatrix sets the "matrix" field.
+func (m *StepMutation) SetMatrix(s *schema.Matrix) {
+	m.matrix = &s
+}
+
+// Matrix returns the value of the "matrix" field in the mutation.
+func (m *StepMutation) Matrix() (r *schema.Matrix, exists bool) {
+	v := m.matrix
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMatrix returns the old "matrix" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldMatrix(ctx context.Context) (v *schema.Matrix, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMatrix is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMatrix requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMatrix: %w", err)
+	}
+	return oldValue.Matrix, nil
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (m *StepMutation) ClearMatrix() {
+	m.matrix = nil
+	m.clearedFields[step.FieldMatrix] = struct{}{}
+}
+
+// MatrixCleared returns if the "matrix" field was cleared in this mutation.
+func (m *StepMutation) MatrixCleared() bool {
+	_, ok := m.clearedFields[step.FieldMatrix]
+	return ok
+}
+
+// ResetMatrix resets all changes to the "matrix" field.
+func (m *StepMutation) ResetMatrix() {
+	m.matrix = nil
+	delete(m.clearedFields, step.FieldMatrix)
+}
+
+// SetForeach sets the "foreach" field.
+func (m *StepMutation) SetForeach(s *schema.Foreach) {
+	m.foreach = &s
+}
+
+// Foreach returns the value of the "foreach" field in the mutation.
+func (m *StepMutation) Foreach() (r *schema.Foreach, exists bool) {
+	v := m.foreach
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldForeach returns the old "foreach" field's value of the Step entity.
+// If the Step object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *StepMutation) OldForeach(ctx context.Context) (v *schema.Foreach, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldForeach is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldForeach requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldForeach: %w", err)
+	}
+	return oldValue.Foreach, nil
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (m *StepMutation) ClearForeach() {
+	m.foreach = nil
+	m.clearedFields[step.FieldForeach] = struct{}{}
+}
+
+// ForeachCleared returns if the "foreach" field was cleared in this mutation.
+func (m *StepMutation) ForeachCleared() bool {
+	_, ok := m.clearedFields[step.FieldForeach]
+	return ok
+}
+
+// ResetForeach resets all changes to the "foreach" field.
+func (m *StepMutation) ResetForeach() {
+	m.foreach = nil
+	delete(m.clearedFields, step.FieldForeach)
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (m *StepMutation) SetRetryPolicy(sp *schema.RetryPolicy) {
 	m.retry_policy = &sp
@@ -2349,7 +2843,7 @@ func (m *StepMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *StepMutation) Fields() []string {
-	fields := make([]string, 0, 13)
+	fields := make([]string, 0, 22)
 	if m.created_at != nil {
 		fields = append(fields, step.FieldCreatedAt)
 	}
@@ -2383,6 +2877,33 @@ func (m *StepMutation) Fields() []string {
 	if m.rule != nil {
 		fields = append(fields, step.FieldRule)
 	}
+	if m.run_when != nil {
+		fields = append(fields, step.FieldRunWhen)
+	}
+	if m.handler != nil {
+		fields = append(fields, step.FieldHandler)
+	}
+	if m.priority != nil {
+		fields = append(fields, step.FieldPriority)
+	}
+	if m.weight != nil {
+		fields = append(fields, step.FieldWeight)
+	}
+	if m.mutex != nil {
+		fields = append(fields, step.FieldMutex)
+	}
+	if m.allow_failure != nil {
+		fields = append(fields, step.FieldAllowFailure)
+	}
+	if m.idempotent != nil {
+		fields = append(fields, step.FieldIdempotent)
+	}
+	if m.matrix != nil {
+		fields = append(fields, step.FieldMatrix)
+	}
+	if m.foreach != nil {
+		fields = append(fields, step.FieldForeach)
+	}
 	if m.retry_policy != nil {
 		fields = append(fields, step.FieldRetryPolicy)
 	}
@@ -2419,6 +2940,24 @@ func (m *StepMutation) Field(name string) (ent.Value, bool) {
 		return m.Action()
 	case step.FieldRule:
 		return m.Rule()
+	case step.FieldRunWhen:
+		return m.RunWhen()
+	case step.FieldHandler:
+		return m.Handler()
+	case step.FieldPriority:
+		return m.Priority()
+	case step.FieldWeight:
+		return m.Weight()
+	case step.FieldMutex:
+		return m.Mutex()
+	case step.FieldAllowFailure:
+		return m.AllowFailure()
+	case step.FieldIdempotent:
+		return m.Idempotent()
+	case step.FieldMatrix:
+		return m.Matrix()
+	case step.FieldForeach:
+		return m.Foreach()
 	case step.FieldRetryPolicy:
 		return m.RetryPolicy()
 	case step.FieldMetadata:
@@ -2454,6 +2993,24 @@ func (m *StepMutation) OldField(ctx context.Context, name string) (ent.Value, er
 		return m.OldAction(ctx)
 	case step.FieldRule:
 		return m.OldRule(ctx)
+	case step.FieldRunWhen:
+		return m.OldRunWhen(ctx)
+	case step.FieldHandler:
+		return m.OldHandler(ctx)
+	case step.FieldPriority:
+		return m.OldPriority(ctx)
+	case step.FieldWeight:
+		return m.OldWeight(ctx)
+	case step.FieldMutex:
+		return m.OldMutex(ctx)
+	case step.FieldAllowFailure:
+		return m.OldAllowFailure(ctx)
+	case step.FieldIdempotent:
+		return m.OldIdempotent(ctx)
+	case step.FieldMatrix:
+		return m.OldMatrix(ctx)
+	case step.FieldForeach:
+		return m.OldForeach(ctx)
 	case step.FieldRetryPolicy:
 		return m.OldRetryPolicy(ctx)
 	case step.FieldMetadata:
@@ -2514,35 +3071,98 @@ func (m *StepMutation) SetField(name string, value ent.Value) error {
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetKind(v)
+		m.SetKind(v)
+		return nil
+	case step.FieldContent:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetContent(v)
+		return nil
+	case step.FieldTimeout:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTimeout(v)
+		return nil
+	case step.FieldAction:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAction(v)
+		return nil
+	case step.FieldRule:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRule(v)
+		return nil
+	case step.FieldRunWhen:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRunWhen(v)
+		return nil
+	case step.FieldHandler:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHandler(v)
+		return nil
+	case step.FieldPriority:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPriority(v)
+		return nil
+	case step.FieldWeight:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetWeight(v)
+		return nil
+	case step.FieldMutex:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMutex(v)
 		return nil
-	case step.FieldContent:
-		v, ok := value.(string)
+	case step.FieldAllowFailure:
+		v, ok := value.(bool)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetContent(v)
+		m.SetAllowFailure(v)
 		return nil
-	case step.FieldTimeout:
-		v, ok := value.(int64)
+	case step.FieldIdempotent:
+		v, ok := value.(bool)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetTimeout(v)
+		m.SetIdempotent(v)
 		return nil
-	case step.FieldAction:
-		v, ok := value.(string)
+	case step.FieldMatrix:
+		v, ok := value.(*schema.Matrix)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetAction(v)
+		m.SetMatrix(v)
 		return nil
-	case step.FieldRule:
-		v, ok := value.(string)
+	case step.FieldForeach:
+		v, ok := value.(*schema.Foreach)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetRule(v)
+		m.SetForeach(v)
 		return nil
 	case step.FieldRetryPolicy:
 		v, ok := value.(*schema.RetryPolicy)
@@ -2569,6 +3189,12 @@ func (m *StepMutation) AddedFields() []string {
 	if m.addtimeout != nil {
 		fields = append(fields, step.FieldTimeout)
 	}
+	if m.addpriority != nil {
+		fields = append(fields, step.FieldPriority)
+	}
+	if m.addweight != nil {
+		fields = append(fields, step.FieldWeight)
+	}
 	return fields
 }
 
@@ -2579,6 +3205,10 @@ func (m *StepMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
 	case step.FieldTimeout:
 		return m.AddedTimeout()
+	case step.FieldPriority:
+		return m.AddedPriority()
+	case step.FieldWeight:
+		return m.AddedWeight()
 	}
 	return nil, false
 }
@@ -2595,6 +3225,20 @@ func (m *StepMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddTimeout(v)
 		return nil
+	case step.FieldPriority:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddPriority(v)
+		return nil
+	case step.FieldWeight:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddWeight(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Step numeric field %s", name)
 }
@@ -2633,6 +3277,33 @@ func (m *StepMutation) ClearedFields() []string {
 	if m.FieldCleared(step.FieldRule) {
 		fields = append(fields, step.FieldRule)
 	}
+	if m.FieldCleared(step.FieldRunWhen) {
+		fields = append(fields, step.FieldRunWhen)
+	}
+	if m.FieldCleared(step.FieldHandler) {
+		fields = append(fields, step.FieldHandler)
+	}
+	if m.FieldCleared(step.FieldPriority) {
+		fields = append(fields, step.FieldPriority)
+	}
+	if m.FieldCleared(step.FieldWeight) {
+		fields = append(fields, step.FieldWeight)
+	}
+	if m.FieldCleared(step.FieldMutex) {
+		fields = append(fields, step.FieldMutex)
+	}
+	if m.FieldCleared(step.FieldAllowFailure) {
+		fields = append(fields, step.FieldAllowFailure)
+	}
+	if m.FieldCleared(step.FieldIdempotent) {
+		fields = append(fields, step.FieldIdempotent)
+	}
+	if m.FieldCleared(step.FieldMatrix) {
+		fields = append(fields, step.FieldMatrix)
+	}
+	if m.FieldCleared(step.FieldForeach) {
+		fields = append(fields, step.FieldForeach)
+	}
 	if m.FieldCleared(step.FieldRetryPolicy) {
 		fields = append(fields, step.FieldRetryPolicy)
 	}
@@ -2683,6 +3354,33 @@ func (m *StepMutation) ClearField(name string) error {
 	case step.FieldRule:
 		m.ClearRule()
 		return nil
+	case step.FieldRunWhen:
+		m.ClearRunWhen()
+		return nil
+	case step.FieldHandler:
+		m.ClearHandler()
+		return nil
+	case step.FieldPriority:
+		m.ClearPriority()
+		return nil
+	case step.FieldWeight:
+		m.ClearWeight()
+		return nil
+	case step.FieldMutex:
+		m.ClearMutex()
+		return nil
+	case step.FieldAllowFailure:
+		m.ClearAllowFailure()
+		return nil
+	case step.FieldIdempotent:
+		m.ClearIdempotent()
+		return nil
+	case step.FieldMatrix:
+		m.ClearMatrix()
+		return nil
+	case step.FieldForeach:
+		m.ClearForeach()
+		return nil
 	case step.FieldRetryPolicy:
 		m.ClearRetryPolicy()
 		return nil
@@ -2730,6 +3428,33 @@ func (m *StepMutation) ResetField(name string) error {
 	case step.FieldRule:
 		m.ResetRule()
 		return nil
+	case step.FieldRunWhen:
+		m.ResetRunWhen()
+		return nil
+	case step.FieldHandler:
+		m.ResetHandler()
+		return nil
+	case step.FieldPriority:
+		m.ResetPriority()
+		return nil
+	case step.FieldWeight:
+		m.ResetWeight()
+		return nil
+	case step.FieldMutex:
+		m.ResetMutex()
+		return nil
+	case step.FieldAllowFailure:
+		m.ResetAllowFailure()
+		return nil
+	case step.FieldIdempotent:
+		m.ResetIdempotent()
+		return nil
+	case step.FieldMatrix:
+		m.ResetMatrix()
+		return nil
+	case step.FieldForeach:
+		m.ResetForeach()
+		return nil
 	case step.FieldRetryPolicy:
 		m.ResetRetryPolicy()
 		return nil
@@ -4610,6 +5335,10 @@ type TaskMutation struct {
 	timeout            *int64
 	addtimeout         *int64
 	retry_policy       **schema.RetryPolicy
+	fail_fast          *bool
+	concurrency_group  *string
+	concurrency_policy *string
+	metadata           *map[string]interface{}
 	is_tpl             *bool
 	clearedFields      map[string]struct{}
 	steps              map[uint64]struct{}
@@ -5525,6 +6254,202 @@ func (m *TaskMutation) ResetRetryPolicy() {
 	delete(m.clearedFields, task.FieldRetryPolicy)
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (m *TaskMutation) SetFailFast(b bool) {
+	m.fail_fast = &b
+}
+
+// FailFast returns the value of the "fail_fast" field in the mutation.
+func (m *TaskMutation) FailFast() (r bool, exists bool) {
+	v := m.fail_fast
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldFailFast returns the old "fail_fast" field's value of the Task entity.
+// If the Task object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TaskMutation) OldFailFast(ctx context.Context) (v *bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldFailFast is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldFailFast requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldFailFast: %w", err)
+	}
+	return oldValue.FailFast, nil
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (m *TaskMutation) ClearFailFast() {
+	m.fail_fast = nil
+	m.clearedFields[task.FieldFailFast] = struct{}{}
+}
+
+// FailFastCleared returns if the "fail_fast" field was cleared in this mutation.
+func (m *TaskMutation) FailFastCleared() bool {
+	_, ok := m.clearedFields[task.FieldFailFast]
+	return ok
+}
+
+// ResetFailFast resets all changes to the "fail_fast" field.
+func (m *TaskMutation) ResetFailFast() {
+	m.fail_fast = nil
+	delete(m.clearedFields, task.FieldFailFast)
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (m *TaskMutation) SetConcurrencyGroup(s string) {
+	m.concurrency_group = &s
+}
+
+// ConcurrencyGroup returns the value of the "concurrency_group" field in the mutation.
+func (m *TaskMutation) ConcurrencyGroup() (r string, exists bool) {
+	v := m.concurrency_group
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldConcurrencyGroup returns the old "concurrency_group" field's value of the Task entity.
+// If the Task object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TaskMutation) OldConcurrencyGroup(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldConcurrencyGroup is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldConcurrencyGroup requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldConcurrencyGroup: %w", err)
+	}
+	return oldValue.ConcurrencyGroup, nil
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (m *TaskMutation) ClearConcurrencyGroup() {
+	m.concurrency_group = nil
+	m.clearedFields[task.FieldConcurrencyGroup] = struct{}{}
+}
+
+// ConcurrencyGroupCleared returns if the "concurrency_group" field was cleared in this mutation.
+func (m *TaskMutation) ConcurrencyGroupCleared() bool {
+	_, ok := m.clearedFields[task.FieldConcurrencyGroup]
+	return ok
+}
+
+// ResetConcurrencyGroup resets all changes to the "concurrency_group" field.
+func (m *TaskMutation) ResetConcurrencyGroup() {
+	m.concurrency_group = nil
+	delete(m.clearedFields, task.FieldConcurrencyGroup)
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (m *TaskMutation) SetConcurrencyPolicy(s string) {
+	m.concurrency_policy = &s
+}
+
+// ConcurrencyPolicy returns the value of the "concurrency_policy" field in the mutation.
+func (m *TaskMutation) ConcurrencyPolicy() (r string, exists bool) {
+	v := m.concurrency_policy
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldConcurrencyPolicy returns the old "concurrency_policy" field's value of the Task entity.
+// If the Task object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TaskMutation) OldConcurrencyPolicy(ctx context.Context) (v *string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldConcurrencyPolicy is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldConcurrencyPolicy requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldConcurrencyPolicy: %w", err)
+	}
+	return oldValue.ConcurrencyPolicy, nil
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (m *TaskMutation) ClearConcurrencyPolicy() {
+	m.concurrency_policy = nil
+	m.clearedFields[task.FieldConcurrencyPolicy] = struct{}{}
+}
+
+// ConcurrencyPolicyCleared returns if the "concurrency_policy" field was cleared in this mutation.
+func (m *TaskMutation) ConcurrencyPolicyCleared() bool {
+	_, ok := m.clearedFields[task.FieldConcurrencyPolicy]
+	return ok
+}
+
+// ResetConcurrencyPolicy resets all changes to the "concurrency_policy" field.
+func (m *TaskMutation) ResetConcurrencyPolicy() {
+	m.concurrency_policy = nil
+	delete(m.clearedFields, task.FieldConcurrencyPolicy)
+}
+
+// SetMetadata sets the "metadata" field.
+func (m *TaskMutation) SetMetadata(value map[string]interface{}) {
+	m.metadata = &value
+}
+
+// Metadata returns the value of the "metadata" field in the mutation.
+func (m *TaskMutation) Metadata() (r map[string]interface{}, exists bool) {
+	v := m.metadata
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMetadata returns the old "metadata" field's value of the Task entity.
+// If the Task object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TaskMutation) OldMetadata(ctx context.Context) (v map[string]interface{}, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMetadata is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMetadata requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMetadata: %w", err)
+	}
+	return oldValue.Metadata, nil
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (m *TaskMutation) ClearMetadata() {
+	m.metadata = nil
+	m.clearedFields[task.FieldMetadata] = struct{}{}
+}
+
+// MetadataCleared returns if the "metadata" field was cleared in this mutation.
+func (m *TaskMutation) MetadataCleared() bool {
+	_, ok := m.clearedFields[task.FieldMetadata]
+	return ok
+}
+
+// ResetMetadata resets all changes to the "metadata" field.
+func (m *TaskMutation) ResetMetadata() {
+	m.metadata = nil
+	delete(m.clearedFields, task.FieldMetadata)
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (m *TaskMutation) SetIsTpl(b bool) {
 	m.is_tpl = &b
@@ -5824,7 +6749,7 @@ func (m *TaskMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *TaskMutation) Fields() []string {
-	fields := make([]string, 0, 17)
+	fields := make([]string, 0, 21)
 	if m.created_at != nil {
 		fields = append(fields, task.FieldCreatedAt)
 	}
@@ -5873,6 +6798,18 @@ func (m *TaskMutation) Fields() []string {
 	if m.retry_policy != nil {
 		fields = append(fields, task.FieldRetryPolicy)
 	}
+	if m.fail_fast != nil {
+		fields = append(fields, task.FieldFailFast)
+	}
+	if m.concurrency_group != nil {
+		fields = append(fields, task.FieldConcurrencyGroup)
+	}
+	if m.concurrency_policy != nil {
+		fields = append(fields, task.FieldConcurrencyPolicy)
+	}
+	if m.metadata != nil {
+		fields = append(fields, task.FieldMetadata)
+	}
 	if m.is_tpl != nil {
 		fields = append(fields, task.FieldIsTpl)
 	}
@@ -5916,6 +6853,14 @@ func (m *TaskMutation) Field(name string) (ent.Value, bool) {
 		return m.Timeout()
 	case task.FieldRetryPolicy:
 		return m.RetryPolicy()
+	case task.FieldFailFast:
+		return m.FailFast()
+	case task.FieldConcurrencyGroup:
+		return m.ConcurrencyGroup()
+	case task.FieldConcurrencyPolicy:
+		return m.ConcurrencyPolicy()
+	case task.FieldMetadata:
+		return m.Metadata()
 	case task.FieldIsTpl:
 		return m.IsTpl()
 	}
@@ -5959,6 +6904,14 @@ func (m *TaskMutation) OldField(ctx context.Context, name string) (ent.Value, er
 		return m.OldTimeout(ctx)
 	case task.FieldRetryPolicy:
 		return m.OldRetryPolicy(ctx)
+	case task.FieldFailFast:
+		return m.OldFailFast(ctx)
+	case task.FieldConcurrencyGroup:
+		return m.OldConcurrencyGroup(ctx)
+	case task.FieldConcurrencyPolicy:
+		return m.OldConcurrencyPolicy(ctx)
+	case task.FieldMetadata:
+		return m.OldMetadata(ctx)
 	case task.FieldIsTpl:
 		return m.OldIsTpl(ctx)
 	}
@@ -6082,6 +7035,34 @@ func (m *TaskMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetRetryPolicy(v)
 		return nil
+	case task.FieldFailFast:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetFailFast(v)
+		return nil
+	case task.FieldConcurrencyGroup:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetConcurrencyGroup(v)
+		return nil
+	case task.FieldConcurrencyPolicy:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetConcurrencyPolicy(v)
+		return nil
+	case task.FieldMetadata:
+		v, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMetadata(v)
+		return nil
 	case task.FieldIsTpl:
 		v, ok := value.(bool)
 		if !ok {
@@ -6179,6 +7160,18 @@ func (m *TaskMutation) ClearedFields() []string {
 	if m.FieldCleared(task.FieldRetryPolicy) {
 		fields = append(fields, task.FieldRetryPolicy)
 	}
+	if m.FieldCleared(task.FieldFailFast) {
+		fields = append(fields, task.FieldFailFast)
+	}
+	if m.FieldCleared(task.FieldConcurrencyGroup) {
+		fields = append(fields, task.FieldConcurrencyGroup)
+	}
+	if m.FieldCleared(task.FieldConcurrencyPolicy) {
+		fields = append(fields, task.FieldConcurrencyPolicy)
+	}
+	if m.FieldCleared(task.FieldMetadata) {
+		fields = append(fields, task.FieldMetadata)
+	}
 	if m.FieldCleared(task.FieldIsTpl) {
 		fields = append(fields, task.FieldIsTpl)
 	}
@@ -6241,6 +7234,18 @@ func (m *TaskMutation) ClearField(name string) error {
 	case task.FieldRetryPolicy:
 		m.ClearRetryPolicy()
 		return nil
+	case task.FieldFailFast:
+		m.ClearFailFast()
+		return nil
+	case task.FieldConcurrencyGroup:
+		m.ClearConcurrencyGroup()
+		return nil
+	case task.FieldConcurrencyPolicy:
+		m.ClearConcurrencyPolicy()
+		return nil
+	case task.FieldMetadata:
+		m.ClearMetadata()
+		return nil
 	case task.FieldIsTpl:
 		m.ClearIsTpl()
 		return nil
@@ -6300,6 +7305,18 @@ func (m *TaskMutation) ResetField(name string) error {
 	case task.FieldRetryPolicy:
 		m.ResetRetryPolicy()
 		return nil
+	case task.FieldFailFast:
+		m.ResetFailFast()
+		return nil
+	case task.FieldConcurrencyGroup:
+		m.ResetConcurrencyGroup()
+		return nil
+	case task.FieldConcurrencyPolicy:
+		m.ResetConcurrencyPolicy()
+		return nil
+	case task.FieldMetadata:
+		m.ResetMetadata()
+		return nil
 	case task.FieldIsTpl:
 		m.ResetIsTpl()
 		return nil
@@ -8784,6 +9801,8 @@ type TaskStepOutputMutation struct {
 	updated_at       *time.Time
 	created_by       *string
 	updated_by       *string
+	line             *int64
+	addline          *int64
 	timestamp        *int64
 	addtimestamp     *int64
 	content          *string
@@ -9131,6 +10150,62 @@ func (m *TaskStepOutputMutation) ResetExecID() {
 	m.task_step = nil
 }
 
+// SetLine sets the "line" field.
+func (m *TaskStepOutputMutation) SetLine(i int64) {
+	m.line = &i
+	m.addline = nil
+}
+
+// Line returns the value of the "line" field in the mutation.
+func (m *TaskStepOutputMutation) Line() (r int64, exists bool) {
+	v := m.line
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLine returns the old "line" field's value of the TaskStepOutput entity.
+// If the TaskStepOutput object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *TaskStepOutputMutation) OldLine(ctx context.Context) (v int64, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLine is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLine requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLine: %w", err)
+	}
+	return oldValue.Line, nil
+}
+
+// AddLine adds i to the "line" field.
+func (m *TaskStepOutputMutation) AddLine(i int64) {
+	if m.addline != nil {
+		*m.addline += i
+	} else {
+		m.addline = &i
+	}
+}
+
+// AddedLine returns the value that was added to the "line" field in this mutation.
+func (m *TaskStepOutputMutation) AddedLine() (r int64, exists bool) {
+	v := m.addline
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetLine resets all changes to the "line" field.
+func (m *TaskStepOutputMutation) ResetLine() {
+	m.line = nil
+	m.addline = nil
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (m *TaskStepOutputMutation) SetTimestamp(i int64) {
 	m.timestamp = &i
@@ -9324,7 +10399,7 @@ func (m *TaskStepOutputMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *TaskStepOutputMutation) Fields() []string {
-	fields := make([]string, 0, 7)
+	fields := make([]string, 0, 8)
 	if m.created_at != nil {
 		fields = append(fields, taskstepoutput.FieldCreatedAt)
 	}
@@ -9340,6 +10415,9 @@ func (m *TaskStepOutputMutation) Fields() []string {
 	if m.task_step != nil {
 		fields = append(fields, taskstepoutput.FieldExecID)
 	}
+	if m.line != nil {
+		fields = append(fields, taskstepoutput.FieldLine)
+	}
 	if m.timestamp != nil {
 		fields = append(fields, taskstepoutput.FieldTimestamp)
 	}
@@ -9364,6 +10442,8 @@ func (m *TaskStepOutputMutation) Field(name string) (ent.Value, bool) {
 		return m.UpdatedBy()
 	case taskstepoutput.FieldExecID:
 		return m.ExecID()
+	case taskstepoutput.FieldLine:
+		return m.Line()
 	case taskstepoutput.FieldTimestamp:
 		return m.Timestamp()
 	case taskstepoutput.FieldContent:
@@ -9387,6 +10467,8 @@ func (m *TaskStepOutputMutation) OldField(ctx context.Context, name string) (ent
 		return m.OldUpdatedBy(ctx)
 	case taskstepoutput.FieldExecID:
 		return m.OldExecID(ctx)
+	case taskstepoutput.FieldLine:
+		return m.OldLine(ctx)
 	case taskstepoutput.FieldTimestamp:
 		return m.OldTimestamp(ctx)
 	case taskstepoutput.FieldContent:
@@ -9435,6 +10517,13 @@ func (m *TaskStepOutputMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetExecID(v)
 		return nil
+	case taskstepoutput.FieldLine:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLine(v)
+		return nil
 	case taskstepoutput.FieldTimestamp:
 		v, ok := value.(int64)
 		if !ok {
@@ -9457,6 +10546,9 @@ func (m *TaskStepOutputMutation) SetField(name string, value ent.Value) error {
 // this mutation.
 func (m *TaskStepOutputMutation) AddedFields() []string {
 	var fields []string
+	if m.addline != nil {
+		fields = append(fields, taskstepoutput.FieldLine)
+	}
 	if m.addtimestamp != nil {
 		fields = append(fields, taskstepoutput.FieldTimestamp)
 	}
@@ -9468,6 +10560,8 @@ func (m *TaskStepOutputMutation) AddedFields() []string {
 // was not set, or was not defined in the schema.
 func (m *TaskStepOutputMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
+	case taskstepoutput.FieldLine:
+		return m.AddedLine()
 	case taskstepoutput.FieldTimestamp:
 		return m.AddedTimestamp()
 	}
@@ -9479,6 +10573,13 @@ func (m *TaskStepOutputMutation) AddedField(name string) (ent.Value, bool) {
 // type.
 func (m *TaskStepOutputMutation) AddField(name string, value ent.Value) error {
 	switch name {
+	case taskstepoutput.FieldLine:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddLine(v)
+		return nil
 	case taskstepoutput.FieldTimestamp:
 		v, ok := value.(int64)
 		if !ok {
@@ -9567,6 +10668,9 @@ func (m *TaskStepOutputMutation) ResetField(name string) error {
 	case taskstepoutput.FieldExecID:
 		m.ResetExecID()
 		return nil
+	case taskstepoutput.FieldLine:
+		m.ResetLine()
+		return nil
 	case taskstepoutput.FieldTimestamp:
 		m.ResetTimestamp()
 		return nil
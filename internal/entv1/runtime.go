@@ -62,8 +62,24 @@ func init() {
 	stepDescTimeout := stepFields[4].Descriptor()
 	// step.DefaultTimeout holds the default value on creation for the timeout field.
 	step.DefaultTimeout = stepDescTimeout.Default.(int64)
+	// stepDescPriority is the schema descriptor for priority field.
+	stepDescPriority := stepFields[9].Descriptor()
+	// step.DefaultPriority holds the default value on creation for the priority field.
+	step.DefaultPriority = stepDescPriority.Default.(int)
+	// stepDescWeight is the schema descriptor for weight field.
+	stepDescWeight := stepFields[10].Descriptor()
+	// step.DefaultWeight holds the default value on creation for the weight field.
+	step.DefaultWeight = stepDescWeight.Default.(int64)
+	// stepDescAllowFailure is the schema descriptor for allow_failure field.
+	stepDescAllowFailure := stepFields[12].Descriptor()
+	// step.DefaultAllowFailure holds the default value on creation for the allow_failure field.
+	step.DefaultAllowFailure = stepDescAllowFailure.Default.(bool)
+	// stepDescIdempotent is the schema descriptor for idempotent field.
+	stepDescIdempotent := stepFields[13].Descriptor()
+	// step.DefaultIdempotent holds the default value on creation for the idempotent field.
+	step.DefaultIdempotent = stepDescIdempotent.Default.(bool)
 	// stepDescRetryPolicy is the schema descriptor for retry_policy field.
-	stepDescRetryPolicy := stepFields[7].Descriptor()
+	stepDescRetryPolicy := stepFields[16].Descriptor()
 	// step.DefaultRetryPolicy holds the default value on creation for the retry_policy field.
 	step.DefaultRetryPolicy = stepDescRetryPolicy.Default.(*schema.RetryPolicy)
 	stepdependMixin := schema.StepDepend{}.Mixin()
@@ -129,8 +145,12 @@ func init() {
 	taskDescRetryPolicy := taskFields[5].Descriptor()
 	// task.DefaultRetryPolicy holds the default value on creation for the retry_policy field.
 	task.DefaultRetryPolicy = taskDescRetryPolicy.Default.(*schema.RetryPolicy)
+	// taskDescFailFast is the schema descriptor for fail_fast field.
+	taskDescFailFast := taskFields[6].Descriptor()
+	// task.DefaultFailFast holds the default value on creation for the fail_fast field.
+	task.DefaultFailFast = taskDescFailFast.Default.(bool)
 	// taskDescIsTpl is the schema descriptor for is_tpl field.
-	taskDescIsTpl := taskFields[6].Descriptor()
+	taskDescIsTpl := taskFields[10].Descriptor()
 	// task.DefaultIsTpl holds the default value on creation for the is_tpl field.
 	task.DefaultIsTpl = taskDescIsTpl.Default.(bool)
 	taskparamMixin := schema.TaskParam{}.Mixin()
@@ -192,8 +212,12 @@ func init() {
 	taskstepoutput.DefaultUpdatedAt = taskstepoutputDescUpdatedAt.Default.(func() time.Time)
 	// taskstepoutput.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	taskstepoutput.UpdateDefaultUpdatedAt = taskstepoutputDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// taskstepoutputDescLine is the schema descriptor for line field.
+	taskstepoutputDescLine := taskstepoutputFields[1].Descriptor()
+	// taskstepoutput.DefaultLine holds the default value on creation for the line field.
+	taskstepoutput.DefaultLine = taskstepoutputDescLine.Default.(int64)
 	// taskstepoutputDescTimestamp is the schema descriptor for timestamp field.
-	taskstepoutputDescTimestamp := taskstepoutputFields[1].Descriptor()
+	taskstepoutputDescTimestamp := taskstepoutputFields[2].Descriptor()
 	// taskstepoutput.DefaultTimestamp holds the default value on creation for the timestamp field.
 	taskstepoutput.DefaultTimestamp = taskstepoutputDescTimestamp.Default.(func() int64)
 }
@@ -59,6 +59,8 @@ func (StatusMixin) Fields() []ent.Field {
 				Pending,
 				Paused,
 				Skipped,
+				Waiting,
+				Queued,
 			).
 			Optional().
 			Nillable().
@@ -73,6 +75,8 @@ func (StatusMixin) Fields() []ent.Field {
 				Pending,
 				Paused,
 				Skipped,
+				Waiting,
+				Queued,
 			).
 			Optional().
 			Nillable().
@@ -92,6 +96,31 @@ type RetryPolicy struct {
 	MaxInterval time.Duration `json:"maxInterval" yaml:"maxInterval" description:"最大间隔时间"`
 	MaxAttempts int           `json:"maxAttempts" yaml:"maxAttempts" description:"最大尝试次数"`
 	Multiplier  float64       `json:"multiplier" yaml:"multiplier" description:"乘数"`
+	Jitter      string        `json:"jitter,omitempty" yaml:"jitter,omitempty" description:"随机抖动, full或equal"`
+	RetryOn     *RetryOn      `json:"retryOn,omitempty" yaml:"retryOn,omitempty" description:"重试条件, 满足任一条件才重试"`
+	RetryIf     string        `json:"retryIf,omitempty" yaml:"retryIf,omitempty" description:"重试条件表达式"`
+}
+
+type RetryOn struct {
+	ExitCodes []int64 `json:"exitCodes,omitempty" yaml:"exitCodes,omitempty" description:"退出码"`
+	Timeout   bool    `json:"timeout,omitempty" yaml:"timeout,omitempty" description:"超时"`
+	Pattern   string  `json:"pattern,omitempty" yaml:"pattern,omitempty" description:"匹配错误信息或最后几行日志的正则"`
+	LogLines  int     `json:"logLines,omitempty" yaml:"logLines,omitempty" description:"正则匹配的日志行数"`
+}
+
+type Matrix struct {
+	Group       string              `json:"group,omitempty" yaml:"group,omitempty" description:"所属矩阵步骤"`
+	Axes        map[string][]string `json:"axes,omitempty" yaml:"axes,omitempty" description:"参数轴"`
+	Values      map[string]string   `json:"values,omitempty" yaml:"values,omitempty" description:"当前实例参数"`
+	MaxParallel int                 `json:"maxParallel,omitempty" yaml:"maxParallel,omitempty" description:"最大并行数"`
+}
+
+type Foreach struct {
+	From        string `json:"from,omitempty" yaml:"from,omitempty" description:"上游步骤的输出, 格式为<步骤>.<输出>"`
+	MaxParallel int    `json:"maxParallel,omitempty" yaml:"maxParallel,omitempty" description:"最大并行数"`
+	Parent      string `json:"parent,omitempty" yaml:"parent,omitempty" description:"生成当前步骤的foreach步骤"`
+	Index       int    `json:"index,omitempty" yaml:"index,omitempty" description:"元素序号"`
+	Item        string `json:"item,omitempty" yaml:"item,omitempty" description:"元素"`
 }
 
 const (
@@ -102,4 +131,6 @@ const (
 	Pending = "pending"
 	Paused  = "paused"
 	Skipped = "skipped"
+	Waiting = "waiting"
+	Queued  = "queued"
 )
@@ -51,6 +51,44 @@ func (Step) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("规则"),
+		field.String("run_when").
+			Optional().
+			Nillable().
+			Comment("执行条件"),
+		field.String("handler").
+			Optional().
+			Nillable().
+			Comment("任务处理步骤类型"),
+		field.Int("priority").
+			Optional().
+			Nillable().
+			Default(0).
+			Comment("优先级"),
+		field.Int64("weight").
+			Optional().
+			Nillable().
+			Default(1).
+			Comment("占用节点预算的槽位"),
+		field.String("mutex").
+			Optional().
+			Nillable().
+			Comment("节点互斥组"),
+		field.Bool("allow_failure").
+			Optional().
+			Nillable().
+			Default(false).
+			Comment("允许失败"),
+		field.Bool("idempotent").
+			Optional().
+			Nillable().
+			Default(false).
+			Comment("幂等, 中断后可重新执行"),
+		field.JSON("matrix", &Matrix{}).
+			Optional().
+			Comment("矩阵"),
+		field.JSON("foreach", &Foreach{}).
+			Optional().
+			Comment("按上游输出动态生成步骤"),
 		field.JSON("retry_policy", &RetryPolicy{}).
 			Default(&RetryPolicy{
 				Interval:    1000,
@@ -54,6 +54,22 @@ func (Task) Fields() []ent.Field {
 			}).
 			Optional().
 			Comment("重试策略"),
+		field.Bool("fail_fast").
+			Optional().
+			Nillable().
+			Default(false).
+			Comment("快速失败"),
+		field.String("concurrency_group").
+			Optional().
+			Nillable().
+			Comment("并发组"),
+		field.String("concurrency_policy").
+			Optional().
+			Nillable().
+			Comment("并发策略"),
+		field.JSON("metadata", map[string]any{}).
+			Optional().
+			Comment("元数据"),
 		field.Bool("is_tpl").
 			Optional().
 			Nillable().
@@ -86,5 +102,6 @@ func (Task) Indexes() []ent.Index {
 		index.Fields("start_time"),
 		index.Fields("end_time"),
 		index.Fields("state", "start_time"),
+		index.Fields("concurrency_group", "state"),
 	}
 }
@@ -26,6 +26,9 @@ func (TaskStepOutput) Fields() []ent.Field {
 	return []ent.Field{
 		field.Uint64("exec_id").
 			Comment("步骤ID"),
+		field.Int64("line").
+			Default(0).
+			Comment("行号"),
 		field.Int64("timestamp").
 			DefaultFunc(func() int64 {
 				return time.Now().UnixNano()
@@ -57,5 +60,6 @@ func (TaskStepOutput) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("timestamp"),
 		index.Fields("exec_id", "timestamp"),
+		index.Fields("exec_id", "line").Unique(),
 	}
 }
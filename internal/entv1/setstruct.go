@@ -54,6 +54,29 @@ func (_c *StepCreate) SetStep(input *Step) *StepCreate {
 	if input.Rule != nil {
 		_c.SetRule(*input.Rule)
 	}
+	if input.RunWhen != nil {
+		_c.SetRunWhen(*input.RunWhen)
+	}
+	if input.Handler != nil {
+		_c.SetHandler(*input.Handler)
+	}
+	if input.Priority != nil {
+		_c.SetPriority(*input.Priority)
+	}
+	if input.Weight != nil {
+		_c.SetWeight(*input.Weight)
+	}
+	if input.Mutex != nil {
+		_c.SetMutex(*input.Mutex)
+	}
+	if input.AllowFailure != nil {
+		_c.SetAllowFailure(*input.AllowFailure)
+	}
+	if input.Idempotent != nil {
+		_c.SetIdempotent(*input.Idempotent)
+	}
+	_c.SetMatrix(input.Matrix)
+	_c.SetForeach(input.Foreach)
 	_c.SetRetryPolicy(input.RetryPolicy)
 	_c.SetMetadata(input.Metadata)
 	return _c
@@ -140,6 +163,16 @@ func (_c *TaskCreate) SetTask(input *Task) *TaskCreate {
 		_c.SetTimeout(*input.Timeout)
 	}
 	_c.SetRetryPolicy(input.RetryPolicy)
+	if input.FailFast != nil {
+		_c.SetFailFast(*input.FailFast)
+	}
+	if input.ConcurrencyGroup != nil {
+		_c.SetConcurrencyGroup(*input.ConcurrencyGroup)
+	}
+	if input.ConcurrencyPolicy != nil {
+		_c.SetConcurrencyPolicy(*input.ConcurrencyPolicy)
+	}
+	_c.SetMetadata(input.Metadata)
 	if input.IsTpl != nil {
 		_c.SetIsTpl(*input.IsTpl)
 	}
@@ -220,6 +253,7 @@ func (_c *TaskStepOutputCreate) SetTaskStepOutput(input *TaskStepOutput) *TaskSt
 		_c.SetUpdatedBy(*input.UpdatedBy)
 	}
 	_c.SetExecID(input.ExecID)
+	_c.SetLine(input.Line)
 	if input.Timestamp != nil {
 		_c.SetTimestamp(*input.Timestamp)
 	}
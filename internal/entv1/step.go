@@ -41,6 +41,24 @@ type Step struct {
 	Action *string `json:"action,omitempty"`
 	// 规则
 	Rule *string `json:"rule,omitempty"`
+	// 执行条件
+	RunWhen *string `json:"run_when,omitempty"`
+	// 任务处理步骤类型
+	Handler *string `json:"handler,omitempty"`
+	// 优先级
+	Priority *int `json:"priority,omitempty"`
+	// 占用节点预算的槽位
+	Weight *int64 `json:"weight,omitempty"`
+	// 节点互斥组
+	Mutex *string `json:"mutex,omitempty"`
+	// 允许失败
+	AllowFailure *bool `json:"allow_failure,omitempty"`
+	// 幂等, 中断后可重新执行
+	Idempotent *bool `json:"idempotent,omitempty"`
+	// 矩阵
+	Matrix *schema.Matrix `json:"matrix,omitempty"`
+	// 按上游输出动态生成步骤
+	Foreach *schema.Foreach `json:"foreach,omitempty"`
 	// 重试策略
 	RetryPolicy *schema.RetryPolicy `json:"retry_policy,omitempty"`
 	// 元数据
@@ -151,11 +169,13 @@ func (*Step) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case step.FieldRetryPolicy, step.FieldMetadata:
+		case step.FieldMatrix, step.FieldForeach, step.FieldRetryPolicy, step.FieldMetadata:
 			values[i] = new([]byte)
-		case step.FieldID, step.FieldTimeout:
+		case step.FieldAllowFailure, step.FieldIdempotent:
+			values[i] = new(sql.NullBool)
+		case step.FieldID, step.FieldTimeout, step.FieldPriority, step.FieldWeight:
 			values[i] = new(sql.NullInt64)
-		case step.FieldCreatedBy, step.FieldUpdatedBy, step.FieldName, step.FieldDesc, step.FieldKind, step.FieldContent, step.FieldAction, step.FieldRule:
+		case step.FieldCreatedBy, step.FieldUpdatedBy, step.FieldName, step.FieldDesc, step.FieldKind, step.FieldContent, step.FieldAction, step.FieldRule, step.FieldRunWhen, step.FieldHandler, step.FieldMutex:
 			values[i] = new(sql.NullString)
 		case step.FieldCreatedAt, step.FieldUpdatedAt:
 			values[i] = new(sql.NullTime)
@@ -256,6 +276,71 @@ func (_m *Step) assignValues(columns []string, values []any) error {
 				_m.Rule = new(string)
 				*_m.Rule = value.String
 			}
+		case step.FieldRunWhen:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field run_when", values[i])
+			} else if value.Valid {
+				_m.RunWhen = new(string)
+				*_m.RunWhen = value.String
+			}
+		case step.FieldHandler:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field handler", values[i])
+			} else if value.Valid {
+				_m.Handler = new(string)
+				*_m.Handler = value.String
+			}
+		case step.FieldPriority:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field priority", values[i])
+			} else if value.Valid {
+				_m.Priority = new(int)
+				*_m.Priority = int(value.Int64)
+			}
+		case step.FieldWeight:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field weight", values[i])
+			} else if value.Valid {
+				_m.Weight = new(int64)
+				*_m.Weight = value.Int64
+			}
+		case step.FieldMutex:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field mutex", values[i])
+			} else if value.Valid {
+				_m.Mutex = new(string)
+				*_m.Mutex = value.String
+			}
+		case step.FieldAllowFailure:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field allow_failure", values[i])
+			} else if value.Valid {
+				_m.AllowFailure = new(bool)
+				*_m.AllowFailure = value.Bool
+			}
+		case step.FieldIdempotent:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field idempotent", values[i])
+			} else if value.Valid {
+				_m.Idempotent = new(bool)
+				*_m.Idempotent = value.Bool
+			}
+		case step.FieldMatrix:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field matrix", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Matrix); err != nil {
+					return fmt.Errorf("unmarshal field matrix: %w", err)
+				}
+			}
+		case step.FieldForeach:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field foreach", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Foreach); err != nil {
+					return fmt.Errorf("unmarshal field foreach: %w", err)
+				}
+			}
 		case step.FieldRetryPolicy:
 			if value, ok := values[i].(*[]byte); !ok {
 				return fmt.Errorf("unexpected type %T for field retry_policy", values[i])
@@ -401,6 +486,47 @@ func (_m *Step) String() string {
 		builder.WriteString(*v)
 	}
 	builder.WriteString(", ")
+	if v := _m.RunWhen; v != nil {
+		builder.WriteString("run_when=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	if v := _m.Handler; v != nil {
+		builder.WriteString("handler=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	if v := _m.Priority; v != nil {
+		builder.WriteString("priority=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.Weight; v != nil {
+		builder.WriteString("weight=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.Mutex; v != nil {
+		builder.WriteString("mutex=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	if v := _m.AllowFailure; v != nil {
+		builder.WriteString("allow_failure=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.Idempotent; v != nil {
+		builder.WriteString("idempotent=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("matrix=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Matrix))
+	builder.WriteString(", ")
+	builder.WriteString("foreach=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Foreach))
+	builder.WriteString(", ")
 	builder.WriteString("retry_policy=")
 	builder.WriteString(fmt.Sprintf("%v", _m.RetryPolicy))
 	builder.WriteString(", ")
@@ -37,6 +37,24 @@ const (
 	FieldAction = "action"
 	// FieldRule holds the string denoting the rule field in the database.
 	FieldRule = "rule"
+	// FieldRunWhen holds the string denoting the run_when field in the database.
+	FieldRunWhen = "run_when"
+	// FieldHandler holds the string denoting the handler field in the database.
+	FieldHandler = "handler"
+	// FieldPriority holds the string denoting the priority field in the database.
+	FieldPriority = "priority"
+	// FieldWeight holds the string denoting the weight field in the database.
+	FieldWeight = "weight"
+	// FieldMutex holds the string denoting the mutex field in the database.
+	FieldMutex = "mutex"
+	// FieldAllowFailure holds the string denoting the allow_failure field in the database.
+	FieldAllowFailure = "allow_failure"
+	// FieldIdempotent holds the string denoting the idempotent field in the database.
+	FieldIdempotent = "idempotent"
+	// FieldMatrix holds the string denoting the matrix field in the database.
+	FieldMatrix = "matrix"
+	// FieldForeach holds the string denoting the foreach field in the database.
+	FieldForeach = "foreach"
 	// FieldRetryPolicy holds the string denoting the retry_policy field in the database.
 	FieldRetryPolicy = "retry_policy"
 	// FieldMetadata holds the string denoting the metadata field in the database.
@@ -117,6 +135,15 @@ var Columns = []string{
 	FieldTimeout,
 	FieldAction,
 	FieldRule,
+	FieldRunWhen,
+	FieldHandler,
+	FieldPriority,
+	FieldWeight,
+	FieldMutex,
+	FieldAllowFailure,
+	FieldIdempotent,
+	FieldMatrix,
+	FieldForeach,
 	FieldRetryPolicy,
 	FieldMetadata,
 }
@@ -157,6 +184,14 @@ var (
 	NameValidator func(string) error
 	// DefaultTimeout holds the default value on creation for the "timeout" field.
 	DefaultTimeout int64
+	// DefaultPriority holds the default value on creation for the "priority" field.
+	DefaultPriority int
+	// DefaultWeight holds the default value on creation for the "weight" field.
+	DefaultWeight int64
+	// DefaultAllowFailure holds the default value on creation for the "allow_failure" field.
+	DefaultAllowFailure bool
+	// DefaultIdempotent holds the default value on creation for the "idempotent" field.
+	DefaultIdempotent bool
 	// DefaultRetryPolicy holds the default value on creation for the "retry_policy" field.
 	DefaultRetryPolicy *schema.RetryPolicy
 )
@@ -224,6 +259,41 @@ func ByRule(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldRule, opts...).ToFunc()
 }
 
+// ByRunWhen orders the results by the run_when field.
+func ByRunWhen(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldRunWhen, opts...).ToFunc()
+}
+
+// ByHandler orders the results by the handler field.
+func ByHandler(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHandler, opts...).ToFunc()
+}
+
+// ByPriority orders the results by the priority field.
+func ByPriority(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPriority, opts...).ToFunc()
+}
+
+// ByWeight orders the results by the weight field.
+func ByWeight(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldWeight, opts...).ToFunc()
+}
+
+// ByMutex orders the results by the mutex field.
+func ByMutex(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMutex, opts...).ToFunc()
+}
+
+// ByAllowFailure orders the results by the allow_failure field.
+func ByAllowFailure(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAllowFailure, opts...).ToFunc()
+}
+
+// ByIdempotent orders the results by the idempotent field.
+func ByIdempotent(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldIdempotent, opts...).ToFunc()
+}
+
 // ByTasksCount orders the results by tasks count.
 func ByTasksCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -110,6 +110,41 @@ func Rule(v string) predicate.Step {
 	return predicate.Step(sql.FieldEQ(FieldRule, v))
 }
 
+// RunWhen applies equality check predicate on the "run_when" field. It's identical to RunWhenEQ.
+func RunWhen(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldRunWhen, v))
+}
+
+// Handler applies equality check predicate on the "handler" field. It's identical to HandlerEQ.
+func Handler(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldHandler, v))
+}
+
+// Priority applies equality check predicate on the "priority" field. It's identical to PriorityEQ.
+func Priority(v int) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldPriority, v))
+}
+
+// Weight applies equality check predicate on the "weight" field. It's identical to WeightEQ.
+func Weight(v int64) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldWeight, v))
+}
+
+// Mutex applies equality check predicate on the "mutex" field. It's identical to MutexEQ.
+func Mutex(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldMutex, v))
+}
+
+// AllowFailure applies equality check predicate on the "allow_failure" field. It's identical to AllowFailureEQ.
+func AllowFailure(v bool) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldAllowFailure, v))
+}
+
+// Idempotent applies equality check predicate on the "idempotent" field. It's identical to IdempotentEQ.
+func Idempotent(v bool) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldIdempotent, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Step {
 	return predicate.Step(sql.FieldEQ(FieldCreatedAt, v))
@@ -850,6 +885,391 @@ func RuleContainsFold(v string) predicate.Step {
 	return predicate.Step(sql.FieldContainsFold(FieldRule, v))
 }
 
+// RunWhenEQ applies the EQ predicate on the "run_when" field.
+func RunWhenEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldRunWhen, v))
+}
+
+// RunWhenNEQ applies the NEQ predicate on the "run_when" field.
+func RunWhenNEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldRunWhen, v))
+}
+
+// RunWhenIn applies the In predicate on the "run_when" field.
+func RunWhenIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldIn(FieldRunWhen, vs...))
+}
+
+// RunWhenNotIn applies the NotIn predicate on the "run_when" field.
+func RunWhenNotIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldNotIn(FieldRunWhen, vs...))
+}
+
+// RunWhenGT applies the GT predicate on the "run_when" field.
+func RunWhenGT(v string) predicate.Step {
+	return predicate.Step(sql.FieldGT(FieldRunWhen, v))
+}
+
+// RunWhenGTE applies the GTE predicate on the "run_when" field.
+func RunWhenGTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldGTE(FieldRunWhen, v))
+}
+
+// RunWhenLT applies the LT predicate on the "run_when" field.
+func RunWhenLT(v string) predicate.Step {
+	return predicate.Step(sql.FieldLT(FieldRunWhen, v))
+}
+
+// RunWhenLTE applies the LTE predicate on the "run_when" field.
+func RunWhenLTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldLTE(FieldRunWhen, v))
+}
+
+// RunWhenContains applies the Contains predicate on the "run_when" field.
+func RunWhenContains(v string) predicate.Step {
+	return predicate.Step(sql.FieldContains(FieldRunWhen, v))
+}
+
+// RunWhenHasPrefix applies the HasPrefix predicate on the "run_when" field.
+func RunWhenHasPrefix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasPrefix(FieldRunWhen, v))
+}
+
+// RunWhenHasSuffix applies the HasSuffix predicate on the "run_when" field.
+func RunWhenHasSuffix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasSuffix(FieldRunWhen, v))
+}
+
+// RunWhenIsNil applies the IsNil predicate on the "run_when" field.
+func RunWhenIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldRunWhen))
+}
+
+// RunWhenNotNil applies the NotNil predicate on the "run_when" field.
+func RunWhenNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldRunWhen))
+}
+
+// RunWhenEqualFold applies the EqualFold predicate on the "run_when" field.
+func RunWhenEqualFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldEqualFold(FieldRunWhen, v))
+}
+
+// RunWhenContainsFold applies the ContainsFold predicate on the "run_when" field.
+func RunWhenContainsFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldContainsFold(FieldRunWhen, v))
+}
+
+// HandlerEQ applies the EQ predicate on the "handler" field.
+func HandlerEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldHandler, v))
+}
+
+// HandlerNEQ applies the NEQ predicate on the "handler" field.
+func HandlerNEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldHandler, v))
+}
+
+// HandlerIn applies the In predicate on the "handler" field.
+func HandlerIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldIn(FieldHandler, vs...))
+}
+
+// HandlerNotIn applies the NotIn predicate on the "handler" field.
+func HandlerNotIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldNotIn(FieldHandler, vs...))
+}
+
+// HandlerGT applies the GT predicate on the "handler" field.
+func HandlerGT(v string) predicate.Step {
+	return predicate.Step(sql.FieldGT(FieldHandler, v))
+}
+
+// HandlerGTE applies the GTE predicate on the "handler" field.
+func HandlerGTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldGTE(FieldHandler, v))
+}
+
+// HandlerLT applies the LT predicate on the "handler" field.
+func HandlerLT(v string) predicate.Step {
+	return predicate.Step(sql.FieldLT(FieldHandler, v))
+}
+
+// HandlerLTE applies the LTE predicate on the "handler" field.
+func HandlerLTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldLTE(FieldHandler, v))
+}
+
+// HandlerContains applies the Contains predicate on the "handler" field.
+func HandlerContains(v string) predicate.Step {
+	return predicate.Step(sql.FieldContains(FieldHandler, v))
+}
+
+// HandlerHasPrefix applies the HasPrefix predicate on the "handler" field.
+func HandlerHasPrefix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasPrefix(FieldHandler, v))
+}
+
+// HandlerHasSuffix applies the HasSuffix predicate on the "handler" field.
+func HandlerHasSuffix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasSuffix(FieldHandler, v))
+}
+
+// HandlerIsNil applies the IsNil predicate on the "handler" field.
+func HandlerIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldHandler))
+}
+
+// HandlerNotNil applies the NotNil predicate on the "handler" field.
+func HandlerNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldHandler))
+}
+
+// HandlerEqualFold applies the EqualFold predicate on the "handler" field.
+func HandlerEqualFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldEqualFold(FieldHandler, v))
+}
+
+// HandlerContainsFold applies the ContainsFold predicate on the "handler" field.
+func HandlerContainsFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldContainsFold(FieldHandler, v))
+}
+
+// PriorityEQ applies the EQ predicate on the "priority" field.
+func PriorityEQ(v int) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldPriority, v))
+}
+
+// PriorityNEQ applies the NEQ predicate on the "priority" field.
+func PriorityNEQ(v int) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldPriority, v))
+}
+
+// PriorityIn applies the In predicate on the "priority" field.
+func PriorityIn(vs ...int) predicate.Step {
+	return predicate.Step(sql.FieldIn(FieldPriority, vs...))
+}
+
+// PriorityNotIn applies the NotIn predicate on the "priority" field.
+func PriorityNotIn(vs ...int) predicate.Step {
+	return predicate.Step(sql.FieldNotIn(FieldPriority, vs...))
+}
+
+// PriorityGT applies the GT predicate on the "priority" field.
+func PriorityGT(v int) predicate.Step {
+	return predicate.Step(sql.FieldGT(FieldPriority, v))
+}
+
+// PriorityGTE applies the GTE predicate on the "priority" field.
+func PriorityGTE(v int) predicate.Step {
+	return predicate.Step(sql.FieldGTE(FieldPriority, v))
+}
+
+// PriorityLT applies the LT predicate on the "priority" field.
+func PriorityLT(v int) predicate.Step {
+	return predicate.Step(sql.FieldLT(FieldPriority, v))
+}
+
+// PriorityLTE applies the LTE predicate on the "priority" field.
+func PriorityLTE(v int) predicate.Step {
+	return predicate.Step(sql.FieldLTE(FieldPriority, v))
+}
+
+// PriorityIsNil applies the IsNil predicate on the "priority" field.
+func PriorityIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldPriority))
+}
+
+// PriorityNotNil applies the NotNil predicate on the "priority" field.
+func PriorityNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldPriority))
+}
+
+// WeightEQ applies the EQ predicate on the "weight" field.
+func WeightEQ(v int64) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldWeight, v))
+}
+
+// WeightNEQ applies the NEQ predicate on the "weight" field.
+func WeightNEQ(v int64) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldWeight, v))
+}
+
+// WeightIn applies the In predicate on the "weight" field.
+func WeightIn(vs ...int64) predicate.Step {
+	return predicate.Step(sql.FieldIn(FieldWeight, vs...))
+}
+
+// WeightNotIn applies the NotIn predicate on the "weight" field.
+func WeightNotIn(vs ...int64) predicate.Step {
+	return predicate.Step(sql.FieldNotIn(FieldWeight, vs...))
+}
+
+// WeightGT applies the GT predicate on the "weight" field.
+func WeightGT(v int64) predicate.Step {
+	return predicate.Step(sql.FieldGT(FieldWeight, v))
+}
+
+// WeightGTE applies the GTE predicate on the "weight" field.
+func WeightGTE(v int64) predicate.Step {
+	return predicate.Step(sql.FieldGTE(FieldWeight, v))
+}
+
+// WeightLT applies the LT predicate on the "weight" field.
+func WeightLT(v int64) predicate.Step {
+	return predicate.Step(sql.FieldLT(FieldWeight, v))
+}
+
+// WeightLTE applies the LTE predicate on the "weight" field.
+func WeightLTE(v int64) predicate.Step {
+	return predicate.Step(sql.FieldLTE(FieldWeight, v))
+}
+
+// WeightIsNil applies the IsNil predicate on the "weight" field.
+func WeightIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldWeight))
+}
+
+// WeightNotNil applies the NotNil predicate on the "weight" field.
+func WeightNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldWeight))
+}
+
+// MutexEQ applies the EQ predicate on the "mutex" field.
+func MutexEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldMutex, v))
+}
+
+// MutexNEQ applies the NEQ predicate on the "mutex" field.
+func MutexNEQ(v string) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldMutex, v))
+}
+
+// MutexIn applies the In predicate on the "mutex" field.
+func MutexIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldIn(FieldMutex, vs...))
+}
+
+// MutexNotIn applies the NotIn predicate on the "mutex" field.
+func MutexNotIn(vs ...string) predicate.Step {
+	return predicate.Step(sql.FieldNotIn(FieldMutex, vs...))
+}
+
+// MutexGT applies the GT predicate on the "mutex" field.
+func MutexGT(v string) predicate.Step {
+	return predicate.Step(sql.FieldGT(FieldMutex, v))
+}
+
+// MutexGTE applies the GTE predicate on the "mutex" field.
+func MutexGTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldGTE(FieldMutex, v))
+}
+
+// MutexLT applies the LT predicate on the "mutex" field.
+func MutexLT(v string) predicate.Step {
+	return predicate.Step(sql.FieldLT(FieldMutex, v))
+}
+
+// MutexLTE applies the LTE predicate on the "mutex" field.
+func MutexLTE(v string) predicate.Step {
+	return predicate.Step(sql.FieldLTE(FieldMutex, v))
+}
+
+// MutexContains applies the Contains predicate on the "mutex" field.
+func MutexContains(v string) predicate.Step {
+	return predicate.Step(sql.FieldContains(FieldMutex, v))
+}
+
+// MutexHasPrefix applies the HasPrefix predicate on the "mutex" field.
+func MutexHasPrefix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasPrefix(FieldMutex, v))
+}
+
+// MutexHasSuffix applies the HasSuffix predicate on the "mutex" field.
+func MutexHasSuffix(v string) predicate.Step {
+	return predicate.Step(sql.FieldHasSuffix(FieldMutex, v))
+}
+
+// MutexIsNil applies the IsNil predicate on the "mutex" field.
+func MutexIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldMutex))
+}
+
+// MutexNotNil applies the NotNil predicate on the "mutex" field.
+func MutexNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldMutex))
+}
+
+// MutexEqualFold applies the EqualFold predicate on the "mutex" field.
+func MutexEqualFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldEqualFold(FieldMutex, v))
+}
+
+// MutexContainsFold applies the ContainsFold predicate on the "mutex" field.
+func MutexContainsFold(v string) predicate.Step {
+	return predicate.Step(sql.FieldContainsFold(FieldMutex, v))
+}
+
+// AllowFailureEQ applies the EQ predicate on the "allow_failure" field.
+func AllowFailureEQ(v bool) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldAllowFailure, v))
+}
+
+// AllowFailureNEQ applies the NEQ predicate on the "allow_failure" field.
+func AllowFailureNEQ(v bool) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldAllowFailure, v))
+}
+
+// AllowFailureIsNil applies the IsNil predicate on the "allow_failure" field.
+func AllowFailureIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldAllowFailure))
+}
+
+// AllowFailureNotNil applies the NotNil predicate on the "allow_failure" field.
+func AllowFailureNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldAllowFailure))
+}
+
+// IdempotentEQ applies the EQ predicate on the "idempotent" field.
+func IdempotentEQ(v bool) predicate.Step {
+	return predicate.Step(sql.FieldEQ(FieldIdempotent, v))
+}
+
+// IdempotentNEQ applies the NEQ predicate on the "idempotent" field.
+func IdempotentNEQ(v bool) predicate.Step {
+	return predicate.Step(sql.FieldNEQ(FieldIdempotent, v))
+}
+
+// IdempotentIsNil applies the IsNil predicate on the "idempotent" field.
+func IdempotentIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldIdempotent))
+}
+
+// IdempotentNotNil applies the NotNil predicate on the "idempotent" field.
+func IdempotentNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldIdempotent))
+}
+
+// MatrixIsNil applies the IsNil predicate on the "matrix" field.
+func MatrixIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldMatrix))
+}
+
+// MatrixNotNil applies the NotNil predicate on the "matrix" field.
+func MatrixNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldMatrix))
+}
+
+// ForeachIsNil applies the IsNil predicate on the "foreach" field.
+func ForeachIsNil() predicate.Step {
+	return predicate.Step(sql.FieldIsNull(FieldForeach))
+}
+
+// ForeachNotNil applies the NotNil predicate on the "foreach" field.
+func ForeachNotNil() predicate.Step {
+	return predicate.Step(sql.FieldNotNull(FieldForeach))
+}
+
 // RetryPolicyIsNil applies the IsNil predicate on the "retry_policy" field.
 func RetryPolicyIsNil() predicate.Step {
 	return predicate.Step(sql.FieldIsNull(FieldRetryPolicy))
@@ -174,6 +174,116 @@ func (_c *StepCreate) SetNillableRule(v *string) *StepCreate {
 	return _c
 }
 
+// SetRunWhen sets the "run_when" field.
+func (_c *StepCreate) SetRunWhen(v string) *StepCreate {
+	_c.mutation.SetRunWhen(v)
+	return _c
+}
+
+// SetNillableRunWhen sets the "run_when" field if the given value is not nil.
+func (_c *StepCreate) SetNillableRunWhen(v *string) *StepCreate {
+	if v != nil {
+		_c.SetRunWhen(*v)
+	}
+	return _c
+}
+
+// SetHandler sets the "handler" field.
+func (_c *StepCreate) SetHandler(v string) *StepCreate {
+	_c.mutation.SetHandler(v)
+	return _c
+}
+
+// SetNillableHandler sets the "handler" field if the given value is not nil.
+func (_c *StepCreate) SetNillableHandler(v *string) *StepCreate {
+	if v != nil {
+		_c.SetHandler(*v)
+	}
+	return _c
+}
+
+// SetPriority sets the "priority" field.
+func (_c *StepCreate) SetPriority(v int) *StepCreate {
+	_c.mutation.SetPriority(v)
+	return _c
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (_c *StepCreate) SetNillablePriority(v *int) *StepCreate {
+	if v != nil {
+		_c.SetPriority(*v)
+	}
+	return _c
+}
+
+// SetWeight sets the "weight" field.
+func (_c *StepCreate) SetWeight(v int64) *StepCreate {
+	_c.mutation.SetWeight(v)
+	return _c
+}
+
+// SetNillableWeight sets the "weight" field if the given value is not nil.
+func (_c *StepCreate) SetNillableWeight(v *int64) *StepCreate {
+	if v != nil {
+		_c.SetWeight(*v)
+	}
+	return _c
+}
+
+// SetMutex sets the "mutex" field.
+func (_c *StepCreate) SetMutex(v string) *StepCreate {
+	_c.mutation.SetMutex(v)
+	return _c
+}
+
+// SetNillableMutex sets the "mutex" field if the given value is not nil.
+func (_c *StepCreate) SetNillableMutex(v *string) *StepCreate {
+	if v != nil {
+		_c.SetMutex(*v)
+	}
+	return _c
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (_c *StepCreate) SetAllowFailure(v bool) *StepCreate {
+	_c.mutation.SetAllowFailure(v)
+	return _c
+}
+
+// SetNillableAllowFailure sets the "allow_failure" field if the given value is not nil.
+func (_c *StepCreate) SetNillableAllowFailure(v *bool) *StepCreate {
+	if v != nil {
+		_c.SetAllowFailure(*v)
+	}
+	return _c
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (_c *StepCreate) SetIdempotent(v bool) *StepCreate {
+	_c.mutation.SetIdempotent(v)
+	return _c
+}
+
+// SetNillableIdempotent sets the "idempotent" field if the given value is not nil.
+func (_c *StepCreate) SetNillableIdempotent(v *bool) *StepCreate {
+	if v != nil {
+		_c.SetIdempotent(*v)
+	}
+	return _c
+}
+
+// SetMatrix sets the "matrix" field.
+func (_c *StepCreate) SetMatrix(v *schema.Matrix) *StepCreate {
+	_c.mutation.SetMatrix(v)
+	return _c
+}
+
+// SetForeach sets the "foreach" field.
+func (_c *StepCreate) SetForeach(v *schema.Foreach) *StepCreate {
+	_c.mutation.SetForeach(v)
+	return _c
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (_c *StepCreate) SetRetryPolicy(v *schema.RetryPolicy) *StepCreate {
 	_c.mutation.SetRetryPolicy(v)
@@ -359,6 +469,22 @@ func (_c *StepCreate) defaults() {
 		v := step.DefaultTimeout
 		_c.mutation.SetTimeout(v)
 	}
+	if _, ok := _c.mutation.Priority(); !ok {
+		v := step.DefaultPriority
+		_c.mutation.SetPriority(v)
+	}
+	if _, ok := _c.mutation.Weight(); !ok {
+		v := step.DefaultWeight
+		_c.mutation.SetWeight(v)
+	}
+	if _, ok := _c.mutation.AllowFailure(); !ok {
+		v := step.DefaultAllowFailure
+		_c.mutation.SetAllowFailure(v)
+	}
+	if _, ok := _c.mutation.Idempotent(); !ok {
+		v := step.DefaultIdempotent
+		_c.mutation.SetIdempotent(v)
+	}
 	if _, ok := _c.mutation.RetryPolicy(); !ok {
 		v := step.DefaultRetryPolicy
 		_c.mutation.SetRetryPolicy(v)
@@ -452,6 +578,42 @@ func (_c *StepCreate) createSpec() (*Step, *sqlgraph.CreateSpec) {
 		_spec.SetField(step.FieldRule, field.TypeString, value)
 		_node.Rule = &value
 	}
+	if value, ok := _c.mutation.RunWhen(); ok {
+		_spec.SetField(step.FieldRunWhen, field.TypeString, value)
+		_node.RunWhen = &value
+	}
+	if value, ok := _c.mutation.Handler(); ok {
+		_spec.SetField(step.FieldHandler, field.TypeString, value)
+		_node.Handler = &value
+	}
+	if value, ok := _c.mutation.Priority(); ok {
+		_spec.SetField(step.FieldPriority, field.TypeInt, value)
+		_node.Priority = &value
+	}
+	if value, ok := _c.mutation.Weight(); ok {
+		_spec.SetField(step.FieldWeight, field.TypeInt64, value)
+		_node.Weight = &value
+	}
+	if value, ok := _c.mutation.Mutex(); ok {
+		_spec.SetField(step.FieldMutex, field.TypeString, value)
+		_node.Mutex = &value
+	}
+	if value, ok := _c.mutation.AllowFailure(); ok {
+		_spec.SetField(step.FieldAllowFailure, field.TypeBool, value)
+		_node.AllowFailure = &value
+	}
+	if value, ok := _c.mutation.Idempotent(); ok {
+		_spec.SetField(step.FieldIdempotent, field.TypeBool, value)
+		_node.Idempotent = &value
+	}
+	if value, ok := _c.mutation.Matrix(); ok {
+		_spec.SetField(step.FieldMatrix, field.TypeJSON, value)
+		_node.Matrix = value
+	}
+	if value, ok := _c.mutation.Foreach(); ok {
+		_spec.SetField(step.FieldForeach, field.TypeJSON, value)
+		_node.Foreach = value
+	}
 	if value, ok := _c.mutation.RetryPolicy(); ok {
 		_spec.SetField(step.FieldRetryPolicy, field.TypeJSON, value)
 		_node.RetryPolicy = value
@@ -836,6 +998,180 @@ func (u *StepUpsert) ClearRule() *StepUpsert {
 	return u
 }
 
+// SetRunWhen sets the "run_when" field.
+func (u *StepUpsert) SetRunWhen(v string) *StepUpsert {
+	u.Set(step.FieldRunWhen, v)
+	return u
+}
+
+// UpdateRunWhen sets the "run_when" field to the value that was provided on create.
+func (u *StepUpsert) UpdateRunWhen() *StepUpsert {
+	u.SetExcluded(step.FieldRunWhen)
+	return u
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (u *StepUpsert) ClearRunWhen() *StepUpsert {
+	u.SetNull(step.FieldRunWhen)
+	return u
+}
+
+// SetHandler sets the "handler" field.
+func (u *StepUpsert) SetHandler(v string) *StepUpsert {
+	u.Set(step.FieldHandler, v)
+	return u
+}
+
+// UpdateHandler sets the "handler" field to the value that was provided on create.
+func (u *StepUpsert) UpdateHandler() *StepUpsert {
+	u.SetExcluded(step.FieldHandler)
+	return u
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (u *StepUpsert) ClearHandler() *StepUpsert {
+	u.SetNull(step.FieldHandler)
+	return u
+}
+
+// SetPriority sets the "priority" field.
+func (u *StepUpsert) SetPriority(v int) *StepUpsert {
+	u.Set(step.FieldPriority, v)
+	return u
+}
+
+// UpdatePriority sets the "priority" field to the value that was provided on create.
+func (u *StepUpsert) UpdatePriority() *StepUpsert {
+	u.SetExcluded(step.FieldPriority)
+	return u
+}
+
+// AddPriority adds v to the "priority" field.
+func (u *StepUpsert) AddPriority(v int) *StepUpsert {
+	u.Add(step.FieldPriority, v)
+	return u
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (u *StepUpsert) ClearPriority() *StepUpsert {
+	u.SetNull(step.FieldPriority)
+	return u
+}
+
+// SetWeight sets the "weight" field.
+func (u *StepUpsert) SetWeight(v int64) *StepUpsert {
+	u.Set(step.FieldWeight, v)
+	return u
+}
+
+// UpdateWeight sets the "weight" field to the value that was provided on create.
+func (u *StepUpsert) UpdateWeight() *StepUpsert {
+	u.SetExcluded(step.FieldWeight)
+	return u
+}
+
+// AddWeight adds v to the "weight" field.
+func (u *StepUpsert) AddWeight(v int64) *StepUpsert {
+	u.Add(step.FieldWeight, v)
+	return u
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (u *StepUpsert) ClearWeight() *StepUpsert {
+	u.SetNull(step.FieldWeight)
+	return u
+}
+
+// SetMutex sets the "mutex" field.
+func (u *StepUpsert) SetMutex(v string) *StepUpsert {
+	u.Set(step.FieldMutex, v)
+	return u
+}
+
+// UpdateMutex sets the "mutex" field to the value that was provided on create.
+func (u *StepUpsert) UpdateMutex() *StepUpsert {
+	u.SetExcluded(step.FieldMutex)
+	return u
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (u *StepUpsert) ClearMutex() *StepUpsert {
+	u.SetNull(step.FieldMutex)
+	return u
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (u *StepUpsert) SetAllowFailure(v bool) *StepUpsert {
+	u.Set(step.FieldAllowFailure, v)
+	return u
+}
+
+// UpdateAllowFailure sets the "allow_failure" field to the value that was provided on create.
+func (u *StepUpsert) UpdateAllowFailure() *StepUpsert {
+	u.SetExcluded(step.FieldAllowFailure)
+	return u
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (u *StepUpsert) ClearAllowFailure() *StepUpsert {
+	u.SetNull(step.FieldAllowFailure)
+	return u
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (u *StepUpsert) SetIdempotent(v bool) *StepUpsert {
+	u.Set(step.FieldIdempotent, v)
+	return u
+}
+
+// UpdateIdempotent sets the "idempotent" field to the value that was provided on create.
+func (u *StepUpsert) UpdateIdempotent() *StepUpsert {
+	u.SetExcluded(step.FieldIdempotent)
+	return u
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (u *StepUpsert) ClearIdempotent() *StepUpsert {
+	u.SetNull(step.FieldIdempotent)
+	return u
+}
+
+// SetMatrix sets the "matrix" field.
+func (u *StepUpsert) SetMatrix(v *schema.Matrix) *StepUpsert {
+	u.Set(step.FieldMatrix, v)
+	return u
+}
+
+// UpdateMatrix sets the "matrix" field to the value that was provided on create.
+func (u *StepUpsert) UpdateMatrix() *StepUpsert {
+	u.SetExcluded(step.FieldMatrix)
+	return u
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (u *StepUpsert) ClearMatrix() *StepUpsert {
+	u.SetNull(step.FieldMatrix)
+	return u
+}
+
+// SetForeach sets the "foreach" field.
+func (u *StepUpsert) SetForeach(v *schema.Foreach) *StepUpsert {
+	u.Set(step.FieldForeach, v)
+	return u
+}
+
+// UpdateForeach sets the "foreach" field to the value that was provided on create.
+func (u *StepUpsert) UpdateForeach() *StepUpsert {
+	u.SetExcluded(step.FieldForeach)
+	return u
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (u *StepUpsert) ClearForeach() *StepUpsert {
+	u.SetNull(step.FieldForeach)
+	return u
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (u *StepUpsert) SetRetryPolicy(v *schema.RetryPolicy) *StepUpsert {
 	u.Set(step.FieldRetryPolicy, v)
@@ -1133,6 +1469,209 @@ func (u *StepUpsertOne) ClearRule() *StepUpsertOne {
 	})
 }
 
+// SetRunWhen sets the "run_when" field.
+func (u *StepUpsertOne) SetRunWhen(v string) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetRunWhen(v)
+	})
+}
+
+// UpdateRunWhen sets the "run_when" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateRunWhen() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateRunWhen()
+	})
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (u *StepUpsertOne) ClearRunWhen() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearRunWhen()
+	})
+}
+
+// SetHandler sets the "handler" field.
+func (u *StepUpsertOne) SetHandler(v string) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetHandler(v)
+	})
+}
+
+// UpdateHandler sets the "handler" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateHandler() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateHandler()
+	})
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (u *StepUpsertOne) ClearHandler() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearHandler()
+	})
+}
+
+// SetPriority sets the "priority" field.
+func (u *StepUpsertOne) SetPriority(v int) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetPriority(v)
+	})
+}
+
+// AddPriority adds v to the "priority" field.
+func (u *StepUpsertOne) AddPriority(v int) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.AddPriority(v)
+	})
+}
+
+// UpdatePriority sets the "priority" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdatePriority() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdatePriority()
+	})
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (u *StepUpsertOne) ClearPriority() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearPriority()
+	})
+}
+
+// SetWeight sets the "weight" field.
+func (u *StepUpsertOne) SetWeight(v int64) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetWeight(v)
+	})
+}
+
+// AddWeight adds v to the "weight" field.
+func (u *StepUpsertOne) AddWeight(v int64) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.AddWeight(v)
+	})
+}
+
+// UpdateWeight sets the "weight" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateWeight() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateWeight()
+	})
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (u *StepUpsertOne) ClearWeight() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearWeight()
+	})
+}
+
+// SetMutex sets the "mutex" field.
+func (u *StepUpsertOne) SetMutex(v string) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetMutex(v)
+	})
+}
+
+// UpdateMutex sets the "mutex" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateMutex() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateMutex()
+	})
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (u *StepUpsertOne) ClearMutex() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearMutex()
+	})
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (u *StepUpsertOne) SetAllowFailure(v bool) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetAllowFailure(v)
+	})
+}
+
+// UpdateAllowFailure sets the "allow_failure" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateAllowFailure() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateAllowFailure()
+	})
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (u *StepUpsertOne) ClearAllowFailure() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearAllowFailure()
+	})
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (u *StepUpsertOne) SetIdempotent(v bool) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetIdempotent(v)
+	})
+}
+
+// UpdateIdempotent sets the "idempotent" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateIdempotent() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateIdempotent()
+	})
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (u *StepUpsertOne) ClearIdempotent() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearIdempotent()
+	})
+}
+
+// SetMatrix sets the "matrix" field.
+func (u *StepUpsertOne) SetMatrix(v *schema.Matrix) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetMatrix(v)
+	})
+}
+
+// UpdateMatrix sets the "matrix" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateMatrix() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateMatrix()
+	})
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (u *StepUpsertOne) ClearMatrix() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearMatrix()
+	})
+}
+
+// SetForeach sets the "foreach" field.
+func (u *StepUpsertOne) SetForeach(v *schema.Foreach) *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.SetForeach(v)
+	})
+}
+
+// UpdateForeach sets the "foreach" field to the value that was provided on create.
+func (u *StepUpsertOne) UpdateForeach() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateForeach()
+	})
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (u *StepUpsertOne) ClearForeach() *StepUpsertOne {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearForeach()
+	})
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (u *StepUpsertOne) SetRetryPolicy(v *schema.RetryPolicy) *StepUpsertOne {
 	return u.Update(func(s *StepUpsert) {
@@ -1602,6 +2141,209 @@ func (u *StepUpsertBulk) ClearRule() *StepUpsertBulk {
 	})
 }
 
+// SetRunWhen sets the "run_when" field.
+func (u *StepUpsertBulk) SetRunWhen(v string) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetRunWhen(v)
+	})
+}
+
+// UpdateRunWhen sets the "run_when" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateRunWhen() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateRunWhen()
+	})
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (u *StepUpsertBulk) ClearRunWhen() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearRunWhen()
+	})
+}
+
+// SetHandler sets the "handler" field.
+func (u *StepUpsertBulk) SetHandler(v string) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetHandler(v)
+	})
+}
+
+// UpdateHandler sets the "handler" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateHandler() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateHandler()
+	})
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (u *StepUpsertBulk) ClearHandler() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearHandler()
+	})
+}
+
+// SetPriority sets the "priority" field.
+func (u *StepUpsertBulk) SetPriority(v int) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetPriority(v)
+	})
+}
+
+// AddPriority adds v to the "priority" field.
+func (u *StepUpsertBulk) AddPriority(v int) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.AddPriority(v)
+	})
+}
+
+// UpdatePriority sets the "priority" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdatePriority() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdatePriority()
+	})
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (u *StepUpsertBulk) ClearPriority() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearPriority()
+	})
+}
+
+// SetWeight sets the "weight" field.
+func (u *StepUpsertBulk) SetWeight(v int64) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetWeight(v)
+	})
+}
+
+// AddWeight adds v to the "weight" field.
+func (u *StepUpsertBulk) AddWeight(v int64) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.AddWeight(v)
+	})
+}
+
+// UpdateWeight sets the "weight" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateWeight() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateWeight()
+	})
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (u *StepUpsertBulk) ClearWeight() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearWeight()
+	})
+}
+
+// SetMutex sets the "mutex" field.
+func (u *StepUpsertBulk) SetMutex(v string) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetMutex(v)
+	})
+}
+
+// UpdateMutex sets the "mutex" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateMutex() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateMutex()
+	})
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (u *StepUpsertBulk) ClearMutex() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearMutex()
+	})
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (u *StepUpsertBulk) SetAllowFailure(v bool) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetAllowFailure(v)
+	})
+}
+
+// UpdateAllowFailure sets the "allow_failure" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateAllowFailure() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateAllowFailure()
+	})
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (u *StepUpsertBulk) ClearAllowFailure() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearAllowFailure()
+	})
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (u *StepUpsertBulk) SetIdempotent(v bool) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetIdempotent(v)
+	})
+}
+
+// UpdateIdempotent sets the "idempotent" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateIdempotent() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateIdempotent()
+	})
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (u *StepUpsertBulk) ClearIdempotent() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearIdempotent()
+	})
+}
+
+// SetMatrix sets the "matrix" field.
+func (u *StepUpsertBulk) SetMatrix(v *schema.Matrix) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetMatrix(v)
+	})
+}
+
+// UpdateMatrix sets the "matrix" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateMatrix() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateMatrix()
+	})
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (u *StepUpsertBulk) ClearMatrix() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearMatrix()
+	})
+}
+
+// SetForeach sets the "foreach" field.
+func (u *StepUpsertBulk) SetForeach(v *schema.Foreach) *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.SetForeach(v)
+	})
+}
+
+// UpdateForeach sets the "foreach" field to the value that was provided on create.
+func (u *StepUpsertBulk) UpdateForeach() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.UpdateForeach()
+	})
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (u *StepUpsertBulk) ClearForeach() *StepUpsertBulk {
+	return u.Update(func(s *StepUpsert) {
+		s.ClearForeach()
+	})
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (u *StepUpsertBulk) SetRetryPolicy(v *schema.RetryPolicy) *StepUpsertBulk {
 	return u.Update(func(s *StepUpsert) {
@@ -228,6 +228,184 @@ func (_u *StepUpdate) ClearRule() *StepUpdate {
 	return _u
 }
 
+// SetRunWhen sets the "run_when" field.
+func (_u *StepUpdate) SetRunWhen(v string) *StepUpdate {
+	_u.mutation.SetRunWhen(v)
+	return _u
+}
+
+// SetNillableRunWhen sets the "run_when" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableRunWhen(v *string) *StepUpdate {
+	if v != nil {
+		_u.SetRunWhen(*v)
+	}
+	return _u
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (_u *StepUpdate) ClearRunWhen() *StepUpdate {
+	_u.mutation.ClearRunWhen()
+	return _u
+}
+
+// SetHandler sets the "handler" field.
+func (_u *StepUpdate) SetHandler(v string) *StepUpdate {
+	_u.mutation.SetHandler(v)
+	return _u
+}
+
+// SetNillableHandler sets the "handler" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableHandler(v *string) *StepUpdate {
+	if v != nil {
+		_u.SetHandler(*v)
+	}
+	return _u
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (_u *StepUpdate) ClearHandler() *StepUpdate {
+	_u.mutation.ClearHandler()
+	return _u
+}
+
+// SetPriority sets the "priority" field.
+func (_u *StepUpdate) SetPriority(v int) *StepUpdate {
+	_u.mutation.ResetPriority()
+	_u.mutation.SetPriority(v)
+	return _u
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (_u *StepUpdate) SetNillablePriority(v *int) *StepUpdate {
+	if v != nil {
+		_u.SetPriority(*v)
+	}
+	return _u
+}
+
+// AddPriority adds value to the "priority" field.
+func (_u *StepUpdate) AddPriority(v int) *StepUpdate {
+	_u.mutation.AddPriority(v)
+	return _u
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (_u *StepUpdate) ClearPriority() *StepUpdate {
+	_u.mutation.ClearPriority()
+	return _u
+}
+
+// SetWeight sets the "weight" field.
+func (_u *StepUpdate) SetWeight(v int64) *StepUpdate {
+	_u.mutation.ResetWeight()
+	_u.mutation.SetWeight(v)
+	return _u
+}
+
+// SetNillableWeight sets the "weight" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableWeight(v *int64) *StepUpdate {
+	if v != nil {
+		_u.SetWeight(*v)
+	}
+	return _u
+}
+
+// AddWeight adds value to the "weight" field.
+func (_u *StepUpdate) AddWeight(v int64) *StepUpdate {
+	_u.mutation.AddWeight(v)
+	return _u
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (_u *StepUpdate) ClearWeight() *StepUpdate {
+	_u.mutation.ClearWeight()
+	return _u
+}
+
+// SetMutex sets the "mutex" field.
+func (_u *StepUpdate) SetMutex(v string) *StepUpdate {
+	_u.mutation.SetMutex(v)
+	return _u
+}
+
+// SetNillableMutex sets the "mutex" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableMutex(v *string) *StepUpdate {
+	if v != nil {
+		_u.SetMutex(*v)
+	}
+	return _u
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (_u *StepUpdate) ClearMutex() *StepUpdate {
+	_u.mutation.ClearMutex()
+	return _u
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (_u *StepUpdate) SetAllowFailure(v bool) *StepUpdate {
+	_u.mutation.SetAllowFailure(v)
+	return _u
+}
+
+// SetNillableAllowFailure sets the "allow_failure" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableAllowFailure(v *bool) *StepUpdate {
+	if v != nil {
+		_u.SetAllowFailure(*v)
+	}
+	return _u
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (_u *StepUpdate) ClearAllowFailure() *StepUpdate {
+	_u.mutation.ClearAllowFailure()
+	return _u
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (_u *StepUpdate) SetIdempotent(v bool) *StepUpdate {
+	_u.mutation.SetIdempotent(v)
+	return _u
+}
+
+// SetNillableIdempotent sets the "idempotent" field if the given value is not nil.
+func (_u *StepUpdate) SetNillableIdempotent(v *bool) *StepUpdate {
+	if v != nil {
+		_u.SetIdempotent(*v)
+	}
+	return _u
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (_u *StepUpdate) ClearIdempotent() *StepUpdate {
+	_u.mutation.ClearIdempotent()
+	return _u
+}
+
+// SetMatrix sets the "matrix" field.
+func (_u *StepUpdate) SetMatrix(v *schema.Matrix) *StepUpdate {
+	_u.mutation.SetMatrix(v)
+	return _u
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (_u *StepUpdate) ClearMatrix() *StepUpdate {
+	_u.mutation.ClearMatrix()
+	return _u
+}
+
+// SetForeach sets the "foreach" field.
+func (_u *StepUpdate) SetForeach(v *schema.Foreach) *StepUpdate {
+	_u.mutation.SetForeach(v)
+	return _u
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (_u *StepUpdate) ClearForeach() *StepUpdate {
+	_u.mutation.ClearForeach()
+	return _u
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (_u *StepUpdate) SetRetryPolicy(v *schema.RetryPolicy) *StepUpdate {
 	_u.mutation.SetRetryPolicy(v)
@@ -672,6 +850,66 @@ func (_u *StepUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.RuleCleared() {
 		_spec.ClearField(step.FieldRule, field.TypeString)
 	}
+	if value, ok := _u.mutation.RunWhen(); ok {
+		_spec.SetField(step.FieldRunWhen, field.TypeString, value)
+	}
+	if _u.mutation.RunWhenCleared() {
+		_spec.ClearField(step.FieldRunWhen, field.TypeString)
+	}
+	if value, ok := _u.mutation.Handler(); ok {
+		_spec.SetField(step.FieldHandler, field.TypeString, value)
+	}
+	if _u.mutation.HandlerCleared() {
+		_spec.ClearField(step.FieldHandler, field.TypeString)
+	}
+	if value, ok := _u.mutation.Priority(); ok {
+		_spec.SetField(step.FieldPriority, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPriority(); ok {
+		_spec.AddField(step.FieldPriority, field.TypeInt, value)
+	}
+	if _u.mutation.PriorityCleared() {
+		_spec.ClearField(step.FieldPriority, field.TypeInt)
+	}
+	if value, ok := _u.mutation.Weight(); ok {
+		_spec.SetField(step.FieldWeight, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedWeight(); ok {
+		_spec.AddField(step.FieldWeight, field.TypeInt64, value)
+	}
+	if _u.mutation.WeightCleared() {
+		_spec.ClearField(step.FieldWeight, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.Mutex(); ok {
+		_spec.SetField(step.FieldMutex, field.TypeString, value)
+	}
+	if _u.mutation.MutexCleared() {
+		_spec.ClearField(step.FieldMutex, field.TypeString)
+	}
+	if value, ok := _u.mutation.AllowFailure(); ok {
+		_spec.SetField(step.FieldAllowFailure, field.TypeBool, value)
+	}
+	if _u.mutation.AllowFailureCleared() {
+		_spec.ClearField(step.FieldAllowFailure, field.TypeBool)
+	}
+	if value, ok := _u.mutation.Idempotent(); ok {
+		_spec.SetField(step.FieldIdempotent, field.TypeBool, value)
+	}
+	if _u.mutation.IdempotentCleared() {
+		_spec.ClearField(step.FieldIdempotent, field.TypeBool)
+	}
+	if value, ok := _u.mutation.Matrix(); ok {
+		_spec.SetField(step.FieldMatrix, field.TypeJSON, value)
+	}
+	if _u.mutation.MatrixCleared() {
+		_spec.ClearField(step.FieldMatrix, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Foreach(); ok {
+		_spec.SetField(step.FieldForeach, field.TypeJSON, value)
+	}
+	if _u.mutation.ForeachCleared() {
+		_spec.ClearField(step.FieldForeach, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.RetryPolicy(); ok {
 		_spec.SetField(step.FieldRetryPolicy, field.TypeJSON, value)
 	}
@@ -1307,6 +1545,184 @@ func (_u *StepUpdateOne) ClearRule() *StepUpdateOne {
 	return _u
 }
 
+// SetRunWhen sets the "run_when" field.
+func (_u *StepUpdateOne) SetRunWhen(v string) *StepUpdateOne {
+	_u.mutation.SetRunWhen(v)
+	return _u
+}
+
+// SetNillableRunWhen sets the "run_when" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableRunWhen(v *string) *StepUpdateOne {
+	if v != nil {
+		_u.SetRunWhen(*v)
+	}
+	return _u
+}
+
+// ClearRunWhen clears the value of the "run_when" field.
+func (_u *StepUpdateOne) ClearRunWhen() *StepUpdateOne {
+	_u.mutation.ClearRunWhen()
+	return _u
+}
+
+// SetHandler sets the "handler" field.
+func (_u *StepUpdateOne) SetHandler(v string) *StepUpdateOne {
+	_u.mutation.SetHandler(v)
+	return _u
+}
+
+// SetNillableHandler sets the "handler" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableHandler(v *string) *StepUpdateOne {
+	if v != nil {
+		_u.SetHandler(*v)
+	}
+	return _u
+}
+
+// ClearHandler clears the value of the "handler" field.
+func (_u *StepUpdateOne) ClearHandler() *StepUpdateOne {
+	_u.mutation.ClearHandler()
+	return _u
+}
+
+// SetPriority sets the "priority" field.
+func (_u *StepUpdateOne) SetPriority(v int) *StepUpdateOne {
+	_u.mutation.ResetPriority()
+	_u.mutation.SetPriority(v)
+	return _u
+}
+
+// SetNillablePriority sets the "priority" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillablePriority(v *int) *StepUpdateOne {
+	if v != nil {
+		_u.SetPriority(*v)
+	}
+	return _u
+}
+
+// AddPriority adds value to the "priority" field.
+func (_u *StepUpdateOne) AddPriority(v int) *StepUpdateOne {
+	_u.mutation.AddPriority(v)
+	return _u
+}
+
+// ClearPriority clears the value of the "priority" field.
+func (_u *StepUpdateOne) ClearPriority() *StepUpdateOne {
+	_u.mutation.ClearPriority()
+	return _u
+}
+
+// SetWeight sets the "weight" field.
+func (_u *StepUpdateOne) SetWeight(v int64) *StepUpdateOne {
+	_u.mutation.ResetWeight()
+	_u.mutation.SetWeight(v)
+	return _u
+}
+
+// SetNillableWeight sets the "weight" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableWeight(v *int64) *StepUpdateOne {
+	if v != nil {
+		_u.SetWeight(*v)
+	}
+	return _u
+}
+
+// AddWeight adds value to the "weight" field.
+func (_u *StepUpdateOne) AddWeight(v int64) *StepUpdateOne {
+	_u.mutation.AddWeight(v)
+	return _u
+}
+
+// ClearWeight clears the value of the "weight" field.
+func (_u *StepUpdateOne) ClearWeight() *StepUpdateOne {
+	_u.mutation.ClearWeight()
+	return _u
+}
+
+// SetMutex sets the "mutex" field.
+func (_u *StepUpdateOne) SetMutex(v string) *StepUpdateOne {
+	_u.mutation.SetMutex(v)
+	return _u
+}
+
+// SetNillableMutex sets the "mutex" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableMutex(v *string) *StepUpdateOne {
+	if v != nil {
+		_u.SetMutex(*v)
+	}
+	return _u
+}
+
+// ClearMutex clears the value of the "mutex" field.
+func (_u *StepUpdateOne) ClearMutex() *StepUpdateOne {
+	_u.mutation.ClearMutex()
+	return _u
+}
+
+// SetAllowFailure sets the "allow_failure" field.
+func (_u *StepUpdateOne) SetAllowFailure(v bool) *StepUpdateOne {
+	_u.mutation.SetAllowFailure(v)
+	return _u
+}
+
+// SetNillableAllowFailure sets the "allow_failure" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableAllowFailure(v *bool) *StepUpdateOne {
+	if v != nil {
+		_u.SetAllowFailure(*v)
+	}
+	return _u
+}
+
+// ClearAllowFailure clears the value of the "allow_failure" field.
+func (_u *StepUpdateOne) ClearAllowFailure() *StepUpdateOne {
+	_u.mutation.ClearAllowFailure()
+	return _u
+}
+
+// SetIdempotent sets the "idempotent" field.
+func (_u *StepUpdateOne) SetIdempotent(v bool) *StepUpdateOne {
+	_u.mutation.SetIdempotent(v)
+	return _u
+}
+
+// SetNillableIdempotent sets the "idempotent" field if the given value is not nil.
+func (_u *StepUpdateOne) SetNillableIdempotent(v *bool) *StepUpdateOne {
+	if v != nil {
+		_u.SetIdempotent(*v)
+	}
+	return _u
+}
+
+// ClearIdempotent clears the value of the "idempotent" field.
+func (_u *StepUpdateOne) ClearIdempotent() *StepUpdateOne {
+	_u.mutation.ClearIdempotent()
+	return _u
+}
+
+// SetMatrix sets the "matrix" field.
+func (_u *StepUpdateOne) SetMatrix(v *schema.Matrix) *StepUpdateOne {
+	_u.mutation.SetMatrix(v)
+	return _u
+}
+
+// ClearMatrix clears the value of the "matrix" field.
+func (_u *StepUpdateOne) ClearMatrix() *StepUpdateOne {
+	_u.mutation.ClearMatrix()
+	return _u
+}
+
+// SetForeach sets the "foreach" field.
+func (_u *StepUpdateOne) SetForeach(v *schema.Foreach) *StepUpdateOne {
+	_u.mutation.SetForeach(v)
+	return _u
+}
+
+// ClearForeach clears the value of the "foreach" field.
+func (_u *StepUpdateOne) ClearForeach() *StepUpdateOne {
+	_u.mutation.ClearForeach()
+	return _u
+}
+
 // SetRetryPolicy sets the "retry_policy" field.
 func (_u *StepUpdateOne) SetRetryPolicy(v *schema.RetryPolicy) *StepUpdateOne {
 	_u.mutation.SetRetryPolicy(v)
@@ -1781,6 +2197,66 @@ func (_u *StepUpdateOne) sqlSave(ctx context.Context) (_node *Step, err error) {
 	if _u.mutation.RuleCleared() {
 		_spec.ClearField(step.FieldRule, field.TypeString)
 	}
+	if value, ok := _u.mutation.RunWhen(); ok {
+		_spec.SetField(step.FieldRunWhen, field.TypeString, value)
+	}
+	if _u.mutation.RunWhenCleared() {
+		_spec.ClearField(step.FieldRunWhen, field.TypeString)
+	}
+	if value, ok := _u.mutation.Handler(); ok {
+		_spec.SetField(step.FieldHandler, field.TypeString, value)
+	}
+	if _u.mutation.HandlerCleared() {
+		_spec.ClearField(step.FieldHandler, field.TypeString)
+	}
+	if value, ok := _u.mutation.Priority(); ok {
+		_spec.SetField(step.FieldPriority, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedPriority(); ok {
+		_spec.AddField(step.FieldPriority, field.TypeInt, value)
+	}
+	if _u.mutation.PriorityCleared() {
+		_spec.ClearField(step.FieldPriority, field.TypeInt)
+	}
+	if value, ok := _u.mutation.Weight(); ok {
+		_spec.SetField(step.FieldWeight, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedWeight(); ok {
+		_spec.AddField(step.FieldWeight, field.TypeInt64, value)
+	}
+	if _u.mutation.WeightCleared() {
+		_spec.ClearField(step.FieldWeight, field.TypeInt64)
+	}
+	if value, ok := _u.mutation.Mutex(); ok {
+		_spec.SetField(step.FieldMutex, field.TypeString, value)
+	}
+	if _u.mutation.MutexCleared() {
+		_spec.ClearField(step.FieldMutex, field.TypeString)
+	}
+	if value, ok := _u.mutation.AllowFailure(); ok {
+		_spec.SetField(step.FieldAllowFailure, field.TypeBool, value)
+	}
+	if _u.mutation.AllowFailureCleared() {
+		_spec.ClearField(step.FieldAllowFailure, field.TypeBool)
+	}
+	if value, ok := _u.mutation.Idempotent(); ok {
+		_spec.SetField(step.FieldIdempotent, field.TypeBool, value)
+	}
+	if _u.mutation.IdempotentCleared() {
+		_spec.ClearField(step.FieldIdempotent, field.TypeBool)
+	}
+	if value, ok := _u.mutation.Matrix(); ok {
+		_spec.SetField(step.FieldMatrix, field.TypeJSON, value)
+	}
+	if _u.mutation.MatrixCleared() {
+		_spec.ClearField(step.FieldMatrix, field.TypeJSON)
+	}
+	if value, ok := _u.mutation.Foreach(); ok {
+		_spec.SetField(step.FieldForeach, field.TypeJSON, value)
+	}
+	if _u.mutation.ForeachCleared() {
+		_spec.ClearField(step.FieldForeach, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.RetryPolicy(); ok {
 		_spec.SetField(step.FieldRetryPolicy, field.TypeJSON, value)
 	}
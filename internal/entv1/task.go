@@ -51,6 +51,14 @@ type Task struct {
 	Timeout *int64 `json:"timeout,omitempty"`
 	// 重试策略
 	RetryPolicy *schema.RetryPolicy `json:"retry_policy,omitempty"`
+	// 快速失败
+	FailFast *bool `json:"fail_fast,omitempty"`
+	// 并发组
+	ConcurrencyGroup *string `json:"concurrency_group,omitempty"`
+	// 并发策略
+	ConcurrencyPolicy *string `json:"concurrency_policy,omitempty"`
+	// 元数据
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
 	// 是否为模板(默认否)
 	IsTpl *bool `json:"is_tpl,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -115,13 +123,13 @@ func (*Task) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case task.FieldRetryPolicy:
+		case task.FieldRetryPolicy, task.FieldMetadata:
 			values[i] = new([]byte)
-		case task.FieldDisabled, task.FieldIsTpl:
+		case task.FieldDisabled, task.FieldFailFast, task.FieldIsTpl:
 			values[i] = new(sql.NullBool)
 		case task.FieldID, task.FieldTimeout:
 			values[i] = new(sql.NullInt64)
-		case task.FieldCreatedBy, task.FieldUpdatedBy, task.FieldMessage, task.FieldState, task.FieldPreviousState, task.FieldName, task.FieldDesc, task.FieldKind, task.FieldNode:
+		case task.FieldCreatedBy, task.FieldUpdatedBy, task.FieldMessage, task.FieldState, task.FieldPreviousState, task.FieldName, task.FieldDesc, task.FieldKind, task.FieldNode, task.FieldConcurrencyGroup, task.FieldConcurrencyPolicy:
 			values[i] = new(sql.NullString)
 		case task.FieldCreatedAt, task.FieldUpdatedAt, task.FieldStartTime, task.FieldEndTime:
 			values[i] = new(sql.NullTime)
@@ -258,6 +266,35 @@ func (_m *Task) assignValues(columns []string, values []any) error {
 					return fmt.Errorf("unmarshal field retry_policy: %w", err)
 				}
 			}
+		case task.FieldFailFast:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field fail_fast", values[i])
+			} else if value.Valid {
+				_m.FailFast = new(bool)
+				*_m.FailFast = value.Bool
+			}
+		case task.FieldConcurrencyGroup:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field concurrency_group", values[i])
+			} else if value.Valid {
+				_m.ConcurrencyGroup = new(string)
+				*_m.ConcurrencyGroup = value.String
+			}
+		case task.FieldConcurrencyPolicy:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field concurrency_policy", values[i])
+			} else if value.Valid {
+				_m.ConcurrencyPolicy = new(string)
+				*_m.ConcurrencyPolicy = value.String
+			}
+		case task.FieldMetadata:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field metadata", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.Metadata); err != nil {
+					return fmt.Errorf("unmarshal field metadata: %w", err)
+				}
+			}
 		case task.FieldIsTpl:
 			if value, ok := values[i].(*sql.NullBool); !ok {
 				return fmt.Errorf("unexpected type %T for field is_tpl", values[i])
@@ -397,6 +434,24 @@ func (_m *Task) String() string {
 	builder.WriteString("retry_policy=")
 	builder.WriteString(fmt.Sprintf("%v", _m.RetryPolicy))
 	builder.WriteString(", ")
+	if v := _m.FailFast; v != nil {
+		builder.WriteString("fail_fast=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := _m.ConcurrencyGroup; v != nil {
+		builder.WriteString("concurrency_group=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	if v := _m.ConcurrencyPolicy; v != nil {
+		builder.WriteString("concurrency_policy=")
+		builder.WriteString(*v)
+	}
+	builder.WriteString(", ")
+	builder.WriteString("metadata=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Metadata))
+	builder.WriteString(", ")
 	if v := _m.IsTpl; v != nil {
 		builder.WriteString("is_tpl=")
 		builder.WriteString(fmt.Sprintf("%v", *v))
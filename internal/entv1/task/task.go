@@ -48,6 +48,14 @@ const (
 	FieldTimeout = "timeout"
 	// FieldRetryPolicy holds the string denoting the retry_policy field in the database.
 	FieldRetryPolicy = "retry_policy"
+	// FieldFailFast holds the string denoting the fail_fast field in the database.
+	FieldFailFast = "fail_fast"
+	// FieldConcurrencyGroup holds the string denoting the concurrency_group field in the database.
+	FieldConcurrencyGroup = "concurrency_group"
+	// FieldConcurrencyPolicy holds the string denoting the concurrency_policy field in the database.
+	FieldConcurrencyPolicy = "concurrency_policy"
+	// FieldMetadata holds the string denoting the metadata field in the database.
+	FieldMetadata = "metadata"
 	// FieldIsTpl holds the string denoting the is_tpl field in the database.
 	FieldIsTpl = "is_tpl"
 	// EdgeSteps holds the string denoting the steps edge name in mutations.
@@ -105,6 +113,10 @@ var Columns = []string{
 	FieldNode,
 	FieldTimeout,
 	FieldRetryPolicy,
+	FieldFailFast,
+	FieldConcurrencyGroup,
+	FieldConcurrencyPolicy,
+	FieldMetadata,
 	FieldIsTpl,
 }
 
@@ -142,6 +154,8 @@ var (
 	DefaultTimeout int64
 	// DefaultRetryPolicy holds the default value on creation for the "retry_policy" field.
 	DefaultRetryPolicy *schema.RetryPolicy
+	// DefaultFailFast holds the default value on creation for the "fail_fast" field.
+	DefaultFailFast bool
 	// DefaultIsTpl holds the default value on creation for the "is_tpl" field.
 	DefaultIsTpl bool
 )
@@ -161,6 +175,8 @@ const (
 	StatePending State = "pending"
 	StatePaused  State = "paused"
 	StateSkipped State = "skipped"
+	StateWaiting State = "waiting"
+	StateQueued  State = "queued"
 )
 
 func (s State) String() string {
@@ -170,7 +186,7 @@ func (s State) String() string {
 // StateValidator is a validator for the "state" field enum values. It is called by the builders before save.
 func StateValidator(s State) error {
 	switch s {
-	case StateUnknown, StateStopped, StateRunning, StateFailed, StatePending, StatePaused, StateSkipped:
+	case StateUnknown, StateStopped, StateRunning, StateFailed, StatePending, StatePaused, StateSkipped, StateWaiting, StateQueued:
 		return nil
 	default:
 		return fmt.Errorf("task: invalid enum value for state field: %q", s)
@@ -192,6 +208,8 @@ const (
 	PreviousStatePending PreviousState = "pending"
 	PreviousStatePaused  PreviousState = "paused"
 	PreviousStateSkipped PreviousState = "skipped"
+	PreviousStateWaiting PreviousState = "waiting"
+	PreviousStateQueued  PreviousState = "queued"
 )
 
 func (ps PreviousState) String() string {
@@ -201,7 +219,7 @@ func (ps PreviousState) String() string {
 // PreviousStateValidator is a validator for the "previous_state" field enum values. It is called by the builders before save.
 func PreviousStateValidator(ps PreviousState) error {
 	switch ps {
-	case PreviousStateUnknown, PreviousStateStopped, PreviousStateRunning, PreviousStateFailed, PreviousStatePending, PreviousStatePaused, PreviousStateSkipped:
+	case PreviousStateUnknown, PreviousStateStopped, PreviousStateRunning, PreviousStateFailed, PreviousStatePending, PreviousStatePaused, PreviousStateSkipped, PreviousStateWaiting, PreviousStateQueued:
 		return nil
 	default:
 		return fmt.Errorf("task: invalid enum value for previous_state field: %q", ps)
@@ -291,6 +309,21 @@ func ByTimeout(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldTimeout, opts...).ToFunc()
 }
 
+// ByFailFast orders the results by the fail_fast field.
+func ByFailFast(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldFailFast, opts...).ToFunc()
+}
+
+// ByConcurrencyGroup orders the results by the concurrency_group field.
+func ByConcurrencyGroup(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConcurrencyGroup, opts...).ToFunc()
+}
+
+// ByConcurrencyPolicy orders the results by the concurrency_policy field.
+func ByConcurrencyPolicy(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldConcurrencyPolicy, opts...).ToFunc()
+}
+
 // ByIsTpl orders the results by the is_tpl field.
 func ByIsTpl(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldIsTpl, opts...).ToFunc()
@@ -120,6 +120,21 @@ func Timeout(v int64) predicate.Task {
 	return predicate.Task(sql.FieldEQ(FieldTimeout, v))
 }
 
+// FailFast applies equality check predicate on the "fail_fast" field. It's identical to FailFastEQ.
+func FailFast(v bool) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldFailFast, v))
+}
+
+// ConcurrencyGroup applies equality check predicate on the "concurrency_group" field. It's identical to ConcurrencyGroupEQ.
+func ConcurrencyGroup(v string) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyPolicy applies equality check predicate on the "concurrency_policy" field. It's identical to ConcurrencyPolicyEQ.
+func ConcurrencyPolicy(v string) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldConcurrencyPolicy, v))
+}
+
 // IsTpl applies equality check predicate on the "is_tpl" field. It's identical to IsTplEQ.
 func IsTpl(v bool) predicate.Task {
 	return predicate.Task(sql.FieldEQ(FieldIsTpl, v))
@@ -980,6 +995,186 @@ func RetryPolicyNotNil() predicate.Task {
 	return predicate.Task(sql.FieldNotNull(FieldRetryPolicy))
 }
 
+// FailFastEQ applies the EQ predicate on the "fail_fast" field.
+func FailFastEQ(v bool) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldFailFast, v))
+}
+
+// FailFastNEQ applies the NEQ predicate on the "fail_fast" field.
+func FailFastNEQ(v bool) predicate.Task {
+	return predicate.Task(sql.FieldNEQ(FieldFailFast, v))
+}
+
+// FailFastIsNil applies the IsNil predicate on the "fail_fast" field.
+func FailFastIsNil() predicate.Task {
+	return predicate.Task(sql.FieldIsNull(FieldFailFast))
+}
+
+// FailFastNotNil applies the NotNil predicate on the "fail_fast" field.
+func FailFastNotNil() predicate.Task {
+	return predicate.Task(sql.FieldNotNull(FieldFailFast))
+}
+
+// ConcurrencyGroupEQ applies the EQ predicate on the "concurrency_group" field.
+func ConcurrencyGroupEQ(v string) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupNEQ applies the NEQ predicate on the "concurrency_group" field.
+func ConcurrencyGroupNEQ(v string) predicate.Task {
+	return predicate.Task(sql.FieldNEQ(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupIn applies the In predicate on the "concurrency_group" field.
+func ConcurrencyGroupIn(vs ...string) predicate.Task {
+	return predicate.Task(sql.FieldIn(FieldConcurrencyGroup, vs...))
+}
+
+// ConcurrencyGroupNotIn applies the NotIn predicate on the "concurrency_group" field.
+func ConcurrencyGroupNotIn(vs ...string) predicate.Task {
+	return predicate.Task(sql.FieldNotIn(FieldConcurrencyGroup, vs...))
+}
+
+// ConcurrencyGroupGT applies the GT predicate on the "concurrency_group" field.
+func ConcurrencyGroupGT(v string) predicate.Task {
+	return predicate.Task(sql.FieldGT(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupGTE applies the GTE predicate on the "concurrency_group" field.
+func ConcurrencyGroupGTE(v string) predicate.Task {
+	return predicate.Task(sql.FieldGTE(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupLT applies the LT predicate on the "concurrency_group" field.
+func ConcurrencyGroupLT(v string) predicate.Task {
+	return predicate.Task(sql.FieldLT(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupLTE applies the LTE predicate on the "concurrency_group" field.
+func ConcurrencyGroupLTE(v string) predicate.Task {
+	return predicate.Task(sql.FieldLTE(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupContains applies the Contains predicate on the "concurrency_group" field.
+func ConcurrencyGroupContains(v string) predicate.Task {
+	return predicate.Task(sql.FieldContains(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupHasPrefix applies the HasPrefix predicate on the "concurrency_group" field.
+func ConcurrencyGroupHasPrefix(v string) predicate.Task {
+	return predicate.Task(sql.FieldHasPrefix(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupHasSuffix applies the HasSuffix predicate on the "concurrency_group" field.
+func ConcurrencyGroupHasSuffix(v string) predicate.Task {
+	return predicate.Task(sql.FieldHasSuffix(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupIsNil applies the IsNil predicate on the "concurrency_group" field.
+func ConcurrencyGroupIsNil() predicate.Task {
+	return predicate.Task(sql.FieldIsNull(FieldConcurrencyGroup))
+}
+
+// ConcurrencyGroupNotNil applies the NotNil predicate on the "concurrency_group" field.
+func ConcurrencyGroupNotNil() predicate.Task {
+	return predicate.Task(sql.FieldNotNull(FieldConcurrencyGroup))
+}
+
+// ConcurrencyGroupEqualFold applies the EqualFold predicate on the "concurrency_group" field.
+func ConcurrencyGroupEqualFold(v string) predicate.Task {
+	return predicate.Task(sql.FieldEqualFold(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyGroupContainsFold applies the ContainsFold predicate on the "concurrency_group" field.
+func ConcurrencyGroupContainsFold(v string) predicate.Task {
+	return predicate.Task(sql.FieldContainsFold(FieldConcurrencyGroup, v))
+}
+
+// ConcurrencyPolicyEQ applies the EQ predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyEQ(v string) predicate.Task {
+	return predicate.Task(sql.FieldEQ(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyNEQ applies the NEQ predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyNEQ(v string) predicate.Task {
+	return predicate.Task(sql.FieldNEQ(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyIn applies the In predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyIn(vs ...string) predicate.Task {
+	return predicate.Task(sql.FieldIn(FieldConcurrencyPolicy, vs...))
+}
+
+// ConcurrencyPolicyNotIn applies the NotIn predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyNotIn(vs ...string) predicate.Task {
+	return predicate.Task(sql.FieldNotIn(FieldConcurrencyPolicy, vs...))
+}
+
+// ConcurrencyPolicyGT applies the GT predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyGT(v string) predicate.Task {
+	return predicate.Task(sql.FieldGT(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyGTE applies the GTE predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyGTE(v string) predicate.Task {
+	return predicate.Task(sql.FieldGTE(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyLT applies the LT predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyLT(v string) predicate.Task {
+	return predicate.Task(sql.FieldLT(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyLTE applies the LTE predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyLTE(v string) predicate.Task {
+	return predicate.Task(sql.FieldLTE(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyContains applies the Contains predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyContains(v string) predicate.Task {
+	return predicate.Task(sql.FieldContains(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyHasPrefix applies the HasPrefix predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyHasPrefix(v string) predicate.Task {
+	return predicate.Task(sql.FieldHasPrefix(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyHasSuffix applies the HasSuffix predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyHasSuffix(v string) predicate.Task {
+	return predicate.Task(sql.FieldHasSuffix(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyIsNil applies the IsNil predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyIsNil() predicate.Task {
+	return predicate.Task(sql.FieldIsNull(FieldConcurrencyPolicy))
+}
+
+// ConcurrencyPolicyNotNil applies the NotNil predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyNotNil() predicate.Task {
+	return predicate.Task(sql.FieldNotNull(FieldConcurrencyPolicy))
+}
+
+// ConcurrencyPolicyEqualFold applies the EqualFold predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyEqualFold(v string) predicate.Task {
+	return predicate.Task(sql.FieldEqualFold(FieldConcurrencyPolicy, v))
+}
+
+// ConcurrencyPolicyContainsFold applies the ContainsFold predicate on the "concurrency_policy" field.
+func ConcurrencyPolicyContainsFold(v string) predicate.Task {
+	return predicate.Task(sql.FieldContainsFold(FieldConcurrencyPolicy, v))
+}
+
+// MetadataIsNil applies the IsNil predicate on the "metadata" field.
+func MetadataIsNil() predicate.Task {
+	return predicate.Task(sql.FieldIsNull(FieldMetadata))
+}
+
+// MetadataNotNil applies the NotNil predicate on the "metadata" field.
+func MetadataNotNil() predicate.Task {
+	return predicate.Task(sql.FieldNotNull(FieldMetadata))
+}
+
 // IsTplEQ applies the EQ predicate on the "is_tpl" field.
 func IsTplEQ(v bool) predicate.Task {
 	return predicate.Task(sql.FieldEQ(FieldIsTpl, v))
@@ -235,6 +235,54 @@ func (_c *TaskCreate) SetRetryPolicy(v *schema.RetryPolicy) *TaskCreate {
 	return _c
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (_c *TaskCreate) SetFailFast(v bool) *TaskCreate {
+	_c.mutation.SetFailFast(v)
+	return _c
+}
+
+// SetNillableFailFast sets the "fail_fast" field if the given value is not nil.
+func (_c *TaskCreate) SetNillableFailFast(v *bool) *TaskCreate {
+	if v != nil {
+		_c.SetFailFast(*v)
+	}
+	return _c
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (_c *TaskCreate) SetConcurrencyGroup(v string) *TaskCreate {
+	_c.mutation.SetConcurrencyGroup(v)
+	return _c
+}
+
+// SetNillableConcurrencyGroup sets the "concurrency_group" field if the given value is not nil.
+func (_c *TaskCreate) SetNillableConcurrencyGroup(v *string) *TaskCreate {
+	if v != nil {
+		_c.SetConcurrencyGroup(*v)
+	}
+	return _c
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (_c *TaskCreate) SetConcurrencyPolicy(v string) *TaskCreate {
+	_c.mutation.SetConcurrencyPolicy(v)
+	return _c
+}
+
+// SetNillableConcurrencyPolicy sets the "concurrency_policy" field if the given value is not nil.
+func (_c *TaskCreate) SetNillableConcurrencyPolicy(v *string) *TaskCreate {
+	if v != nil {
+		_c.SetConcurrencyPolicy(*v)
+	}
+	return _c
+}
+
+// SetMetadata sets the "metadata" field.
+func (_c *TaskCreate) SetMetadata(v map[string]interface{}) *TaskCreate {
+	_c.mutation.SetMetadata(v)
+	return _c
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (_c *TaskCreate) SetIsTpl(v bool) *TaskCreate {
 	_c.mutation.SetIsTpl(v)
@@ -378,6 +426,10 @@ func (_c *TaskCreate) defaults() {
 		v := task.DefaultRetryPolicy
 		_c.mutation.SetRetryPolicy(v)
 	}
+	if _, ok := _c.mutation.FailFast(); !ok {
+		v := task.DefaultFailFast
+		_c.mutation.SetFailFast(v)
+	}
 	if _, ok := _c.mutation.IsTpl(); !ok {
 		v := task.DefaultIsTpl
 		_c.mutation.SetIsTpl(v)
@@ -501,6 +553,22 @@ func (_c *TaskCreate) createSpec() (*Task, *sqlgraph.CreateSpec) {
 		_spec.SetField(task.FieldRetryPolicy, field.TypeJSON, value)
 		_node.RetryPolicy = value
 	}
+	if value, ok := _c.mutation.FailFast(); ok {
+		_spec.SetField(task.FieldFailFast, field.TypeBool, value)
+		_node.FailFast = &value
+	}
+	if value, ok := _c.mutation.ConcurrencyGroup(); ok {
+		_spec.SetField(task.FieldConcurrencyGroup, field.TypeString, value)
+		_node.ConcurrencyGroup = &value
+	}
+	if value, ok := _c.mutation.ConcurrencyPolicy(); ok {
+		_spec.SetField(task.FieldConcurrencyPolicy, field.TypeString, value)
+		_node.ConcurrencyPolicy = &value
+	}
+	if value, ok := _c.mutation.Metadata(); ok {
+		_spec.SetField(task.FieldMetadata, field.TypeJSON, value)
+		_node.Metadata = value
+	}
 	if value, ok := _c.mutation.IsTpl(); ok {
 		_spec.SetField(task.FieldIsTpl, field.TypeBool, value)
 		_node.IsTpl = &value
@@ -899,6 +967,78 @@ func (u *TaskUpsert) ClearRetryPolicy() *TaskUpsert {
 	return u
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (u *TaskUpsert) SetFailFast(v bool) *TaskUpsert {
+	u.Set(task.FieldFailFast, v)
+	return u
+}
+
+// UpdateFailFast sets the "fail_fast" field to the value that was provided on create.
+func (u *TaskUpsert) UpdateFailFast() *TaskUpsert {
+	u.SetExcluded(task.FieldFailFast)
+	return u
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (u *TaskUpsert) ClearFailFast() *TaskUpsert {
+	u.SetNull(task.FieldFailFast)
+	return u
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (u *TaskUpsert) SetConcurrencyGroup(v string) *TaskUpsert {
+	u.Set(task.FieldConcurrencyGroup, v)
+	return u
+}
+
+// UpdateConcurrencyGroup sets the "concurrency_group" field to the value that was provided on create.
+func (u *TaskUpsert) UpdateConcurrencyGroup() *TaskUpsert {
+	u.SetExcluded(task.FieldConcurrencyGroup)
+	return u
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (u *TaskUpsert) ClearConcurrencyGroup() *TaskUpsert {
+	u.SetNull(task.FieldConcurrencyGroup)
+	return u
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (u *TaskUpsert) SetConcurrencyPolicy(v string) *TaskUpsert {
+	u.Set(task.FieldConcurrencyPolicy, v)
+	return u
+}
+
+// UpdateConcurrencyPolicy sets the "concurrency_policy" field to the value that was provided on create.
+func (u *TaskUpsert) UpdateConcurrencyPolicy() *TaskUpsert {
+	u.SetExcluded(task.FieldConcurrencyPolicy)
+	return u
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (u *TaskUpsert) ClearConcurrencyPolicy() *TaskUpsert {
+	u.SetNull(task.FieldConcurrencyPolicy)
+	return u
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *TaskUpsert) SetMetadata(v map[string]interface{}) *TaskUpsert {
+	u.Set(task.FieldMetadata, v)
+	return u
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *TaskUpsert) UpdateMetadata() *TaskUpsert {
+	u.SetExcluded(task.FieldMetadata)
+	return u
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *TaskUpsert) ClearMetadata() *TaskUpsert {
+	u.SetNull(task.FieldMetadata)
+	return u
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (u *TaskUpsert) SetIsTpl(v bool) *TaskUpsert {
 	u.Set(task.FieldIsTpl, v)
@@ -1283,6 +1423,90 @@ func (u *TaskUpsertOne) ClearRetryPolicy() *TaskUpsertOne {
 	})
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (u *TaskUpsertOne) SetFailFast(v bool) *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetFailFast(v)
+	})
+}
+
+// UpdateFailFast sets the "fail_fast" field to the value that was provided on create.
+func (u *TaskUpsertOne) UpdateFailFast() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateFailFast()
+	})
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (u *TaskUpsertOne) ClearFailFast() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearFailFast()
+	})
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (u *TaskUpsertOne) SetConcurrencyGroup(v string) *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetConcurrencyGroup(v)
+	})
+}
+
+// UpdateConcurrencyGroup sets the "concurrency_group" field to the value that was provided on create.
+func (u *TaskUpsertOne) UpdateConcurrencyGroup() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateConcurrencyGroup()
+	})
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (u *TaskUpsertOne) ClearConcurrencyGroup() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearConcurrencyGroup()
+	})
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (u *TaskUpsertOne) SetConcurrencyPolicy(v string) *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetConcurrencyPolicy(v)
+	})
+}
+
+// UpdateConcurrencyPolicy sets the "concurrency_policy" field to the value that was provided on create.
+func (u *TaskUpsertOne) UpdateConcurrencyPolicy() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateConcurrencyPolicy()
+	})
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (u *TaskUpsertOne) ClearConcurrencyPolicy() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearConcurrencyPolicy()
+	})
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *TaskUpsertOne) SetMetadata(v map[string]interface{}) *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetMetadata(v)
+	})
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *TaskUpsertOne) UpdateMetadata() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateMetadata()
+	})
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *TaskUpsertOne) ClearMetadata() *TaskUpsertOne {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearMetadata()
+	})
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (u *TaskUpsertOne) SetIsTpl(v bool) *TaskUpsertOne {
 	return u.Update(func(s *TaskUpsert) {
@@ -1836,6 +2060,90 @@ func (u *TaskUpsertBulk) ClearRetryPolicy() *TaskUpsertBulk {
 	})
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (u *TaskUpsertBulk) SetFailFast(v bool) *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetFailFast(v)
+	})
+}
+
+// UpdateFailFast sets the "fail_fast" field to the value that was provided on create.
+func (u *TaskUpsertBulk) UpdateFailFast() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateFailFast()
+	})
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (u *TaskUpsertBulk) ClearFailFast() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearFailFast()
+	})
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (u *TaskUpsertBulk) SetConcurrencyGroup(v string) *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetConcurrencyGroup(v)
+	})
+}
+
+// UpdateConcurrencyGroup sets the "concurrency_group" field to the value that was provided on create.
+func (u *TaskUpsertBulk) UpdateConcurrencyGroup() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateConcurrencyGroup()
+	})
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (u *TaskUpsertBulk) ClearConcurrencyGroup() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearConcurrencyGroup()
+	})
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (u *TaskUpsertBulk) SetConcurrencyPolicy(v string) *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetConcurrencyPolicy(v)
+	})
+}
+
+// UpdateConcurrencyPolicy sets the "concurrency_policy" field to the value that was provided on create.
+func (u *TaskUpsertBulk) UpdateConcurrencyPolicy() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateConcurrencyPolicy()
+	})
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (u *TaskUpsertBulk) ClearConcurrencyPolicy() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearConcurrencyPolicy()
+	})
+}
+
+// SetMetadata sets the "metadata" field.
+func (u *TaskUpsertBulk) SetMetadata(v map[string]interface{}) *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.SetMetadata(v)
+	})
+}
+
+// UpdateMetadata sets the "metadata" field to the value that was provided on create.
+func (u *TaskUpsertBulk) UpdateMetadata() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.UpdateMetadata()
+	})
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (u *TaskUpsertBulk) ClearMetadata() *TaskUpsertBulk {
+	return u.Update(func(s *TaskUpsert) {
+		s.ClearMetadata()
+	})
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (u *TaskUpsertBulk) SetIsTpl(v bool) *TaskUpsertBulk {
 	return u.Update(func(s *TaskUpsert) {
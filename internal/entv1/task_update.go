@@ -319,6 +319,78 @@ func (_u *TaskUpdate) ClearRetryPolicy() *TaskUpdate {
 	return _u
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (_u *TaskUpdate) SetFailFast(v bool) *TaskUpdate {
+	_u.mutation.SetFailFast(v)
+	return _u
+}
+
+// SetNillableFailFast sets the "fail_fast" field if the given value is not nil.
+func (_u *TaskUpdate) SetNillableFailFast(v *bool) *TaskUpdate {
+	if v != nil {
+		_u.SetFailFast(*v)
+	}
+	return _u
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (_u *TaskUpdate) ClearFailFast() *TaskUpdate {
+	_u.mutation.ClearFailFast()
+	return _u
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (_u *TaskUpdate) SetConcurrencyGroup(v string) *TaskUpdate {
+	_u.mutation.SetConcurrencyGroup(v)
+	return _u
+}
+
+// SetNillableConcurrencyGroup sets the "concurrency_group" field if the given value is not nil.
+func (_u *TaskUpdate) SetNillableConcurrencyGroup(v *string) *TaskUpdate {
+	if v != nil {
+		_u.SetConcurrencyGroup(*v)
+	}
+	return _u
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (_u *TaskUpdate) ClearConcurrencyGroup() *TaskUpdate {
+	_u.mutation.ClearConcurrencyGroup()
+	return _u
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (_u *TaskUpdate) SetConcurrencyPolicy(v string) *TaskUpdate {
+	_u.mutation.SetConcurrencyPolicy(v)
+	return _u
+}
+
+// SetNillableConcurrencyPolicy sets the "concurrency_policy" field if the given value is not nil.
+func (_u *TaskUpdate) SetNillableConcurrencyPolicy(v *string) *TaskUpdate {
+	if v != nil {
+		_u.SetConcurrencyPolicy(*v)
+	}
+	return _u
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (_u *TaskUpdate) ClearConcurrencyPolicy() *TaskUpdate {
+	_u.mutation.ClearConcurrencyPolicy()
+	return _u
+}
+
+// SetMetadata sets the "metadata" field.
+func (_u *TaskUpdate) SetMetadata(v map[string]interface{}) *TaskUpdate {
+	_u.mutation.SetMetadata(v)
+	return _u
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (_u *TaskUpdate) ClearMetadata() *TaskUpdate {
+	_u.mutation.ClearMetadata()
+	return _u
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (_u *TaskUpdate) SetIsTpl(v bool) *TaskUpdate {
 	_u.mutation.SetIsTpl(v)
@@ -655,6 +727,30 @@ func (_u *TaskUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if _u.mutation.RetryPolicyCleared() {
 		_spec.ClearField(task.FieldRetryPolicy, field.TypeJSON)
 	}
+	if value, ok := _u.mutation.FailFast(); ok {
+		_spec.SetField(task.FieldFailFast, field.TypeBool, value)
+	}
+	if _u.mutation.FailFastCleared() {
+		_spec.ClearField(task.FieldFailFast, field.TypeBool)
+	}
+	if value, ok := _u.mutation.ConcurrencyGroup(); ok {
+		_spec.SetField(task.FieldConcurrencyGroup, field.TypeString, value)
+	}
+	if _u.mutation.ConcurrencyGroupCleared() {
+		_spec.ClearField(task.FieldConcurrencyGroup, field.TypeString)
+	}
+	if value, ok := _u.mutation.ConcurrencyPolicy(); ok {
+		_spec.SetField(task.FieldConcurrencyPolicy, field.TypeString, value)
+	}
+	if _u.mutation.ConcurrencyPolicyCleared() {
+		_spec.ClearField(task.FieldConcurrencyPolicy, field.TypeString)
+	}
+	if value, ok := _u.mutation.Metadata(); ok {
+		_spec.SetField(task.FieldMetadata, field.TypeJSON, value)
+	}
+	if _u.mutation.MetadataCleared() {
+		_spec.ClearField(task.FieldMetadata, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.IsTpl(); ok {
 		_spec.SetField(task.FieldIsTpl, field.TypeBool, value)
 	}
@@ -1172,6 +1268,78 @@ func (_u *TaskUpdateOne) ClearRetryPolicy() *TaskUpdateOne {
 	return _u
 }
 
+// SetFailFast sets the "fail_fast" field.
+func (_u *TaskUpdateOne) SetFailFast(v bool) *TaskUpdateOne {
+	_u.mutation.SetFailFast(v)
+	return _u
+}
+
+// SetNillableFailFast sets the "fail_fast" field if the given value is not nil.
+func (_u *TaskUpdateOne) SetNillableFailFast(v *bool) *TaskUpdateOne {
+	if v != nil {
+		_u.SetFailFast(*v)
+	}
+	return _u
+}
+
+// ClearFailFast clears the value of the "fail_fast" field.
+func (_u *TaskUpdateOne) ClearFailFast() *TaskUpdateOne {
+	_u.mutation.ClearFailFast()
+	return _u
+}
+
+// SetConcurrencyGroup sets the "concurrency_group" field.
+func (_u *TaskUpdateOne) SetConcurrencyGroup(v string) *TaskUpdateOne {
+	_u.mutation.SetConcurrencyGroup(v)
+	return _u
+}
+
+// SetNillableConcurrencyGroup sets the "concurrency_group" field if the given value is not nil.
+func (_u *TaskUpdateOne) SetNillableConcurrencyGroup(v *string) *TaskUpdateOne {
+	if v != nil {
+		_u.SetConcurrencyGroup(*v)
+	}
+	return _u
+}
+
+// ClearConcurrencyGroup clears the value of the "concurrency_group" field.
+func (_u *TaskUpdateOne) ClearConcurrencyGroup() *TaskUpdateOne {
+	_u.mutation.ClearConcurrencyGroup()
+	return _u
+}
+
+// SetConcurrencyPolicy sets the "concurrency_policy" field.
+func (_u *TaskUpdateOne) SetConcurrencyPolicy(v string) *TaskUpdateOne {
+	_u.mutation.SetConcurrencyPolicy(v)
+	return _u
+}
+
+// SetNillableConcurrencyPolicy sets the "concurrency_policy" field if the given value is not nil.
+func (_u *TaskUpdateOne) SetNillableConcurrencyPolicy(v *string) *TaskUpdateOne {
+	if v != nil {
+		_u.SetConcurrencyPolicy(*v)
+	}
+	return _u
+}
+
+// ClearConcurrencyPolicy clears the value of the "concurrency_policy" field.
+func (_u *TaskUpdateOne) ClearConcurrencyPolicy() *TaskUpdateOne {
+	_u.mutation.ClearConcurrencyPolicy()
+	return _u
+}
+
+// SetMetadata sets the "metadata" field.
+func (_u *TaskUpdateOne) SetMetadata(v map[string]interface{}) *TaskUpdateOne {
+	_u.mutation.SetMetadata(v)
+	return _u
+}
+
+// ClearMetadata clears the value of the "metadata" field.
+func (_u *TaskUpdateOne) ClearMetadata() *TaskUpdateOne {
+	_u.mutation.ClearMetadata()
+	return _u
+}
+
 // SetIsTpl sets the "is_tpl" field.
 func (_u *TaskUpdateOne) SetIsTpl(v bool) *TaskUpdateOne {
 	_u.mutation.SetIsTpl(v)
@@ -1538,6 +1706,30 @@ func (_u *TaskUpdateOne) sqlSave(ctx context.Context) (_node *Task, err error) {
 	if _u.mutation.RetryPolicyCleared() {
 		_spec.ClearField(task.FieldRetryPolicy, field.TypeJSON)
 	}
+	if value, ok := _u.mutation.FailFast(); ok {
+		_spec.SetField(task.FieldFailFast, field.TypeBool, value)
+	}
+	if _u.mutation.FailFastCleared() {
+		_spec.ClearField(task.FieldFailFast, field.TypeBool)
+	}
+	if value, ok := _u.mutation.ConcurrencyGroup(); ok {
+		_spec.SetField(task.FieldConcurrencyGroup, field.TypeString, value)
+	}
+	if _u.mutation.ConcurrencyGroupCleared() {
+		_spec.ClearField(task.FieldConcurrencyGroup, field.TypeString)
+	}
+	if value, ok := _u.mutation.ConcurrencyPolicy(); ok {
+		_spec.SetField(task.FieldConcurrencyPolicy, field.TypeString, value)
+	}
+	if _u.mutation.ConcurrencyPolicyCleared() {
+		_spec.ClearField(task.FieldConcurrencyPolicy, field.TypeString)
+	}
+	if value, ok := _u.mutation.Metadata(); ok {
+		_spec.SetField(task.FieldMetadata, field.TypeJSON, value)
+	}
+	if _u.mutation.MetadataCleared() {
+		_spec.ClearField(task.FieldMetadata, field.TypeJSON)
+	}
 	if value, ok := _u.mutation.IsTpl(); ok {
 		_spec.SetField(task.FieldIsTpl, field.TypeBool, value)
 	}
@@ -133,6 +133,8 @@ const (
 	StatePending State = "pending"
 	StatePaused  State = "paused"
 	StateSkipped State = "skipped"
+	StateWaiting State = "waiting"
+	StateQueued  State = "queued"
 )
 
 func (s State) String() string {
@@ -142,7 +144,7 @@ func (s State) String() string {
 // StateValidator is a validator for the "state" field enum values. It is called by the builders before save.
 func StateValidator(s State) error {
 	switch s {
-	case StateUnknown, StateStopped, StateRunning, StateFailed, StatePending, StatePaused, StateSkipped:
+	case StateUnknown, StateStopped, StateRunning, StateFailed, StatePending, StatePaused, StateSkipped, StateWaiting, StateQueued:
 		return nil
 	default:
 		return fmt.Errorf("taskstep: invalid enum value for state field: %q", s)
@@ -164,6 +166,8 @@ const (
 	PreviousStatePending PreviousState = "pending"
 	PreviousStatePaused  PreviousState = "paused"
 	PreviousStateSkipped PreviousState = "skipped"
+	PreviousStateWaiting PreviousState = "waiting"
+	PreviousStateQueued  PreviousState = "queued"
 )
 
 func (ps PreviousState) String() string {
@@ -173,7 +177,7 @@ func (ps PreviousState) String() string {
 // PreviousStateValidator is a validator for the "previous_state" field enum values. It is called by the builders before save.
 func PreviousStateValidator(ps PreviousState) error {
 	switch ps {
-	case PreviousStateUnknown, PreviousStateStopped, PreviousStateRunning, PreviousStateFailed, PreviousStatePending, PreviousStatePaused, PreviousStateSkipped:
+	case PreviousStateUnknown, PreviousStateStopped, PreviousStateRunning, PreviousStateFailed, PreviousStatePending, PreviousStatePaused, PreviousStateSkipped, PreviousStateWaiting, PreviousStateQueued:
 		return nil
 	default:
 		return fmt.Errorf("taskstep: invalid enum value for previous_state field: %q", ps)
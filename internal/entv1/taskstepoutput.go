@@ -28,6 +28,8 @@ type TaskStepOutput struct {
 	UpdatedBy *string `json:"updated_by,omitempty"`
 	// 步骤ID
 	ExecID uint64 `json:"exec_id,omitempty"`
+	// 行号
+	Line int64 `json:"line,omitempty"`
 	// 时间戳
 	Timestamp *int64 `json:"timestamp,omitempty"`
 	// 内容
@@ -63,7 +65,7 @@ func (*TaskStepOutput) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case taskstepoutput.FieldID, taskstepoutput.FieldExecID, taskstepoutput.FieldTimestamp:
+		case taskstepoutput.FieldID, taskstepoutput.FieldExecID, taskstepoutput.FieldLine, taskstepoutput.FieldTimestamp:
 			values[i] = new(sql.NullInt64)
 		case taskstepoutput.FieldCreatedBy, taskstepoutput.FieldUpdatedBy, taskstepoutput.FieldContent:
 			values[i] = new(sql.NullString)
@@ -124,6 +126,12 @@ func (_m *TaskStepOutput) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.ExecID = uint64(value.Int64)
 			}
+		case taskstepoutput.FieldLine:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field line", values[i])
+			} else if value.Valid {
+				_m.Line = value.Int64
+			}
 		case taskstepoutput.FieldTimestamp:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field timestamp", values[i])
@@ -202,6 +210,9 @@ func (_m *TaskStepOutput) String() string {
 	builder.WriteString("exec_id=")
 	builder.WriteString(fmt.Sprintf("%v", _m.ExecID))
 	builder.WriteString(", ")
+	builder.WriteString("line=")
+	builder.WriteString(fmt.Sprintf("%v", _m.Line))
+	builder.WriteString(", ")
 	if v := _m.Timestamp; v != nil {
 		builder.WriteString("timestamp=")
 		builder.WriteString(fmt.Sprintf("%v", *v))
@@ -24,6 +24,8 @@ const (
 	FieldUpdatedBy = "updated_by"
 	// FieldExecID holds the string denoting the exec_id field in the database.
 	FieldExecID = "exec_id"
+	// FieldLine holds the string denoting the line field in the database.
+	FieldLine = "line"
 	// FieldTimestamp holds the string denoting the timestamp field in the database.
 	FieldTimestamp = "timestamp"
 	// FieldContent holds the string denoting the content field in the database.
@@ -49,6 +51,7 @@ var Columns = []string{
 	FieldCreatedBy,
 	FieldUpdatedBy,
 	FieldExecID,
+	FieldLine,
 	FieldTimestamp,
 	FieldContent,
 }
@@ -70,6 +73,8 @@ var (
 	DefaultUpdatedAt func() time.Time
 	// UpdateDefaultUpdatedAt holds the default value on update for the "updated_at" field.
 	UpdateDefaultUpdatedAt func() time.Time
+	// DefaultLine holds the default value on creation for the "line" field.
+	DefaultLine int64
 	// DefaultTimestamp holds the default value on creation for the "timestamp" field.
 	DefaultTimestamp func() int64
 )
@@ -107,6 +112,11 @@ func ByExecID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldExecID, opts...).ToFunc()
 }
 
+// ByLine orders the results by the line field.
+func ByLine(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLine, opts...).ToFunc()
+}
+
 // ByTimestamp orders the results by the timestamp field.
 func ByTimestamp(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldTimestamp, opts...).ToFunc()
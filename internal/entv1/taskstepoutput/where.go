@@ -80,6 +80,11 @@ func ExecID(v uint64) predicate.TaskStepOutput {
 	return predicate.TaskStepOutput(sql.FieldEQ(FieldExecID, v))
 }
 
+// Line applies equality check predicate on the "line" field. It's identical to LineEQ.
+func Line(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldEQ(FieldLine, v))
+}
+
 // Timestamp applies equality check predicate on the "timestamp" field. It's identical to TimestampEQ.
 func Timestamp(v int64) predicate.TaskStepOutput {
 	return predicate.TaskStepOutput(sql.FieldEQ(FieldTimestamp, v))
@@ -360,6 +365,46 @@ func ExecIDNotIn(vs ...uint64) predicate.TaskStepOutput {
 	return predicate.TaskStepOutput(sql.FieldNotIn(FieldExecID, vs...))
 }
 
+// LineEQ applies the EQ predicate on the "line" field.
+func LineEQ(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldEQ(FieldLine, v))
+}
+
+// LineNEQ applies the NEQ predicate on the "line" field.
+func LineNEQ(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldNEQ(FieldLine, v))
+}
+
+// LineIn applies the In predicate on the "line" field.
+func LineIn(vs ...int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldIn(FieldLine, vs...))
+}
+
+// LineNotIn applies the NotIn predicate on the "line" field.
+func LineNotIn(vs ...int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldNotIn(FieldLine, vs...))
+}
+
+// LineGT applies the GT predicate on the "line" field.
+func LineGT(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldGT(FieldLine, v))
+}
+
+// LineGTE applies the GTE predicate on the "line" field.
+func LineGTE(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldGTE(FieldLine, v))
+}
+
+// LineLT applies the LT predicate on the "line" field.
+func LineLT(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldLT(FieldLine, v))
+}
+
+// LineLTE applies the LTE predicate on the "line" field.
+func LineLTE(v int64) predicate.TaskStepOutput {
+	return predicate.TaskStepOutput(sql.FieldLTE(FieldLine, v))
+}
+
 // TimestampEQ applies the EQ predicate on the "timestamp" field.
 func TimestampEQ(v int64) predicate.TaskStepOutput {
 	return predicate.TaskStepOutput(sql.FieldEQ(FieldTimestamp, v))
@@ -85,6 +85,20 @@ func (_c *TaskStepOutputCreate) SetExecID(v uint64) *TaskStepOutputCreate {
 	return _c
 }
 
+// SetLine sets the "line" field.
+func (_c *TaskStepOutputCreate) SetLine(v int64) *TaskStepOutputCreate {
+	_c.mutation.SetLine(v)
+	return _c
+}
+
+// SetNillableLine sets the "line" field if the given value is not nil.
+func (_c *TaskStepOutputCreate) SetNillableLine(v *int64) *TaskStepOutputCreate {
+	if v != nil {
+		_c.SetLine(*v)
+	}
+	return _c
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (_c *TaskStepOutputCreate) SetTimestamp(v int64) *TaskStepOutputCreate {
 	_c.mutation.SetTimestamp(v)
@@ -173,6 +187,10 @@ func (_c *TaskStepOutputCreate) defaults() {
 		v := taskstepoutput.DefaultUpdatedAt()
 		_c.mutation.SetUpdatedAt(v)
 	}
+	if _, ok := _c.mutation.Line(); !ok {
+		v := taskstepoutput.DefaultLine
+		_c.mutation.SetLine(v)
+	}
 	if _, ok := _c.mutation.Timestamp(); !ok {
 		v := taskstepoutput.DefaultTimestamp()
 		_c.mutation.SetTimestamp(v)
@@ -184,6 +202,9 @@ func (_c *TaskStepOutputCreate) check() error {
 	if _, ok := _c.mutation.ExecID(); !ok {
 		return &ValidationError{Name: "exec_id", err: errors.New(`entv1: missing required field "TaskStepOutput.exec_id"`)}
 	}
+	if _, ok := _c.mutation.Line(); !ok {
+		return &ValidationError{Name: "line", err: errors.New(`entv1: missing required field "TaskStepOutput.line"`)}
+	}
 	if len(_c.mutation.TaskStepIDs()) == 0 {
 		return &ValidationError{Name: "task_step", err: errors.New(`entv1: missing required edge "TaskStepOutput.task_step"`)}
 	}
@@ -236,6 +257,10 @@ func (_c *TaskStepOutputCreate) createSpec() (*TaskStepOutput, *sqlgraph.CreateS
 		_spec.SetField(taskstepoutput.FieldUpdatedBy, field.TypeString, value)
 		_node.UpdatedBy = &value
 	}
+	if value, ok := _c.mutation.Line(); ok {
+		_spec.SetField(taskstepoutput.FieldLine, field.TypeInt64, value)
+		_node.Line = value
+	}
 	if value, ok := _c.mutation.Timestamp(); ok {
 		_spec.SetField(taskstepoutput.FieldTimestamp, field.TypeInt64, value)
 		_node.Timestamp = &value
@@ -379,6 +404,24 @@ func (u *TaskStepOutputUpsert) UpdateExecID() *TaskStepOutputUpsert {
 	return u
 }
 
+// SetLine sets the "line" field.
+func (u *TaskStepOutputUpsert) SetLine(v int64) *TaskStepOutputUpsert {
+	u.Set(taskstepoutput.FieldLine, v)
+	return u
+}
+
+// UpdateLine sets the "line" field to the value that was provided on create.
+func (u *TaskStepOutputUpsert) UpdateLine() *TaskStepOutputUpsert {
+	u.SetExcluded(taskstepoutput.FieldLine)
+	return u
+}
+
+// AddLine adds v to the "line" field.
+func (u *TaskStepOutputUpsert) AddLine(v int64) *TaskStepOutputUpsert {
+	u.Add(taskstepoutput.FieldLine, v)
+	return u
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (u *TaskStepOutputUpsert) SetTimestamp(v int64) *TaskStepOutputUpsert {
 	u.Set(taskstepoutput.FieldTimestamp, v)
@@ -549,6 +592,27 @@ func (u *TaskStepOutputUpsertOne) UpdateExecID() *TaskStepOutputUpsertOne {
 	})
 }
 
+// SetLine sets the "line" field.
+func (u *TaskStepOutputUpsertOne) SetLine(v int64) *TaskStepOutputUpsertOne {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.SetLine(v)
+	})
+}
+
+// AddLine adds v to the "line" field.
+func (u *TaskStepOutputUpsertOne) AddLine(v int64) *TaskStepOutputUpsertOne {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.AddLine(v)
+	})
+}
+
+// UpdateLine sets the "line" field to the value that was provided on create.
+func (u *TaskStepOutputUpsertOne) UpdateLine() *TaskStepOutputUpsertOne {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.UpdateLine()
+	})
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (u *TaskStepOutputUpsertOne) SetTimestamp(v int64) *TaskStepOutputUpsertOne {
 	return u.Update(func(s *TaskStepOutputUpsert) {
@@ -892,6 +956,27 @@ func (u *TaskStepOutputUpsertBulk) UpdateExecID() *TaskStepOutputUpsertBulk {
 	})
 }
 
+// SetLine sets the "line" field.
+func (u *TaskStepOutputUpsertBulk) SetLine(v int64) *TaskStepOutputUpsertBulk {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.SetLine(v)
+	})
+}
+
+// AddLine adds v to the "line" field.
+func (u *TaskStepOutputUpsertBulk) AddLine(v int64) *TaskStepOutputUpsertBulk {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.AddLine(v)
+	})
+}
+
+// UpdateLine sets the "line" field to the value that was provided on create.
+func (u *TaskStepOutputUpsertBulk) UpdateLine() *TaskStepOutputUpsertBulk {
+	return u.Update(func(s *TaskStepOutputUpsert) {
+		s.UpdateLine()
+	})
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (u *TaskStepOutputUpsertBulk) SetTimestamp(v int64) *TaskStepOutputUpsertBulk {
 	return u.Update(func(s *TaskStepOutputUpsert) {
@@ -96,6 +96,27 @@ func (_u *TaskStepOutputUpdate) SetNillableExecID(v *uint64) *TaskStepOutputUpda
 	return _u
 }
 
+// SetLine sets the "line" field.
+func (_u *TaskStepOutputUpdate) SetLine(v int64) *TaskStepOutputUpdate {
+	_u.mutation.ResetLine()
+	_u.mutation.SetLine(v)
+	return _u
+}
+
+// SetNillableLine sets the "line" field if the given value is not nil.
+func (_u *TaskStepOutputUpdate) SetNillableLine(v *int64) *TaskStepOutputUpdate {
+	if v != nil {
+		_u.SetLine(*v)
+	}
+	return _u
+}
+
+// AddLine adds value to the "line" field.
+func (_u *TaskStepOutputUpdate) AddLine(v int64) *TaskStepOutputUpdate {
+	_u.mutation.AddLine(v)
+	return _u
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (_u *TaskStepOutputUpdate) SetTimestamp(v int64) *TaskStepOutputUpdate {
 	_u.mutation.ResetTimestamp()
@@ -248,6 +269,12 @@ func (_u *TaskStepOutputUpdate) sqlSave(ctx context.Context) (_node int, err err
 	if _u.mutation.UpdatedByCleared() {
 		_spec.ClearField(taskstepoutput.FieldUpdatedBy, field.TypeString)
 	}
+	if value, ok := _u.mutation.Line(); ok {
+		_spec.SetField(taskstepoutput.FieldLine, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLine(); ok {
+		_spec.AddField(taskstepoutput.FieldLine, field.TypeInt64, value)
+	}
 	if value, ok := _u.mutation.Timestamp(); ok {
 		_spec.SetField(taskstepoutput.FieldTimestamp, field.TypeInt64, value)
 	}
@@ -380,6 +407,27 @@ func (_u *TaskStepOutputUpdateOne) SetNillableExecID(v *uint64) *TaskStepOutputU
 	return _u
 }
 
+// SetLine sets the "line" field.
+func (_u *TaskStepOutputUpdateOne) SetLine(v int64) *TaskStepOutputUpdateOne {
+	_u.mutation.ResetLine()
+	_u.mutation.SetLine(v)
+	return _u
+}
+
+// SetNillableLine sets the "line" field if the given value is not nil.
+func (_u *TaskStepOutputUpdateOne) SetNillableLine(v *int64) *TaskStepOutputUpdateOne {
+	if v != nil {
+		_u.SetLine(*v)
+	}
+	return _u
+}
+
+// AddLine adds value to the "line" field.
+func (_u *TaskStepOutputUpdateOne) AddLine(v int64) *TaskStepOutputUpdateOne {
+	_u.mutation.AddLine(v)
+	return _u
+}
+
 // SetTimestamp sets the "timestamp" field.
 func (_u *TaskStepOutputUpdateOne) SetTimestamp(v int64) *TaskStepOutputUpdateOne {
 	_u.mutation.ResetTimestamp()
@@ -562,6 +610,12 @@ func (_u *TaskStepOutputUpdateOne) sqlSave(ctx context.Context) (_node *TaskStep
 	if _u.mutation.UpdatedByCleared() {
 		_spec.ClearField(taskstepoutput.FieldUpdatedBy, field.TypeString)
 	}
+	if value, ok := _u.mutation.Line(); ok {
+		_spec.SetField(taskstepoutput.FieldLine, field.TypeInt64, value)
+	}
+	if value, ok := _u.mutation.AddedLine(); ok {
+		_spec.AddField(taskstepoutput.FieldLine, field.TypeInt64, value)
+	}
 	if value, ok := _u.mutation.Timestamp(); ok {
 		_spec.SetField(taskstepoutput.FieldTimestamp, field.TypeInt64, value)
 	}
@@ -0,0 +1,114 @@
+package janitor
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Cleaner 清理一类资源, 返回清理的数量及释放的空间
+type Cleaner func(ctx context.Context) (models.SReclaim, error)
+
+type sCleaner struct {
+	name string
+	fn   Cleaner
+}
+
+var (
+	mu       sync.Mutex
+	cleaners []*sCleaner
+	once     sync.Once
+)
+
+// Register 注册清理项, 同一进程中的API及工作节点共用一个清理循环
+func Register(name string, fn Cleaner) {
+	mu.Lock()
+	defer mu.Unlock()
+	cleaners = append(cleaners, &sCleaner{name: name, fn: fn})
+}
+
+// Start 按 gc_interval 定期执行所有清理项, 首次清理在启动一分钟后, 间隔为0时不清理
+func Start(ctx context.Context) {
+	once.Do(func() {
+		interval := viper.GetDuration("gc_interval")
+		if interval <= 0 {
+			logx.Infoln("janitor is disabled")
+			return
+		}
+		go func() {
+			timer := time.NewTimer(min(interval, time.Minute))
+			defer timer.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C:
+				}
+				Run(ctx)
+				timer.Reset(interval)
+			}
+		}()
+	})
+}
+
+// Run 执行一次所有清理项并保存节点的清理统计
+func Run(ctx context.Context) models.SReclaim {
+	mu.Lock()
+	var list = append([]*sCleaner(nil), cleaners...)
+	mu.Unlock()
+
+	var reclaim models.SReclaim
+	for _, cleaner := range list {
+		if ctx.Err() != nil {
+			break
+		}
+		res, err := cleaner.fn(ctx)
+		if err != nil {
+			logx.Warnln("janitor", cleaner.name, err)
+		}
+		reclaim.Add(res)
+	}
+	logx.Infof("janitor reclaimed %+v", reclaim)
+
+	node := storage.Node(viper.GetString("node_name"))
+	var janitor models.SJanitor
+	if res, err := node.Get(); err == nil {
+		janitor = res.Janitor.Data()
+	}
+	janitor.LastRun = time.Now()
+	janitor.Last = reclaim
+	janitor.Total.Add(reclaim)
+	if err := node.UpdateJanitor(janitor); err != nil {
+		logx.Warnln("janitor", err)
+	}
+	return reclaim
+}
+
+// Expired 修改时间早于 before 的文件或目录
+func Expired(path string, before time.Time) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.ModTime().Before(before)
+}
+
+// RemoveAll 删除文件或目录, 返回释放的空间
+func RemoveAll(path string) (size int64, err error) {
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, os.RemoveAll(path)
+}
@@ -0,0 +1,175 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/worker/event"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 审批结果轮询间隔
+const pollInterval = time.Second
+
+// Approver 超时自动拒绝时记录的审批人
+const Approver = "system"
+
+type sApproval struct {
+	storage storage.IStep
+
+	Message string `json:"message"` // 提示信息
+	Timeout string `json:"timeout"` // 超时自动拒绝, 如30m, 未设置时一直等待
+}
+
+func (a *sApproval) Run(ctx context.Context) (exit common.ExecCode, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			logx.Errorln(string(debug.Stack()), r)
+			err = fmt.Errorf("panic: %s", r)
+			exit = common.ExecCodeSystemErr
+		}
+	}()
+	content, err := a.storage.Content()
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+	if err = yaml.Unmarshal([]byte(content), a); err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+	timeout, err := a.timeout()
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+
+	// 重启后重新执行时, 已有的审批记录继续生效
+	approval, err := a.storage.Approval().Get()
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return common.ExecCodeSystemErr, err
+	}
+	if approval == nil {
+		approval, err = a.wait(ctx, timeout)
+		if err != nil {
+			if errors.Is(context.Cause(ctx), common.ExecErrTimeOut) {
+				return common.ExecCodeTimeout, common.ExecErrTimeOut
+			}
+			return common.ExecCodeKilled, common.ExecErrKilled
+		}
+	}
+	return a.finish(approval)
+}
+
+// wait 将步骤标记为等待审批, 直到审批、超时自动拒绝或步骤被终止
+func (a *sApproval) wait(ctx context.Context, timeout time.Duration) (*models.SStepApproval, error) {
+	message := "waiting for approval"
+	if a.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, a.Message)
+	}
+	a.storage.Log().Write(message)
+	event.Emit(&event.SEvent{Type: event.TypeWaiting, Task: a.storage.TaskName(), Step: a.storage.Name(), Message: a.Message})
+	if err := a.storage.Update(&models.SStepUpdate{
+		State:    models.Pointer(models.StateWaiting),
+		OldState: models.Pointer(models.StateRunning),
+		Message:  message,
+	}); err != nil {
+		logx.Warnln(a.storage.TaskName(), a.storage.Name(), err)
+	}
+	defer func() {
+		_ = a.storage.Update(&models.SStepUpdate{
+			State:    models.Pointer(models.StateRunning),
+			OldState: models.Pointer(models.StateWaiting),
+		})
+	}()
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			// 超时自动拒绝, 与人工审批同时发生时以先写入的记录为准
+			err := a.storage.Approval().Insert(&models.SStepApproval{
+				Approved: models.Pointer(false),
+				Approver: Approver,
+				Comment:  fmt.Sprintf("approval timed out after %s", timeout),
+			})
+			if err != nil {
+				logx.Warnln(a.storage.TaskName(), a.storage.Name(), err)
+			}
+			event.Emit(&event.SEvent{
+				Type:    event.TypeRejected,
+				Task:    a.storage.TaskName(),
+				Step:    a.storage.Name(),
+				Message: fmt.Sprintf("approval timed out after %s", timeout),
+			})
+		case <-ticker.C:
+		}
+		approval, err := a.storage.Approval().Get()
+		if err == nil {
+			return approval, nil
+		}
+		if !errors.Is(err, storage.ErrNotFound) {
+			logx.Warnln(a.storage.TaskName(), a.storage.Name(), err)
+		}
+	}
+}
+
+// finish 输出审批记录, 拒绝时步骤失败
+func (a *sApproval) finish(approval *models.SStepApproval) (common.ExecCode, error) {
+	approved := approval.Approved != nil && *approval.Approved
+	_ = a.storage.Output().Insert(
+		&models.SEnv{Name: "approved", Value: strconv.FormatBool(approved)},
+		&models.SEnv{Name: "approver", Value: approval.Approver},
+		&models.SEnv{Name: "comment", Value: approval.Comment},
+	)
+	if !approved {
+		a.storage.Log().Writef("rejected by %s at %s: %s", approval.Approver, approval.CreatedAt.Format(time.RFC3339), approval.Comment)
+		return common.ExecCodeFailed, fmt.Errorf("rejected by %s: %s", approval.Approver, approval.Comment)
+	}
+	a.storage.Log().Writef("approved by %s at %s: %s", approval.Approver, approval.CreatedAt.Format(time.RFC3339), approval.Comment)
+	return common.ExecCodeSuccess, nil
+}
+
+func (a *sApproval) timeout() (time.Duration, error) {
+	if a.Timeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(a.Timeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout < 0 {
+		return 0, errors.New("timeout must not be negative")
+	}
+	return timeout, nil
+}
+
+func (a *sApproval) Clear() error {
+	return nil
+}
+
+// validate 提交任务前校验步骤内容
+func validate(subCmd, content string) error {
+	var a = new(sApproval)
+	if err := yaml.Unmarshal([]byte(content), a); err != nil {
+		return err
+	}
+	_, err := a.timeout()
+	return err
+}
@@ -0,0 +1,16 @@
+package approval
+
+import (
+	"github.com/busyster996/dagflow/internal/runner"
+	"github.com/busyster996/dagflow/internal/storage"
+)
+
+func init() {
+	runner.RegisterValidator("approval", validate)
+	runner.RegisterIdle("approval")
+	runner.Register("approval", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
+		return &sApproval{
+			storage: storage,
+		}, nil
+	})
+}
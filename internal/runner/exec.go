@@ -2,11 +2,13 @@ package runner
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"strings"
 	"time"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/busyster996/dagflow/internal/common"
 	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
 	"github.com/busyster996/dagflow/pkg/logx"
 	"github.com/busyster996/dagflow/pkg/xexec"
 )
@@ -25,6 +28,8 @@ type sCmd struct {
 	ctx        context.Context
 	cancel     context.CancelFunc
 	envPath    string
+	pathPath   string
+	outputPath string
 	shell      string
 	workspace  string
 	scriptPath string
@@ -42,6 +47,9 @@ func (c *sCmd) scriptSuffix() string {
 func (c *sCmd) Clear() error {
 	c.cancel()
 	_ = os.Remove(c.scriptPath)
+	_ = os.Remove(c.envPath)
+	_ = os.Remove(c.pathPath)
+	_ = os.Remove(c.outputPath)
 	return nil
 }
 
@@ -70,6 +78,14 @@ func (c *sCmd) Run(ctx context.Context) (exit common.ExecCode, err error) {
 		xexec.WithScriptLogger(c),
 	)
 	exit = common.ExecCode(code)
+	// 收集步骤输出
+	if _err := c.storage.Output().Insert(c.parseEnvFileFromFile(c.outputPath)...); _err != nil {
+		logx.Warnln(_err)
+	}
+	if err == nil && exit == common.ExecCodeSuccess {
+		// 成功后将环境变量及PATH写入任务级环境变量, 对后续步骤生效
+		c.exportEnv()
+	}
 	if c.ctx.Err() != nil {
 		switch {
 		case errors.Is(context.Cause(c.ctx), common.ExecErrTimeOut):
@@ -89,6 +105,10 @@ func (c *sCmd) envs() []string {
 	for _, env := range taskEnv {
 		envs = append(envs, fmt.Sprintf("%s=%s", env.Name, env.Value))
 	}
+	// 上游步骤通过TASK_ENV及TASK_PATH导出的环境变量
+	for _, env := range c.storage.UpstreamEnv() {
+		envs = append(envs, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
 	stepEnv := c.storage.Env().List()
 	for _, env := range stepEnv {
 		envs = append(envs, fmt.Sprintf("%s=%s", env.Name, env.Value))
@@ -98,19 +118,105 @@ func (c *sCmd) envs() []string {
 		fmt.Sprintf("TASK_NAME=%s", c.storage.TaskName()),
 		fmt.Sprintf("TASK_STEP_NAME=%s", c.storage.Name()),
 		fmt.Sprintf("TASK_WORKSPACE=%s", c.workspace),
+		fmt.Sprintf("TASK_ENV=%s", c.envPath),
+		fmt.Sprintf("TASK_PATH=%s", c.pathPath),
+		fmt.Sprintf("TASK_OUTPUT=%s", c.outputPath),
 	)
 }
 
-func (c *sCmd) parseEnvFileFromFile() {
+func (c *sCmd) exportEnv() {
+	envs := c.parseEnvFileFromFile(c.envPath)
+	if paths := c.parsePathFromFile(c.pathPath); len(paths) > 0 {
+		var pathEnv *models.SEnv
+		for _, env := range envs {
+			if env.Name == "PATH" {
+				pathEnv = env
+			}
+		}
+		if pathEnv == nil {
+			pathEnv = &models.SEnv{Name: "PATH", Value: c.currentPath()}
+			envs = append(envs, pathEnv)
+		}
+		pathEnv.Value = mergePath(paths, pathEnv.Value)
+	}
+	// 导出的环境变量仅对下游步骤可见, 不写入任务的环境变量
+	if err := c.storage.Export().Insert(envs...); err != nil {
+		logx.Warnln(err)
+		c.storage.Log().Writef("export env failed: %v", err)
+	}
+}
+
+// currentPath 步骤执行时的PATH, 依次为上游步骤导出的、任务的及节点的PATH
+func (c *sCmd) currentPath() string {
+	for _, env := range c.storage.UpstreamEnv() {
+		if env.Name == "PATH" {
+			return env.Value
+		}
+	}
+	if current, err := c.storage.GlobalEnv().Get("PATH"); err == nil && current != "" {
+		return current
+	}
+	return os.Getenv("PATH")
+}
+
+// mergePath 新增目录优先, 已存在的目录不再重复添加, 重试或重新执行时PATH不会不断增长
+func mergePath(paths []string, current string) string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, dir := range append(paths, filepath.SplitList(current)...) {
+		if dir == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		res = append(res, dir)
+	}
+	return strings.Join(res, string(os.PathListSeparator))
+}
+
+// parsePathFromFile 解析每行一个目录的PATH文件
+func (c *sCmd) parsePathFromFile(path string) (res []string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logx.Warnln(err)
+		}
+		return
+	}
+	// skip utf8 bom, powershell 5 legacy uses it for utf8
+	content = bytes.TrimPrefix(content, []byte{239, 187, 191})
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		res = append(res, line)
+	}
+	return
+}
+
+// parseEnvFileFromFile 解析 KEY=VALUE 及 KEY<<EOF 格式的文件
+func (c *sCmd) parseEnvFileFromFile(path string) (res models.SEnvs) {
 	// 打开源文件
-	file, err := os.Open(c.envPath)
+	file, err := os.Open(path)
 	if err != nil {
-		logx.Warnln(err)
+		if !os.IsNotExist(err) {
+			logx.Warnln(err)
+		}
 		return
 	}
 	defer func() {
 		_ = file.Close()
 	}()
+	// 同名变量以最后一次写入为准
+	index := make(map[string]int)
+	add := func(name, value string) {
+		if i, ok := index[name]; ok {
+			res[i].Value = value
+			return
+		}
+		index[name] = len(res)
+		res = append(res, &models.SEnv{Name: name, Value: value})
+	}
 	s := bufio.NewScanner(file)
 	firstLine := true
 	for s.Scan() {
@@ -128,8 +234,8 @@ func (c *sCmd) parseEnvFileFromFile() {
 		singleLineEnv := strings.Index(line, "=")
 		multiLineEnv := strings.Index(line, "<<")
 		if singleLineEnv != -1 && (multiLineEnv == -1 || singleLineEnv < multiLineEnv) {
-			// TODO: write to storage
 			logx.Debugf("parsed env: %v=%v", line[:singleLineEnv], line[singleLineEnv+1:])
+			add(line[:singleLineEnv], line[singleLineEnv+1:])
 		} else if multiLineEnv != -1 {
 			multiLineEnvContent := ""
 			multiLineEnvDelimiter := line[multiLineEnv+2:]
@@ -147,20 +253,21 @@ func (c *sCmd) parseEnvFileFromFile() {
 			}
 			if !delimiterFound {
 				logx.Errorf("invalid format delimiter '%v' not found before end of file", multiLineEnvDelimiter)
-				return
+				return nil
 			}
-			// TODO: write to storage
 			logx.Debugf("parsed env: %v=%v", line[:multiLineEnv], multiLineEnvContent)
+			add(line[:multiLineEnv], multiLineEnvContent)
 		} else {
 			logx.Errorf("invalid format '%v', expected a line with '=' or '<<'", line)
-			return
+			return nil
 		}
 	}
 
 	if err = s.Err(); err != nil {
 		logx.Errorf("error reading file: %v", err)
-		return
+		return nil
 	}
+	return
 }
 
 func (c *sCmd) utf8ToGb2312(s string) string {
@@ -1,13 +1,16 @@
 package runner
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/segmentio/ksuid"
 	"golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/util/yaml"
 
 	"github.com/busyster996/dagflow/internal/storage"
 )
@@ -16,10 +19,42 @@ var runners = make(map[string]Factory)
 
 type Factory func(storage storage.IStep, subCmd, workspace, scriptDir string) (IRunner, error)
 
+// Validator 提交任务前静态校验步骤内容, 不访问执行环境
+type Validator func(subCmd, content string) error
+
+var validators = make(map[string]Validator)
+
+// 只等待外部结果的执行器, 如审批, 执行时不占用节点预算
+var idleRunners = make(map[string]bool)
+
+// exec执行器支持的解释器, 未注册的步骤类型按解释器名称回退到exec
+var execShells = []string{
+	"exec",
+	"ash", "bash", "csh", "dash", "ksh", "shell", "sh", "tcsh", "zsh",
+	"python", "python2", "python3", "py", "py2", "py3",
+	"cmd", "bat", "powershell", "ps", "ps1",
+}
+
 func Register(name string, factory Factory) {
 	runners[strings.ToLower(name)] = factory
 }
 
+// RegisterValidator 注册步骤内容校验
+func RegisterValidator(name string, validator Validator) {
+	validators[strings.ToLower(name)] = validator
+}
+
+// RegisterIdle 声明执行器只等待外部结果, 执行时不占用节点预算
+func RegisterIdle(name string) {
+	idleRunners[strings.ToLower(name)] = true
+}
+
+// IsIdle 步骤类型对应的执行器是否只等待外部结果, 类型格式为 runner[@subCmd]
+func IsIdle(commandType string) bool {
+	cmdType, _, _ := strings.Cut(strings.ToLower(commandType), "@")
+	return idleRunners[cmdType]
+}
+
 func ListAvailable() []string {
 	var names []string
 	for name := range runners {
@@ -40,6 +75,29 @@ func Get(name string) (Factory, error) {
 	return factory, nil
 }
 
+// Validate 校验步骤类型及内容, 类型格式为 runner[@subCmd]
+func Validate(commandType, content string) error {
+	cmdType, subCmd, _ := strings.Cut(strings.ToLower(commandType), "@")
+	if subCmd == "" {
+		subCmd = cmdType
+	}
+	if _, ok := runners[cmdType]; !ok {
+		// 回退到exec时类型即为解释器
+		if !slices.Contains(execShells, cmdType) {
+			return fmt.Errorf("unknown step type %s", commandType)
+		}
+		cmdType = "exec"
+	}
+	if cmdType == "exec" && !slices.Contains(execShells, subCmd) {
+		return fmt.Errorf("unknown shell %s", subCmd)
+	}
+	validator, ok := validators[cmdType]
+	if !ok {
+		return nil
+	}
+	return validator(subCmd, content)
+}
+
 // default runner
 func init() {
 	// sh/bash/cmd/powershell/python3 runner
@@ -50,8 +108,11 @@ func init() {
 			shell:     subCmd,
 		}
 		c.ctx, c.cancel = context.WithCancel(context.Background())
-		c.scriptPath = filepath.Join(scriptDir, ksuid.New().String())
-		c.scriptPath = c.scriptPath + c.scriptSuffix()
+		id := ksuid.New().String()
+		c.scriptPath = filepath.Join(scriptDir, id) + c.scriptSuffix()
+		c.envPath = filepath.Join(scriptDir, id) + ".env"
+		c.pathPath = filepath.Join(scriptDir, id) + ".path"
+		c.outputPath = filepath.Join(scriptDir, id) + ".output"
 		if err := os.MkdirAll(scriptDir, os.ModePerm); err != nil {
 			return nil, err
 		}
@@ -68,6 +129,7 @@ func init() {
 		return c, nil
 	})
 	// mkdir runner
+	RegisterValidator("mkdir", validatePath)
 	Register("mkdir", func(storage storage.IStep, subCmd, workspace, scriptDir string) (IRunner, error) {
 		return &sMkdir{
 			storage:   storage,
@@ -75,6 +137,7 @@ func init() {
 		}, nil
 	})
 	// touch runner
+	RegisterValidator("touch", validatePath)
 	Register("touch", func(storage storage.IStep, subCmd, workspace, scriptDir string) (IRunner, error) {
 		return &sTouch{
 			storage:   storage,
@@ -82,3 +145,17 @@ func init() {
 		}, nil
 	})
 }
+
+// validatePath 校验mkdir及touch的路径
+func validatePath(subCmd, content string) error {
+	var s struct {
+		Path string `json:"path"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &s); err != nil {
+		return err
+	}
+	if s.Path == "" {
+		return errors.New("path is empty")
+	}
+	return nil
+}
@@ -10,3 +10,9 @@ type IRunner interface {
 	Run(ctx context.Context) (exit common.ExecCode, err error)
 	Clear() error
 }
+
+// IPauser 支持在运行中挂起及解挂的执行器
+type IPauser interface {
+	Pause(duration string) error
+	Resume() error
+}
@@ -6,6 +6,7 @@ import (
 )
 
 func init() {
+	runner.RegisterValidator("kubectl", validate)
 	runner.Register("kubectl", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
 		return &sKubectl{
 			storage:    storage,
@@ -210,3 +210,25 @@ func (k *sKubectl) run(ctx context.Context, resource *types.SResource) (err erro
 func (k *sKubectl) Clear() error {
 	return nil
 }
+
+// validate 提交任务前校验步骤内容, 不连接集群
+func validate(subCmd, content string) error {
+	switch subCmd {
+	case "restart", "update", "scale", "status":
+	default:
+		return fmt.Errorf("unknown command: %s", subCmd)
+	}
+	var k = new(sKubectl)
+	if err := yaml.Unmarshal([]byte(content), k); err != nil {
+		return err
+	}
+	if len(k.Resources) == 0 {
+		return errors.New("resources is empty")
+	}
+	for _, res := range k.Resources {
+		if err := res.Check(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
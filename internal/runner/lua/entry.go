@@ -6,6 +6,7 @@ import (
 )
 
 func init() {
+	runner.RegisterValidator("lua", validate)
 	runner.Register("lua", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
 		return &sLua{
 			storage:   storage,
@@ -9,6 +9,7 @@ import (
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
 	luar "layeh.com/gopher-luar"
 
 	"github.com/busyster996/dagflow/internal/common"
@@ -141,3 +142,9 @@ func (l *sLua) logFFunc(level string) func(L *lua.LState) int {
 		return 0
 	}
 }
+
+// validate 提交任务前检查脚本语法
+func validate(subCmd, content string) error {
+	_, err := parse.Parse(strings.NewReader(content), "<string>")
+	return err
+}
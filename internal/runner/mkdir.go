@@ -10,6 +10,7 @@ import (
 
 	"github.com/busyster996/dagflow/internal/common"
 	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
 )
 
 type sMkdir struct {
@@ -36,6 +37,14 @@ func (m *sMkdir) Run(ctx context.Context) (exit common.ExecCode, err error) {
 	if err != nil {
 		return common.ExecCodeSystemErr, err
 	}
+	// 输出绝对路径, 供下游步骤使用
+	err = m.storage.Output().Insert(&models.SEnv{
+		Name:  "path",
+		Value: filepath.Join(m.workspace, m.Path),
+	})
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
 	return common.ExecCodeSuccess, nil
 }
 
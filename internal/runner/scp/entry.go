@@ -6,6 +6,7 @@ import (
 )
 
 func init() {
+	runner.RegisterValidator("scp", validate)
 	runner.Register("scp", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
 		return &sScp{
 			storage:   storage,
@@ -134,3 +134,18 @@ func (s *sScp) buildConfig() error {
 	}
 	return nil
 }
+
+// validate 提交任务前校验步骤内容
+func validate(subCmd, content string) error {
+	var s = new(sScp)
+	if err := yaml.Unmarshal([]byte(content), s); err != nil {
+		return err
+	}
+	if s.Host == "" {
+		return fmt.Errorf("host is empty")
+	}
+	if s.Source == "" || s.Target == "" {
+		return fmt.Errorf("source and target are required")
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 )
 
 func init() {
+	runner.RegisterValidator("sftp", validate)
 	runner.Register("sftp", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
 		return &sSftp{
 			storage:   storage,
@@ -0,0 +1,15 @@
+package subtask
+
+import (
+	"github.com/busyster996/dagflow/internal/runner"
+	"github.com/busyster996/dagflow/internal/storage"
+)
+
+func init() {
+	runner.RegisterValidator("subtask", validate)
+	runner.Register("subtask", func(storage storage.IStep, subCmd, workspace, scriptDir string) (runner.IRunner, error) {
+		return &sSubtask{
+			storage: storage,
+		}, nil
+	})
+}
@@ -0,0 +1,241 @@
+package subtask
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 子任务状态轮询间隔
+const pollInterval = time.Second
+
+// ILauncher 创建及管理子任务, 由服务层注入, runner 不依赖服务层
+type ILauncher interface {
+	// Build 构建流水线, 返回子任务名称
+	Build(pipeline string, params map[string]any) (string, error)
+	// Create 创建任务
+	Create(task *types.STaskReq) error
+	// Manager 管理任务, action 同任务管理接口
+	Manager(task, action, duration string) error
+	// Validate 校验任务, 返回错误信息
+	Validate(task *types.STaskReq) []string
+}
+
+var launcher ILauncher
+
+// SetLauncher 设置创建及管理子任务的实现
+func SetLauncher(l ILauncher) {
+	launcher = l
+}
+
+type sSubtask struct {
+	storage storage.IStep
+
+	mu    sync.Mutex
+	child string // 子任务名称
+
+	Pipeline string          `json:"pipeline"` // 流水线名称, 与task二选一
+	Task     *types.STaskReq `json:"task"`     // 内联任务
+	Params   map[string]any  `json:"params"`   // 参数, 流水线构建参数或内联任务的环境变量
+}
+
+func (s *sSubtask) Run(ctx context.Context) (exit common.ExecCode, err error) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			logx.Errorln(string(debug.Stack()), r)
+			err = fmt.Errorf("panic: %s", r)
+			exit = common.ExecCodeSystemErr
+		}
+	}()
+	content, err := s.storage.Content()
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+	if err = yaml.Unmarshal([]byte(content), s); err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+	if launcher == nil {
+		return common.ExecCodeSystemErr, errors.New("subtask launcher is not set")
+	}
+
+	child, err := s.start()
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
+	s.mu.Lock()
+	s.child = child
+	s.mu.Unlock()
+	s.storage.Log().Writef("subtask %s started", child)
+	_ = s.storage.Output().Insert(&models.SEnv{
+		Name:  "task",
+		Value: child,
+	})
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	var last models.State = -1
+	for {
+		select {
+		case <-ctx.Done():
+			// 父步骤被终止, 同时终止子任务
+			s.storage.Log().Writef("subtask %s killed", child)
+			if _err := launcher.Manager(child, "kill", "0"); _err != nil {
+				logx.Warnln(s.storage.TaskName(), child, _err)
+			}
+			if errors.Is(context.Cause(ctx), common.ExecErrTimeOut) {
+				return common.ExecCodeTimeout, common.ExecErrTimeOut
+			}
+			return common.ExecCodeKilled, common.ExecErrKilled
+		case <-ticker.C:
+		}
+
+		state, _err := storage.Task(child).State()
+		if _err != nil {
+			logx.Warnln(s.storage.TaskName(), child, _err)
+			continue
+		}
+		if state != last {
+			s.storage.Log().Writef("subtask %s is %s", child, models.StateMap[state])
+			last = state
+		}
+		switch state {
+		case models.StateStopped, models.StateSkipped, models.StateFailed:
+			return s.finish(child, state)
+		}
+	}
+}
+
+// start 创建子任务, 返回子任务名称
+func (s *sSubtask) start() (string, error) {
+	switch {
+	case s.Pipeline != "" && s.Task != nil:
+		return "", errors.New("pipeline and task are mutually exclusive")
+	case s.Pipeline != "":
+		return launcher.Build(s.Pipeline, s.Params)
+	case s.Task != nil:
+		if s.Task.Name == "" {
+			s.Task.Name = fmt.Sprintf("%s-%s", s.storage.TaskName(), s.storage.Name())
+		}
+		// 参数覆盖同名环境变量
+		for name, value := range s.Params {
+			env := &types.SEnv{
+				Name:  name,
+				Value: fmt.Sprint(value),
+			}
+			var found bool
+			for k, v := range s.Task.Env {
+				if v.Name == name {
+					s.Task.Env[k] = env
+					found = true
+				}
+			}
+			if !found {
+				s.Task.Env = append(s.Task.Env, env)
+			}
+		}
+		if err := launcher.Create(s.Task); err != nil {
+			return "", err
+		}
+		return s.Task.Name, nil
+	default:
+		return "", errors.New("pipeline or task is required")
+	}
+}
+
+// finish 将子任务的最终状态, 退出码及输出作为当前步骤的结果
+func (s *sSubtask) finish(child string, state models.State) (common.ExecCode, error) {
+	db := storage.Task(child)
+	var outputs models.SEnvs
+	outputs = append(outputs, &models.SEnv{
+		Name:  "state",
+		Value: models.StateMap[state],
+	})
+	exit := common.ExecCodeSuccess
+	for _, step := range db.StepList(storage.All) {
+		if *step.State == models.StateFailed && exit == common.ExecCodeSuccess {
+			exit = *step.Code
+		}
+		for _, output := range db.Step(step.Name).Output().List() {
+			outputs = append(outputs, &models.SEnv{
+				Name:  fmt.Sprintf("%s.%s", step.Name, output.Name),
+				Value: output.Value,
+			})
+		}
+	}
+	if err := s.storage.Output().Insert(outputs...); err != nil {
+		logx.Warnln(s.storage.TaskName(), child, err)
+	}
+	if state != models.StateFailed {
+		return common.ExecCodeSuccess, nil
+	}
+	if exit == common.ExecCodeSuccess {
+		exit = common.ExecCodeFailed
+	}
+	task, err := db.Get()
+	if err != nil {
+		return exit, fmt.Errorf("subtask %s failed", child)
+	}
+	return exit, fmt.Errorf("subtask %s failed: %s", child, task.Message)
+}
+
+// Pause 挂起子任务
+func (s *sSubtask) Pause(duration string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.child == "" {
+		return errors.New("subtask not started")
+	}
+	return launcher.Manager(s.child, "pause", duration)
+}
+
+// Resume 解挂子任务
+func (s *sSubtask) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.child == "" {
+		return errors.New("subtask not started")
+	}
+	return launcher.Manager(s.child, "resume", "")
+}
+
+// validate 提交任务前校验步骤内容, 内联任务按同样规则递归校验
+func validate(subCmd, content string) error {
+	var s = new(sSubtask)
+	if err := yaml.Unmarshal([]byte(content), s); err != nil {
+		return err
+	}
+	switch {
+	case s.Pipeline != "" && s.Task != nil:
+		return errors.New("pipeline and task are mutually exclusive")
+	case s.Pipeline == "" && s.Task == nil:
+		return errors.New("pipeline or task is required")
+	case s.Task != nil:
+		if s.Task.Name == "" {
+			s.Task.Name = "subtask"
+		}
+		if launcher == nil {
+			return errors.New("subtask launcher is not set")
+		}
+		if errs := launcher.Validate(s.Task); len(errs) != 0 {
+			return fmt.Errorf("invalid task: %s", strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+func (s *sSubtask) Clear() error {
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/busyster996/dagflow/internal/common"
 	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
 )
 
 type sTouch struct {
@@ -51,6 +52,14 @@ func (t *sTouch) Run(ctx context.Context) (exit common.ExecCode, err error) {
 	if err != nil {
 		return common.ExecCodeSystemErr, err
 	}
+	// 输出绝对路径, 供下游步骤使用
+	err = t.storage.Output().Insert(&models.SEnv{
+		Name:  "path",
+		Value: filepath.Join(t.workspace, t.Path),
+	})
+	if err != nil {
+		return common.ExecCodeSystemErr, err
+	}
 	return common.ExecCodeSuccess, nil
 }
 
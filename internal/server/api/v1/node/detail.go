@@ -0,0 +1,35 @@
+package node
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Detail
+// @Summary		详情
+// @Description	获取指定节点的步骤并发预算
+// @Tags		节点
+// @Accept		application/json
+// @Produce		application/json
+// @Param		node path string true "节点名称"
+// @Success		200 {object} base.IResponse[types.SNodeRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/node/{node} [get]
+func Detail(c *gin.Context) {
+	nodeName := c.Param("node")
+	if nodeName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("node does not exist")))
+		return
+	}
+	res, err := service.NodeDetail(nodeName)
+	if err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(err))
+		return
+	}
+	base.Send(c, base.WithData(res))
+}
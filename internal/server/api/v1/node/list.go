@@ -0,0 +1,22 @@
+package node
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/internal/server/types"
+)
+
+// List
+// @Summary		列表
+// @Description	获取所有节点的步骤并发预算, 包含总槽位、已占用槽位、正在执行及等待的步骤
+// @Tags		节点
+// @Accept		application/json
+// @Produce		application/json
+// @Success		200 {object} base.IResponse[types.SNodesRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/node [get]
+func List(c *gin.Context) {
+	base.Send(c, base.WithData[types.SNodesRes](service.NodeList()))
+}
@@ -0,0 +1,47 @@
+package task
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Graph
+// @Summary		拓扑图
+// @Description	导出任务拓扑, 包含步骤状态、耗时、执行节点、依赖边、拓扑层级及关键路径
+// @Tags		任务
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		format query string false "输出格式" Enums(json,dot,mermaid) default(json)
+// @Success		200 {object} base.IResponse[types.STaskGraphRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/graph [get]
+func Graph(c *gin.Context) {
+	taskName := c.Param("task")
+	if taskName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("task does not exist")))
+		return
+	}
+	format := c.DefaultQuery("format", service.GraphFormatJSON)
+	res, err := service.Task(taskName).Graph()
+	if err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	if format == service.GraphFormatJSON {
+		base.Send(c, base.WithData[*types.STaskGraphRes](res))
+		return
+	}
+	data, err := service.RenderGraph(res, format)
+	if err != nil {
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	base.Send(c, base.WithData[string](data))
+}
@@ -0,0 +1,34 @@
+package task
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Resume
+// @Summary		恢复执行
+// @Description	从失败处恢复执行任务, 仅重新执行失败、被终止或未执行的步骤
+// @Tags		任务
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Success		200 {object} base.IResponse[any]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/resume [post]
+func Resume(c *gin.Context) {
+	taskName := c.Param("task")
+	if taskName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("task does not exist")))
+		return
+	}
+	if err := service.Task(taskName).Resume(); err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	base.Send(c, base.WithCode[any](base.CodeSuccess))
+}
@@ -0,0 +1,62 @@
+package run
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+)
+
+// Detail
+// @Summary		详情
+// @Description	获取任务指定执行的详情
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		run path int true "执行序号"
+// @Success		200 {object} base.IResponse[types.STaskRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs/{run} [get]
+func Detail(c *gin.Context) {
+	rs, ok := runService(c)
+	if !ok {
+		return
+	}
+	code, task, err := rs.Detail()
+	if err != nil {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(err))
+		return
+	}
+	base.Send(c, base.WithData(task).WithCode(code).WithError(errors.New(task.Message)))
+}
+
+// Dump
+// @Summary		导出
+// @Description	导出任务指定执行提交的内容, 可直接重新提交
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		run path int true "执行序号"
+// @Success		200 {object} base.IResponse[any]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs/{run}/dump [get]
+func Dump(c *gin.Context) {
+	rs, ok := runService(c)
+	if !ok {
+		return
+	}
+	res, err := rs.Request()
+	if err != nil {
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	data, err := yaml.Marshal(res)
+	if err != nil {
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	base.Send(c, base.WithData[string](string(data)))
+}
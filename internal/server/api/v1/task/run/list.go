@@ -0,0 +1,46 @@
+package run
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+)
+
+// List
+// @Summary		列表
+// @Description	获取任务的所有执行记录, 按执行序号倒序, 最近一次为当前任务
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Success		200 {object} base.IResponse[types.STaskRunsRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs [get]
+func List(c *gin.Context) {
+	taskName := c.Param("task")
+	if taskName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("task does not exist")))
+		return
+	}
+	code, list, err := service.Task(taskName).Runs()
+	base.Send(c, base.WithData(list).WithCode(code).WithError(err))
+}
+
+// runService 解析路径中的任务名称及执行序号
+func runService(c *gin.Context) (*service.STaskRunService, bool) {
+	taskName := c.Param("task")
+	if taskName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("task does not exist")))
+		return nil, false
+	}
+	run, err := strconv.ParseInt(c.Param("run"), 10, 64)
+	if err != nil || run <= 0 {
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(errors.New("invalid run")))
+		return nil, false
+	}
+	return service.TaskRun(taskName, run), true
+}
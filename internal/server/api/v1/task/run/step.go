@@ -0,0 +1,95 @@
+package run
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+)
+
+// StepList
+// @Summary		步骤列表
+// @Description	获取任务指定执行的步骤列表
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		run path int true "执行序号"
+// @Success		200 {object} base.IResponse[types.SStepsRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs/{run}/step [get]
+func StepList(c *gin.Context) {
+	rs, ok := runService(c)
+	if !ok {
+		return
+	}
+	code, list, err := rs.Steps()
+	base.Send(c, base.WithData(list).WithError(err).WithCode(code))
+}
+
+// StepDetail
+// @Summary		步骤详情
+// @Description	获取任务指定执行中的步骤详情
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		run path int true "执行序号"
+// @Param		step path string true "步骤名称"
+// @Success		200 {object} base.IResponse[types.SStepRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs/{run}/step/{step} [get]
+func StepDetail(c *gin.Context) {
+	rs, ok := runService(c)
+	if !ok {
+		return
+	}
+	stepName := c.Param("step")
+	if stepName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("step does not exist")))
+		return
+	}
+	code, step, err := rs.StepDetail(stepName)
+	if err != nil {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(err))
+		return
+	}
+	base.Send(c, base.WithData(step).WithCode(code).WithError(errors.New(step.Message)))
+}
+
+// StepLog
+// @Summary		步骤日志
+// @Description	任务指定执行中步骤的执行输出
+// @Tags		执行记录
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		run path int true "执行序号"
+// @Param		step path string true "步骤名称"
+// @Param		attempt query int false "执行次数, 仅返回该次执行的日志"
+// @Success		200 {object} base.IResponse[types.SStepLogsRes]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/runs/{run}/step/{step}/log [get]
+func StepLog(c *gin.Context) {
+	rs, ok := runService(c)
+	if !ok {
+		return
+	}
+	stepName := c.Param("step")
+	if stepName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("step does not exist")))
+		return
+	}
+	var n int
+	if attempt := c.Query("attempt"); attempt != "" {
+		var err error
+		if n, err = strconv.Atoi(attempt); err != nil {
+			base.Send(c, base.WithCode[any](base.CodeFailed).WithError(errors.New("invalid attempt")))
+			return
+		}
+	}
+	code, res, err := rs.StepLog(stepName, n)
+	base.Send(c, base.WithData(res).WithCode(code).WithError(err))
+}
@@ -0,0 +1,68 @@
+package step
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Approve
+// @Summary		审批通过
+// @Description	通过等待审批的步骤, 审批人由调用方填写
+// @Tags		步骤
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		step path string true "步骤名称"
+// @Param		approval body types.SApprovalReq true "审批内容"
+// @Success		200 {object} base.IResponse[any]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/step/{step}/approve [post]
+func Approve(c *gin.Context) {
+	approval(c, true)
+}
+
+// Reject
+// @Summary		审批拒绝
+// @Description	拒绝等待审批的步骤, 步骤执行失败
+// @Tags		步骤
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task path string true "任务名称"
+// @Param		step path string true "步骤名称"
+// @Param		approval body types.SApprovalReq true "审批内容"
+// @Success		200 {object} base.IResponse[any]
+// @Failure		500 {object} base.IResponse[any]
+// @Router		/api/v1/task/{task}/step/{step}/reject [post]
+func Reject(c *gin.Context) {
+	approval(c, false)
+}
+
+func approval(c *gin.Context, approved bool) {
+	taskName := c.Param("task")
+	if taskName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("task does not exist")))
+		return
+	}
+	stepName := c.Param("step")
+	if stepName == "" {
+		base.Send(c, base.WithCode[any](base.CodeNoData).WithError(errors.New("step does not exist")))
+		return
+	}
+	var req = new(types.SApprovalReq)
+	if err := c.ShouldBind(req); err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	if err := service.Step(taskName, stepName).Approve(approved, req); err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	base.Send(c, base.WithCode[any](base.CodeSuccess))
+}
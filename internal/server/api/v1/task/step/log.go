@@ -2,6 +2,7 @@ package step
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,7 @@ import (
 // @Produce		application/json
 // @Param		task path string true "任务名称"
 // @Param		step path string true "步骤名称"
+// @Param		attempt query int false "执行次数, 仅返回该次执行的日志"
 // @Success		200 {object} base.IResponse[types.SStepLogsRes]
 // @Failure		500 {object} base.IResponse[any]
 // @Router		/api/v1/task/{task}/step/{step}/log [get]
@@ -64,6 +66,17 @@ func Log(c *gin.Context) {
 		return
 	}
 
+	if attempt := c.Query("attempt"); attempt != "" {
+		n, err := strconv.Atoi(attempt)
+		if err != nil {
+			base.Send(c, base.WithCode[any](base.CodeFailed).WithError(errors.New("invalid attempt")))
+			return
+		}
+		code, res, err := service.Step(taskName, stepName).AttemptLog(n)
+		base.Send(c, base.WithData(res).WithCode(code).WithError(err))
+		return
+	}
+
 	code, res, err := service.Step(taskName, stepName).Log()
 	base.Send(c, base.WithData(res).WithCode(code).WithError(err))
 }
@@ -11,13 +11,13 @@ import (
 
 // Manager
 // @Summary		管理
-// @Description	管理指定任务的指定步骤, 支持暂停、恢复、终止、超时暂停自动恢复
+// @Description	管理指定任务的指定步骤, 支持暂停、恢复、终止、超时暂停自动恢复、重新执行失败步骤、跳过待执行步骤
 // @Tags		步骤
 // @Accept		application/json
 // @Produce		application/json
 // @Param		task path string true "任务名称"
 // @Param		step path string true "步骤名称"
-// @Param		action query string false "操作项" Enums(paused,kill,pause,resume) default(paused)
+// @Param		action query string false "操作项" Enums(paused,kill,pause,resume,retry,skip) default(paused)
 // @Param		duration query string false "暂停多久, 如果没设置则需要手工恢复" default(1m)
 // @Success		200 {object} base.IResponse[any]
 // @Failure		500 {object} base.IResponse[any]
@@ -0,0 +1,35 @@
+package task
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/service"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Validate
+// @Summary		校验
+// @Description	静态校验任务并生成执行计划, 不创建任务
+// @Tags		任务
+// @Accept		application/json
+// @Produce		application/json
+// @Param		task body types.STaskReq true "任务内容"
+// @Success		200 {object} base.IResponse[types.STaskPlanRes]
+// @Failure		500 {object} base.IResponse[types.STaskPlanRes]
+// @Router		/api/v1/task/validate [post]
+func Validate(c *gin.Context) {
+	var req = new(types.STaskReq)
+	if err := c.ShouldBind(req); err != nil {
+		logx.Errorln(err)
+		base.Send(c, base.WithCode[any](base.CodeFailed).WithError(err))
+		return
+	}
+	plan := service.Validate(req)
+	if !plan.Valid {
+		base.Send(c, base.WithCode[*types.STaskPlanRes](base.CodeFailed).WithData(plan))
+		return
+	}
+	base.Send(c, base.WithData(plan))
+}
@@ -106,6 +106,8 @@ const (
 	CodePending
 	CodePaused
 	CodeSkipped
+	CodeWaiting
+	CodeQueued
 )
 
 // codeMessageMap 状态码对应的消息映射
@@ -117,6 +119,8 @@ var codeMessageMap = map[Code]string{
 	CodePending: "pending",
 	CodePaused:  "paused",
 	CodeSkipped: "skipped",
+	CodeWaiting: "waiting",
+	CodeQueued:  "queued",
 }
 
 // Response 响应结构体（更清晰的命名）
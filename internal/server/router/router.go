@@ -12,9 +12,11 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/busyster996/dagflow/internal/server/api/v1/event"
+	"github.com/busyster996/dagflow/internal/server/api/v1/node"
 	"github.com/busyster996/dagflow/internal/server/api/v1/pipeline"
 	"github.com/busyster996/dagflow/internal/server/api/v1/pipeline/build"
 	"github.com/busyster996/dagflow/internal/server/api/v1/task"
+	"github.com/busyster996/dagflow/internal/server/api/v1/task/run"
 	"github.com/busyster996/dagflow/internal/server/api/v1/task/step"
 	"github.com/busyster996/dagflow/internal/server/api/v1/task/workspace"
 	"github.com/busyster996/dagflow/internal/server/router/base"
@@ -75,6 +77,10 @@ func New() (*gin.Engine, error) {
 		// event
 		apiV1.GET("/event", event.Stream)
 
+		// node
+		apiV1.GET("/node", node.List)
+		apiV1.GET("/node/:node", node.Detail)
+
 		// pipeline
 		apiV1.GET("/pipeline", pipeline.List)
 		apiV1.POST("/pipeline", pipeline.Post)
@@ -92,10 +98,21 @@ func New() (*gin.Engine, error) {
 		// task
 		apiV1.GET("/task", task.List)
 		apiV1.POST("/task", task.Post)
+		apiV1.POST("/task/validate", task.Validate)
 		apiV1.GET("/task/:task", task.Detail)
 		apiV1.PUT("/task/:task", task.Manager)
 		apiV1.DELETE("/task/:task", task.Delete)
 		apiV1.GET("/task/:task/dump", task.Dump)
+		apiV1.POST("/task/:task/resume", task.Resume)
+		apiV1.GET("/task/:task/graph", task.Graph)
+
+		// run
+		apiV1.GET("/task/:task/runs", run.List)
+		apiV1.GET("/task/:task/runs/:run", run.Detail)
+		apiV1.GET("/task/:task/runs/:run/dump", run.Dump)
+		apiV1.GET("/task/:task/runs/:run/step", run.StepList)
+		apiV1.GET("/task/:task/runs/:run/step/:step", run.StepDetail)
+		apiV1.GET("/task/:task/runs/:run/step/:step/log", run.StepLog)
 
 		// workspace
 		apiV1.GET("/task/:task/workspace", workspace.Get)
@@ -107,6 +124,8 @@ func New() (*gin.Engine, error) {
 		apiV1.GET("/task/:task/step/:step", step.Detail)
 		apiV1.PUT("/task/:task/step/:step", step.Manager)
 		apiV1.GET("/task/:task/step/:step/log", step.Log)
+		apiV1.POST("/task/:task/step/:step/approve", step.Approve)
+		apiV1.POST("/task/:task/step/:step/reject", step.Reject)
 	}
 
 	// no method
@@ -20,7 +20,9 @@ import (
 	"github.com/tus/tusd/v2/pkg/filestore"
 	tusd "github.com/tus/tusd/v2/pkg/handler"
 
+	"github.com/busyster996/dagflow/internal/janitor"
 	"github.com/busyster996/dagflow/internal/server/router"
+	"github.com/busyster996/dagflow/internal/server/service"
 	"github.com/busyster996/dagflow/internal/server/tus/redislocker"
 	"github.com/busyster996/dagflow/internal/server/tus/redisstore"
 	"github.com/busyster996/dagflow/internal/utility"
@@ -62,6 +64,14 @@ func Start(ctx context.Context) error {
 		wg: new(sync.WaitGroup),
 	}
 	server.ctx, server.cancel = context.WithCancel(ctx)
+	// 调度等待前置任务的任务
+	if err := service.StartScheduler(server.ctx); err != nil {
+		logx.Errorln(err)
+		return err
+	}
+	// 定期清理过期的任务, 构建, 日志及上传文件
+	service.RegisterJanitor()
+	janitor.Start(server.ctx)
 	return server.startServer()
 }
 
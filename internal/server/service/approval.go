@@ -0,0 +1,54 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/pubsub"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/worker/event"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// Approve 审批等待中的步骤, 审批人由调用方填写, 仅作为审批记录
+func (ss *SStepService) Approve(approved bool, req *types.SApprovalReq) error {
+	db := storage.Task(ss.taskName).Step(ss.stepName)
+	step, err := db.Get()
+	if err != nil {
+		logx.Errorln("step approve", ss.taskName, ss.stepName, err)
+		return errors.New("step not found")
+	}
+	if *step.State != models.StateWaiting {
+		return errors.New("step is not waiting for approval")
+	}
+	req.Approver = strings.TrimSpace(req.Approver)
+	if req.Approver == "" {
+		return errors.New("approver is required")
+	}
+	if err = db.Approval().Insert(&models.SStepApproval{
+		Approved: models.Pointer(approved),
+		Approver: req.Approver,
+		Comment:  req.Comment,
+	}); err != nil {
+		logx.Errorln("step approve", ss.taskName, ss.stepName, err)
+		return errors.New("step has already been approved or rejected")
+	}
+
+	var e = &event.SEvent{
+		Type:    event.TypeRejected,
+		Task:    ss.taskName,
+		Step:    ss.stepName,
+		Message: fmt.Sprintf("by %s: %s", req.Approver, req.Comment),
+	}
+	if approved {
+		e.Type = event.TypeApproved
+	}
+	if err = pubsub.PublishEvent(e.String()); err != nil {
+		logx.Warnln("step approve", ss.taskName, ss.stepName, err)
+	}
+	return nil
+}
@@ -23,6 +23,10 @@ func ConvertState(state models.State) base.Code {
 		return base.CodeFailed
 	case models.StateSkipped:
 		return base.CodeSkipped
+	case models.StateWaiting:
+		return base.CodeWaiting
+	case models.StateQueued:
+		return base.CodeQueued
 	default:
 		return base.CodeNoData
 	}
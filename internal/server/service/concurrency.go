@@ -0,0 +1,97 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/pubsub"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 同一并发组同时只执行一个任务, 组内已有未结束任务时新任务的处理策略
+const (
+	ConcurrencyQueue  = "queue"
+	ConcurrencyCancel = "cancel"
+	ConcurrencyReject = "reject"
+)
+
+// groupMu 串行化并发组的准入判断
+var groupMu sync.Mutex
+
+func reviewConcurrency(task *types.STaskReq) error {
+	switch task.ConcurrencyPolicy {
+	case "", ConcurrencyQueue, ConcurrencyCancel, ConcurrencyReject:
+	default:
+		return fmt.Errorf("invalid concurrency policy %s, must be %s, %s or %s", task.ConcurrencyPolicy, ConcurrencyQueue, ConcurrencyCancel, ConcurrencyReject)
+	}
+	if task.ConcurrencyGroup == "" {
+		return nil
+	}
+	if !task.Delayed.IsZero() {
+		return errors.New("delayed can not be used with concurrencyGroup")
+	}
+	if task.ConcurrencyPolicy == "" {
+		task.ConcurrencyPolicy = ConcurrencyQueue
+	}
+	return nil
+}
+
+// admit 按并发策略处理同一并发组中未结束的任务, queue时直接排队, cancel时终止其他任务, reject时拒绝创建
+func (ts *STaskService) admit(group, policy string) error {
+	var others models.STasks
+	for _, v := range storage.GroupTasks(group) {
+		if v.Name != ts.name {
+			others = append(others, v)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	switch policy {
+	case ConcurrencyReject:
+		return fmt.Errorf("concurrency group %s is busy with task %s", group, others[0].Name)
+	case ConcurrencyCancel:
+		message := fmt.Sprintf("the task is cancelled by task %s in concurrency group %s", ts.name, group)
+		for _, v := range others {
+			if err := Task(v.Name).cancel(v, message); err != nil {
+				logx.Errorln("concurrency cancel", v.Name, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cancel 终止并发组中的任务, 排队中的任务直接标记为失败, 其他任务由所在节点强杀
+func (ts *STaskService) cancel(task *models.STask, message string) error {
+	if *task.State == models.StateQueued {
+		return ts.cancelUnscheduled(models.StateQueued, message)
+	}
+	if task.Node == "" {
+		// 尚未被节点领取
+		return storage.Task(ts.name).Update(&models.STaskUpdate{
+			Message:  message,
+			State:    models.Pointer(models.StateFailed),
+			OldState: task.State,
+			ETime:    models.Pointer(time.Now()),
+		})
+	}
+	return pubsub.PublishManager(task.Node, utility.JoinWithInvisibleChar(ts.name, "kill", "0"))
+}
+
+// cancelUnscheduled 取消尚未提交到节点的任务
+func (ts *STaskService) cancelUnscheduled(state models.State, message string) error {
+	return storage.Task(ts.name).Update(&models.STaskUpdate{
+		Message:  message,
+		State:    models.Pointer(models.StateFailed),
+		OldState: models.Pointer(state),
+		ETime:    models.Pointer(time.Now()),
+	})
+}
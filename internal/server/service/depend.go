@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/pubsub"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility"
+	"github.com/busyster996/dagflow/internal/worker/event"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 定期检查前置任务, 防止任务结束事件丢失
+const dependInterval = 10 * time.Second
+
+// dependMu 串行化前置任务的检查, 防止任务被重复提交
+var dependMu sync.Mutex
+
+// reviewDepends 校验前置任务, 前置任务须已存在且不能形成循环依赖
+func reviewDepends(task *types.STaskReq) error {
+	if len(task.DependsOnTasks) == 0 {
+		return nil
+	}
+	if !task.Delayed.IsZero() {
+		return errors.New("delayed can not be used with dependsOnTasks")
+	}
+	for _, depend := range task.DependsOnTasks {
+		switch depend.When = common.WhenConvert(depend.When); depend.When {
+		case common.WhenSuccess, common.WhenFailure, common.WhenAlways:
+		default:
+			return fmt.Errorf("invalid when %s of task %s, must be %s, %s or %s", depend.When, depend.Name, common.WhenSuccess, common.WhenFailure, common.WhenAlways)
+		}
+		if depend.Name == task.Name {
+			return fmt.Errorf("task %s can not depend on itself", task.Name)
+		}
+		if _, err := storage.Task(depend.Name).Get(); err != nil {
+			return fmt.Errorf("dependent task %s not found", depend.Name)
+		}
+	}
+	names := dependNames(task.DependsOnTasks)
+	if dup := utility.CheckDuplicate(names); dup != nil {
+		return fmt.Errorf("duplicate dependent tasks %v", dup)
+	}
+	return dependCycle(task.Name, names)
+}
+
+// dependCycle 沿尚未满足的前置任务查找, 判断是否回到当前任务
+func dependCycle(name string, depends []string) error {
+	var visited = make(map[string]bool)
+	var stack = slices.Clone(depends)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == name {
+			return fmt.Errorf("circular task dependency on %s", name)
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		for _, v := range storage.Task(current).Depend().List() {
+			if *v.State == models.StatePending {
+				stack = append(stack, v.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func dependNames(depends []*types.STaskDependReq) []string {
+	var names []string
+	for _, depend := range depends {
+		names = append(names, depend.Name)
+	}
+	return names
+}
+
+func (ts *STaskService) saveDepends(depends []*types.STaskDependReq) error {
+	var res models.STaskDepends
+	for _, depend := range depends {
+		res = append(res, &models.STaskDepend{
+			Name:    depend.Name,
+			When:    depend.When,
+			State:   models.Pointer(models.StatePending),
+			Message: fmt.Sprintf("waiting for task %s", depend.Name),
+		})
+	}
+	return storage.Task(ts.name).Depend().Insert(res...)
+}
+
+// waitingDepends 是否仍在等待前置任务
+func (ts *STaskService) waitingDepends() bool {
+	return slices.ContainsFunc(storage.Task(ts.name).Depend().List(), func(depend *models.STaskDepend) bool {
+		return *depend.State == models.StatePending
+	})
+}
+
+// resolveDepends 检查前置任务的结束状态, 全部满足时提交任务, 任一不满足时跳过任务
+func (ts *STaskService) resolveDepends() error {
+	dependMu.Lock()
+	defer dependMu.Unlock()
+
+	db := storage.Task(ts.name)
+	task, err := db.Get()
+	if err != nil {
+		return err
+	}
+	if *task.State != models.StatePending {
+		return nil
+	}
+	var waiting bool
+	for _, depend := range db.Depend().List() {
+		if *depend.State != models.StatePending {
+			continue
+		}
+		state, message := dependState(depend)
+		if state == models.StatePending {
+			waiting = true
+			continue
+		}
+		if err = db.Depend().Update(depend.Name, state, message); err != nil {
+			return err
+		}
+		if state == models.StateSkipped {
+			return ts.skipDepends(message)
+		}
+	}
+	if waiting {
+		return nil
+	}
+	logx.Infoln("task depends resolved", ts.name)
+	return submit(db, task, "the task is waiting to be scheduled for execution")
+}
+
+// dependState 前置任务结束后, 满足条件为stopped, 不满足为skipped, 未结束为pending
+func dependState(depend *models.STaskDepend) (models.State, string) {
+	task, err := storage.Task(depend.Name).Get()
+	if err != nil {
+		return models.StateSkipped, fmt.Sprintf("dependent task %s not found", depend.Name)
+	}
+	state := *task.State
+	switch state {
+	case models.StateStopped, models.StateFailed, models.StateSkipped:
+	default:
+		return models.StatePending, fmt.Sprintf("waiting for task %s", depend.Name)
+	}
+	var satisfied bool
+	switch depend.When {
+	case common.WhenAlways:
+		satisfied = true
+	case common.WhenFailure:
+		satisfied = state == models.StateFailed
+	default:
+		satisfied = state == models.StateStopped
+	}
+	if !satisfied {
+		return models.StateSkipped, fmt.Sprintf("dependent task %s is %s, required %s", depend.Name, models.StateMap[state], depend.When)
+	}
+	return models.StateStopped, fmt.Sprintf("dependent task %s is %s", depend.Name, models.StateMap[state])
+}
+
+// skipDepends 前置任务不满足条件时跳过任务及其所有步骤
+func (ts *STaskService) skipDepends(message string) error {
+	db := storage.Task(ts.name)
+	for _, name := range db.StepNameList(storage.All) {
+		if err := db.Step(name).Update(&models.SStepUpdate{
+			State:    models.Pointer(models.StateSkipped),
+			OldState: models.Pointer(models.StatePending),
+			Code:     models.Pointer(common.ExecCodeSkipped),
+			Message:  message,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := db.Update(&models.STaskUpdate{
+		State:    models.Pointer(models.StateSkipped),
+		OldState: models.Pointer(models.StatePending),
+		Message:  message,
+		ETime:    models.Pointer(time.Now()),
+	}); err != nil {
+		return err
+	}
+	e := &event.SEvent{Type: event.TypeSkip, Task: ts.name, Message: message}
+	if err := pubsub.PublishEvent(e.String()); err != nil {
+		logx.Warnln("task skip", ts.name, err)
+	}
+	return nil
+}
+
+func dependRes(depends models.STaskDepends) []*types.STaskDependRes {
+	var res []*types.STaskDependRes
+	for _, depend := range depends {
+		res = append(res, &types.STaskDependRes{
+			Name:    depend.Name,
+			When:    depend.When,
+			State:   models.StateMap[*depend.State],
+			Message: depend.Message,
+		})
+	}
+	return res
+}
+
+// StartScheduler 调度等待前置任务的任务, 任务结束时检查其后置任务, 并定期检查所有等待中的任务
+func StartScheduler(ctx context.Context) error {
+	var trigger = make(chan struct{}, 1)
+	if err := pubsub.SubscribeEvent(ctx, func(data string) {
+		var e event.SEvent
+		if err := json.Unmarshal([]byte(data), &e); err != nil || e.Step != "" {
+			return
+		}
+		switch e.Type {
+		case event.TypeStop, event.TypeSkip:
+			select {
+			case trigger <- struct{}{}:
+			default:
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(dependInterval)
+		defer ticker.Stop()
+		for {
+			// 按创建顺序检查, 前置任务被跳过时后置任务在同一轮中跟随
+			for _, name := range storage.DependTasks() {
+				if err := Task(name).resolveDepends(); err != nil {
+					logx.Errorln("task resolve depends", name, err)
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-trigger:
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// dependMessage 等待前置任务时的任务消息
+func dependMessage(names []string) string {
+	return fmt.Sprintf("the task is waiting for tasks %s", strings.Join(names, ", "))
+}
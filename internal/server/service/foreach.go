@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+// reviewForeach 校验foreach步骤, 列表须来自直接依赖的上游步骤的输出
+func reviewForeach(step *types.SStepReq) error {
+	if step.Foreach == "" {
+		return nil
+	}
+	if len(step.Matrix) != 0 {
+		return errors.New("foreach can not be used with matrix")
+	}
+	if step.MaxParallel < 0 {
+		return errors.New("maxParallel must not be negative")
+	}
+	k := strings.LastIndex(step.Foreach, ".")
+	if k <= 0 || k == len(step.Foreach)-1 {
+		return fmt.Errorf("invalid foreach %q, must be <step>.<output>", step.Foreach)
+	}
+	if from := step.Foreach[:k]; !slices.Contains(step.Depends, from) {
+		return fmt.Errorf("foreach step %s must depend on step %s", step.Name, from)
+	}
+	return nil
+}
+
+// stepGroup 步骤所属的矩阵步骤或foreach步骤
+func stepGroup(step *models.SStep) string {
+	if parent := step.Foreach.Data().Parent; parent != "" {
+		return parent
+	}
+	return step.Matrix.Data().Group
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+const (
+	GraphFormatJSON    = "json"
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+)
+
+// 各状态在图中的填充颜色
+var graphColors = map[string]string{
+	models.StateMap[models.StateStopped]: "#a6e3a1",
+	models.StateMap[models.StateRunning]: "#89b4fa",
+	models.StateMap[models.StateFailed]:  "#f38ba8",
+	models.StateMap[models.StateUnknown]: "#bac2de",
+	models.StateMap[models.StatePending]: "#e6e9ef",
+	models.StateMap[models.StatePaused]:  "#f9e2af",
+	models.StateMap[models.StateSkipped]: "#ccd0da",
+	models.StateMap[models.StateWaiting]: "#fab387",
+	models.StateMap[models.StateQueued]:  "#cba6f7",
+}
+
+// Graph 任务拓扑, 包含步骤状态、耗时、层级及按实际耗时计算的关键路径
+func (ts *STaskService) Graph() (*types.STaskGraphRes, error) {
+	db := storage.Task(ts.name)
+	task, err := db.Get()
+	if err != nil {
+		logx.Errorln("task graph", ts.name, err)
+		return nil, errors.New("task not found")
+	}
+	steps := db.StepList(storage.All)
+	if steps == nil {
+		return nil, errors.New("steps not found")
+	}
+
+	var deps = make(map[string][]string, len(steps))
+	var nodes = make(map[string]*types.SGraphNodeRes, len(steps))
+	for _, step := range steps {
+		deps[step.Name] = db.Step(step.Name).Depend().List()
+		nodes[step.Name] = &types.SGraphNodeRes{
+			Name:     step.Name,
+			State:    models.StateMap[*step.State],
+			Node:     task.Node,
+			Duration: stepDuration(step),
+			Time: &types.STimeRes{
+				Start: step.STimeStr(),
+				End:   step.ETimeStr(),
+			},
+		}
+	}
+	graph, err := dagcuter.NewGraph(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &types.STaskGraphRes{
+		Levels: graph.Levels(),
+	}
+	for level, names := range res.Levels {
+		for _, name := range names {
+			nodes[name].Level = level
+		}
+	}
+	res.CriticalPath, res.Duration = graph.CriticalPath(func(name string) time.Duration {
+		return nodes[name].Duration
+	})
+	var critical = make(map[string]string, len(res.CriticalPath))
+	for k, name := range res.CriticalPath {
+		nodes[name].Critical = true
+		if k+1 < len(res.CriticalPath) {
+			critical[name] = res.CriticalPath[k+1]
+		}
+	}
+	for _, name := range graph.Nodes() {
+		res.Nodes = append(res.Nodes, nodes[name])
+	}
+	for _, edge := range graph.Edges() {
+		res.Edges = append(res.Edges, &types.SGraphEdgeRes{
+			From:     edge[0],
+			To:       edge[1],
+			Critical: critical[edge[0]] == edge[1],
+		})
+	}
+	return res, nil
+}
+
+// stepDuration 步骤耗时, 运行中的步骤计算到当前时间
+func stepDuration(step *models.SStep) time.Duration {
+	if step.STime == nil {
+		return 0
+	}
+	end := time.Now()
+	if *step.State != models.StateRunning && *step.State != models.StatePaused && *step.State != models.StateWaiting {
+		if step.ETime == nil || step.ETime.Before(*step.STime) {
+			return 0
+		}
+		end = *step.ETime
+	}
+	return end.Sub(*step.STime).Truncate(time.Millisecond)
+}
+
+// RenderGraph 将任务拓扑渲染为dot或mermaid文本
+func RenderGraph(graph *types.STaskGraphRes, format string) (string, error) {
+	switch format {
+	case GraphFormatDOT:
+		return renderDOT(graph), nil
+	case GraphFormatMermaid:
+		return renderMermaid(graph), nil
+	default:
+		return "", fmt.Errorf("unsupported graph format %s", format)
+	}
+}
+
+func renderDOT(graph *types.STaskGraphRes) string {
+	var sb strings.Builder
+	sb.WriteString("digraph task {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\"];\n")
+	for _, node := range graph.Nodes {
+		_, _ = fmt.Fprintf(&sb, "  %q [label=%q, fillcolor=%q", node.Name, graphLabel(node, "\n"), graphColors[node.State])
+		if node.Critical {
+			sb.WriteString(", penwidth=2")
+		}
+		sb.WriteString("];\n")
+	}
+	for _, edge := range graph.Edges {
+		_, _ = fmt.Fprintf(&sb, "  %q -> %q", edge.From, edge.To)
+		if edge.Critical {
+			sb.WriteString(" [penwidth=2, color=\"#d20f39\"]")
+		}
+		sb.WriteString(";\n")
+	}
+	for _, level := range graph.Levels {
+		sb.WriteString("  { rank=same;")
+		for _, name := range level {
+			_, _ = fmt.Fprintf(&sb, " %q;", name)
+		}
+		sb.WriteString(" }\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func renderMermaid(graph *types.STaskGraphRes) string {
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	// 步骤名称可能包含mermaid保留字符, 统一使用序号作为节点ID
+	var ids = make(map[string]string, len(graph.Nodes))
+	for k, node := range graph.Nodes {
+		ids[node.Name] = fmt.Sprintf("n%d", k)
+		label := strings.ReplaceAll(graphLabel(node, "<br/>"), `"`, "#quot;")
+		_, _ = fmt.Fprintf(&sb, "  %s[\"%s\"]:::%s\n", ids[node.Name], label, node.State)
+	}
+	var critical []string
+	for k, edge := range graph.Edges {
+		_, _ = fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+		if edge.Critical {
+			critical = append(critical, fmt.Sprint(k))
+		}
+	}
+	for _, state := range slices.Sorted(maps.Keys(graphColors)) {
+		_, _ = fmt.Fprintf(&sb, "  classDef %s fill:%s\n", state, graphColors[state])
+	}
+	if len(critical) > 0 {
+		_, _ = fmt.Fprintf(&sb, "  linkStyle %s stroke:#d20f39,stroke-width:2px\n", strings.Join(critical, ","))
+	}
+	return sb.String()
+}
+
+func graphLabel(node *types.SGraphNodeRes, sep string) string {
+	return strings.Join([]string{node.Name, node.State, node.Duration.String()}, sep)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/server/types"
+)
+
+// taskHandlers 任务处理步骤, 条件处理步骤在finally之前执行
+func taskHandlers(task *types.STaskReq) types.SStepsReq {
+	var res types.SStepsReq
+	for _, handler := range []struct {
+		name  string
+		steps types.SStepsReq
+	}{
+		{common.HandlerSuccess, task.OnSuccess},
+		{common.HandlerFailure, task.OnFailure},
+		{common.HandlerFinally, task.Finally},
+	} {
+		for _, step := range handler.steps {
+			step.Handler = handler.name
+			res = append(res, step)
+		}
+	}
+	return res
+}
+
+// reviewHandlers 校验处理步骤, 处理步骤按声明顺序执行, 不支持依赖, 矩阵及foreach, 名称不能与其他步骤重复
+func (ts *STaskService) reviewHandlers(task *types.STaskReq) (types.SStepsReq, error) {
+	handlers := taskHandlers(task)
+	for _, step := range handlers {
+		if len(step.Depends) != 0 {
+			return nil, fmt.Errorf("%s step %s can not have depends", step.Handler, step.Name)
+		}
+		if len(step.Matrix) != 0 || step.Foreach != "" {
+			return nil, fmt.Errorf("%s step %s can not use matrix or foreach", step.Handler, step.Name)
+		}
+	}
+	if err := ts.uniqStepsName(slices.Concat(task.Step, handlers)); err != nil {
+		return nil, err
+	}
+	return handlers, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/multierr"
+
+	"github.com/busyster996/dagflow/internal/janitor"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 单次清理最多删除的任务数
+const janitorBatch = 500
+
+// RegisterJanitor 注册API节点的清理项: 过期任务, 流水线构建, 历史执行记录, 步骤日志及未完成的上传
+func RegisterJanitor() {
+	janitor.Register("task", cleanTasks)
+	janitor.Register("build", cleanBuilds)
+	janitor.Register("run", cleanRuns)
+	janitor.Register("log", cleanLogs)
+	janitor.Register("upload", cleanUploads)
+}
+
+// cleanTasks 删除结束时间早于 gc_task_ttl 的任务, 开启 gc_task_archive 时先归档
+func cleanTasks(ctx context.Context) (res models.SReclaim, err error) {
+	ttl := viper.GetDuration("gc_task_ttl")
+	if ttl <= 0 {
+		return
+	}
+	archive := viper.GetBool("gc_task_archive")
+	for _, name := range storage.ExpiredTasks(time.Now().Add(-ttl), janitorBatch) {
+		if ctx.Err() != nil {
+			break
+		}
+		if archive {
+			if _err := archiveTask(name); _err != nil {
+				// 归档失败时保留任务
+				err = multierr.Append(err, _err)
+				continue
+			}
+			res.Archived++
+		}
+		if _err := storage.Task(name).ClearAll(); _err != nil {
+			err = multierr.Append(err, _err)
+			continue
+		}
+		logx.Infoln("janitor removed task", name)
+		res.Tasks++
+	}
+	return
+}
+
+// cleanBuilds 每个流水线仅保留最近 gc_build_keep 次构建
+func cleanBuilds(ctx context.Context) (res models.SReclaim, err error) {
+	keep := viper.GetInt("gc_build_keep")
+	if keep <= 0 {
+		return
+	}
+	pipelines, _ := storage.PipelineList(-1, 0, "")
+	for _, pipeline := range pipelines {
+		for _, name := range storage.Pipeline(pipeline.Name).Build().Expired(keep) {
+			if ctx.Err() != nil {
+				return
+			}
+			// 任务删除时同时删除构建记录
+			if _err := storage.Task(name).ClearAll(); _err != nil {
+				err = multierr.Append(err, _err)
+				continue
+			}
+			logx.Infoln("janitor removed build", pipeline.Name, name)
+			res.Builds++
+		}
+	}
+	return
+}
+
+// cleanRuns 每个任务仅保留最近 gc_task_runs 次历史执行记录
+func cleanRuns(ctx context.Context) (res models.SReclaim, err error) {
+	keep := viper.GetInt("gc_task_runs")
+	if keep <= 0 {
+		return
+	}
+	for _, name := range storage.ExpiredRuns(keep) {
+		if ctx.Err() != nil {
+			break
+		}
+		runs, size, _err := storage.Task(name).Run().Trim(keep)
+		if _err != nil {
+			err = multierr.Append(err, _err)
+			continue
+		}
+		res.Runs += runs
+		res.Bytes += size
+	}
+	return
+}
+
+// cleanLogs 已结束任务的步骤日志仅保留最后 gc_step_log_lines 行
+func cleanLogs(ctx context.Context) (res models.SReclaim, err error) {
+	keep := viper.GetInt64("gc_step_log_lines")
+	if keep <= 0 {
+		return
+	}
+	for _, step := range storage.OversizedLogs(keep) {
+		if ctx.Err() != nil {
+			break
+		}
+		lines, size, _err := storage.Task(step.TaskName).Step(step.StepName).Log().Trim(keep)
+		if _err != nil {
+			err = multierr.Append(err, _err)
+			continue
+		}
+		res.LogLines += lines
+		res.Bytes += size
+	}
+	return
+}
+
+// cleanUploads 删除修改时间早于 gc_upload_ttl 的上传临时文件, 包括已完成复制和中断的上传
+func cleanUploads(ctx context.Context) (res models.SReclaim, err error) {
+	ttl := viper.GetDuration("gc_upload_ttl")
+	if ttl <= 0 {
+		return
+	}
+	root := filepath.Join(viper.GetString("workspace_dir"), ".tusd")
+	before := time.Now().Add(-ttl)
+	var dirs []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || ctx.Err() != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+		if !janitor.Expired(path, before) {
+			return nil
+		}
+		size, err := janitor.RemoveAll(path)
+		if err != nil {
+			logx.Warnln("janitor", path, err)
+			return nil
+		}
+		if strings.HasSuffix(path, ".info") {
+			res.Uploads++
+		}
+		res.Bytes += size
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	// 从最深的目录开始删除空目录
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+	return
+}
+
+// archiveTask 将任务及步骤日志写入 <root_dir>/archive/<task>.json.gz
+func archiveTask(name string) error {
+	snapshot, err := snapshotTask(name)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(viper.GetString("root_dir"), "archive")
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(dir, name+".json.gz"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return snapshot.encode(file)
+}
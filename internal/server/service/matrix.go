@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/segmentio/ksuid"
+
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/utility"
+)
+
+// 单个矩阵步骤最多展开的实例数
+const maxMatrixInstances = 1024
+
+// 矩阵参数轴名称只允许字母,数字及下划线
+var matrixAxisReg = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// expandMatrix 将声明了matrix的步骤展开为多个实例, 依赖该步骤的步骤改为依赖所有实例
+func (ts *STaskService) expandMatrix(steps types.SStepsReq) (types.SStepsReq, error) {
+	var res types.SStepsReq
+	var groups = make(map[string][]string)
+	for _, step := range steps {
+		if len(step.Matrix) == 0 {
+			res = append(res, step)
+			continue
+		}
+		instances, err := expandMatrixStep(step)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %w", step.Name, err)
+		}
+		for _, instance := range instances {
+			groups[step.Name] = append(groups[step.Name], instance.Name)
+		}
+		res = append(res, instances...)
+	}
+	if len(groups) == 0 {
+		return steps, nil
+	}
+
+	// 依赖矩阵步骤的步骤需等待所有实例完成
+	for _, step := range res {
+		var depends []string
+		for _, depend := range step.Depends {
+			if names, ok := groups[depend]; ok {
+				depends = append(depends, names...)
+				continue
+			}
+			depends = append(depends, depend)
+		}
+		step.Depends = utility.RemoveDuplicate(depends)
+	}
+	return res, ts.uniqStepsName(res)
+}
+
+func expandMatrixStep(step *types.SStepReq) (types.SStepsReq, error) {
+	step.Name = reg.ReplaceAllString(step.Name, "")
+	if step.Name == "" {
+		step.Name = ksuid.New().String()
+	}
+	if step.MaxParallel < 0 {
+		return nil, fmt.Errorf("maxParallel must not be negative")
+	}
+
+	var axes = make([]string, 0, len(step.Matrix))
+	var total = 1
+	for axis, values := range step.Matrix {
+		if !matrixAxisReg.MatchString(axis) {
+			return nil, fmt.Errorf("invalid matrix axis %q", axis)
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix axis %s has no values", axis)
+		}
+		if dup := utility.CheckDuplicate(values); dup != nil {
+			return nil, fmt.Errorf("matrix axis %s has duplicate values %v", axis, dup)
+		}
+		total *= len(values)
+		if total > maxMatrixInstances {
+			return nil, fmt.Errorf("matrix expands to more than %d instances", maxMatrixInstances)
+		}
+		axes = append(axes, axis)
+	}
+	slices.Sort(axes)
+
+	// 按参数轴名称排序后做笛卡尔积, 保证实例名称及顺序稳定
+	var combinations = []map[string]string{{}}
+	for _, axis := range axes {
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range step.Matrix[axis] {
+				values := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					values[k] = v
+				}
+				values[axis] = value
+				next = append(next, values)
+			}
+		}
+		combinations = next
+	}
+
+	var res = make(types.SStepsReq, 0, len(combinations))
+	var used = make(map[string]bool, len(combinations))
+	for k, values := range combinations {
+		instance := *step
+		instance.Name = matrixInstanceName(step.Name, axes, values, k)
+		// 参数值去除非法字符后可能重名, 重名时追加序号
+		for n, name := k, instance.Name; used[instance.Name]; n++ {
+			instance.Name = fmt.Sprintf("%s-%d", name, n)
+		}
+		used[instance.Name] = true
+		instance.Depends = slices.Clone(step.Depends)
+		instance.Env = slices.Clone(step.Env)
+		for _, axis := range axes {
+			instance.Env = append(instance.Env, &types.SEnv{
+				Name:  matrixEnvName(axis),
+				Value: values[axis],
+			})
+		}
+		instance.Instance = &types.SMatrixInstance{
+			Group:  step.Name,
+			Values: values,
+		}
+		res = append(res, &instance)
+	}
+	return res, nil
+}
+
+// matrixInstanceName 实例名称由步骤名称及各参数值组成, 参数值不可用时使用序号
+func matrixInstanceName(name string, axes []string, values map[string]string, index int) string {
+	var parts = []string{name}
+	for _, axis := range axes {
+		value := reg.ReplaceAllString(values[axis], "")
+		if value == "" {
+			return fmt.Sprintf("%s-%d", name, index)
+		}
+		parts = append(parts, value)
+	}
+	return strings.Join(parts, "-")
+}
+
+// matrixEnvName 矩阵参数对应的环境变量名称
+func matrixEnvName(axis string) string {
+	return "MATRIX_" + strings.ToUpper(axis)
+}
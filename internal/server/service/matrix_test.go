@@ -0,0 +1,142 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/busyster996/dagflow/internal/server/types"
+)
+
+func TestExpandMatrix(t *testing.T) {
+	tests := []struct {
+		name      string
+		steps     types.SStepsReq
+		wantNames []string
+		// wantDeps 展开后各步骤的依赖
+		wantDeps map[string][]string
+		wantErr  bool
+	}{
+		{
+			name: "without matrix",
+			steps: types.SStepsReq{
+				{Name: "a"},
+				{Name: "b", Depends: []string{"a"}},
+			},
+			wantNames: []string{"a", "b"},
+			wantDeps:  map[string][]string{"b": {"a"}},
+		},
+		{
+			name: "cartesian product sorted by axis",
+			steps: types.SStepsReq{
+				{Name: "build", Matrix: map[string][]string{
+					"os":   {"linux", "darwin"},
+					"arch": {"amd64", "arm64"},
+				}},
+				{Name: "release", Depends: []string{"build"}},
+			},
+			wantNames: []string{
+				"build-amd64-linux", "build-amd64-darwin",
+				"build-arm64-linux", "build-arm64-darwin",
+				"release",
+			},
+			wantDeps: map[string][]string{
+				"release": {"build-amd64-linux", "build-amd64-darwin", "build-arm64-linux", "build-arm64-darwin"},
+			},
+		},
+		{
+			name: "colliding names are suffixed",
+			steps: types.SStepsReq{
+				{Name: "test", Matrix: map[string][]string{
+					"go": {"1.x", "1 x", "1/x"},
+				}},
+			},
+			wantNames: []string{"test-1.x", "test-1x", "test-1x-2"},
+		},
+		{
+			name: "empty value uses index",
+			steps: types.SStepsReq{
+				{Name: "test", Matrix: map[string][]string{
+					"v": {"a", "!"},
+				}},
+			},
+			wantNames: []string{"test-a", "test-1"},
+		},
+		{
+			name: "instance collides with another step",
+			steps: types.SStepsReq{
+				{Name: "test-a"},
+				{Name: "test", Matrix: map[string][]string{"v": {"a"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid axis",
+			steps: types.SStepsReq{
+				{Name: "test", Matrix: map[string][]string{"1v": {"a"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate values",
+			steps: types.SStepsReq{
+				{Name: "test", Matrix: map[string][]string{"v": {"a", "a"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative max parallel",
+			steps: types.SStepsReq{
+				{Name: "test", MaxParallel: -1, Matrix: map[string][]string{"v": {"a"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Task("matrix").expandMatrix(tt.steps)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expandMatrix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var names []string
+			for _, step := range res {
+				names = append(names, step.Name)
+				if want, ok := tt.wantDeps[step.Name]; ok && !slices.Equal(step.Depends, want) {
+					t.Errorf("step %s depends = %v, want %v", step.Name, step.Depends, want)
+				}
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestExpandMatrixEnv(t *testing.T) {
+	res, err := Task("matrix").expandMatrix(types.SStepsReq{
+		{
+			Name:   "test",
+			Env:    []*types.SEnv{{Name: "A", Value: "1"}},
+			Matrix: map[string][]string{"os": {"linux"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 {
+		t.Fatalf("got %d instances, want 1", len(res))
+	}
+	instance := res[0]
+	if instance.Instance == nil || instance.Instance.Group != "test" || instance.Instance.Values["os"] != "linux" {
+		t.Errorf("instance = %+v", instance.Instance)
+	}
+	var env = make(map[string]string)
+	for _, v := range instance.Env {
+		env[v.Name] = v.Value
+	}
+	if env["A"] != "1" || env["MATRIX_OS"] != "linux" {
+		t.Errorf("env = %v", env)
+	}
+}
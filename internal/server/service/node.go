@@ -0,0 +1,78 @@
+package service
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// NodeList 所有节点的步骤预算及清理统计
+func NodeList() types.SNodesRes {
+	var res = types.SNodesRes{}
+	for _, node := range storage.NodeList() {
+		res = append(res, nodeRes(node))
+	}
+	return res
+}
+
+// NodeDetail 指定节点的步骤预算及清理统计
+func NodeDetail(name string) (*types.SNodeRes, error) {
+	node, err := storage.Node(name).Get()
+	if err != nil {
+		logx.Errorln("node detail", name, err)
+		return nil, errors.New("node not found")
+	}
+	return nodeRes(node), nil
+}
+
+func nodeRes(node *models.SNode) *types.SNodeRes {
+	budget := node.Budget.Data()
+	res := &types.SNodeRes{
+		Name: node.Name,
+		Budget: &types.SBudgetRes{
+			Size: budget.Size,
+			Used: budget.Used,
+		},
+		UpdateAt: node.UpdatedAt,
+	}
+	for _, v := range budget.Holders {
+		res.Budget.Holders = append(res.Budget.Holders, budgetHolderRes(v))
+	}
+	for _, v := range budget.Waiters {
+		res.Budget.Waiters = append(res.Budget.Waiters, budgetHolderRes(v))
+	}
+	if janitor := node.Janitor.Data(); !janitor.LastRun.IsZero() {
+		res.Janitor = &types.SJanitorRes{
+			LastRun: janitor.LastRun,
+			Last:    reclaimRes(janitor.Last),
+			Total:   reclaimRes(janitor.Total),
+		}
+	}
+	return res
+}
+
+func reclaimRes(reclaim models.SReclaim) *types.SReclaimRes {
+	return &types.SReclaimRes{
+		Tasks:    reclaim.Tasks,
+		Archived: reclaim.Archived,
+		Builds:   reclaim.Builds,
+		Runs:     reclaim.Runs,
+		LogLines: reclaim.LogLines,
+		Uploads:  reclaim.Uploads,
+		Dirs:     reclaim.Dirs,
+		Bytes:    reclaim.Bytes,
+	}
+}
+
+func budgetHolderRes(holder *models.SBudgetHolder) *types.SBudgetHolderRes {
+	return &types.SBudgetHolderRes{
+		Task:   holder.Task,
+		Step:   holder.Step,
+		Weight: holder.Weight,
+		Mutex:  holder.Mutex,
+		Since:  holder.Since,
+	}
+}
@@ -210,12 +210,7 @@ func (p *SPipelineService) buildRun(name string, param map[string]any) error {
 		logx.Errorln("pipeline build run", p.name, err)
 		return err
 	}
-	defer func() {
-		if err != nil {
-			_ = storage.Pipeline(p.name).Build().Remove(taskReq.Name)
-		}
-	}()
-	// 自动生成任务名称
+	// 自动生成任务名称, 构建记录在任务创建成功后写入, 重新构建失败时保留原有记录
 	taskReq.Name = name
 	err = Task(p.name).Create(taskReq)
 	if err != nil {
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// reviewRetryPolicy 校验抖动策略、重试条件正则及表达式
+func reviewRetryPolicy(policy *types.SRetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	switch policy.Jitter {
+	case "", dagcuter.JitterFull, dagcuter.JitterEqual:
+	default:
+		return fmt.Errorf("invalid retry jitter %s, must be %s or %s", policy.Jitter, dagcuter.JitterFull, dagcuter.JitterEqual)
+	}
+	if policy.RetryOn != nil {
+		if policy.RetryOn.LogLines < 0 {
+			return errors.New("retryOn logLines can not be negative")
+		}
+		if _, err := regexp.Compile(policy.RetryOn.Pattern); err != nil {
+			return fmt.Errorf("invalid retryOn pattern: %v", err)
+		}
+	}
+	if policy.RetryIf != "" {
+		if _, err := expr.Compile(policy.RetryIf, expr.AsBool(), expr.AllowUndefinedVariables()); err != nil {
+			return fmt.Errorf("invalid retryIf expression: %v", err)
+		}
+	}
+	return nil
+}
+
+func retryPolicyModel(policy *types.SRetryPolicy) models.SRetryPolicy {
+	res := models.SRetryPolicy{
+		Interval:    policy.Interval,
+		MaxAttempts: policy.MaxAttempts,
+		MaxInterval: policy.MaxInterval,
+		Multiplier:  policy.Multiplier,
+		Jitter:      policy.Jitter,
+		RetryIf:     policy.RetryIf,
+	}
+	if policy.RetryOn != nil {
+		res.RetryOn = &models.SRetryOn{
+			ExitCodes: policy.RetryOn.ExitCodes,
+			Timeout:   policy.RetryOn.Timeout,
+			Pattern:   policy.RetryOn.Pattern,
+			LogLines:  policy.RetryOn.LogLines,
+		}
+	}
+	return res
+}
+
+func retryPolicyRes(policy models.SRetryPolicy) *types.SRetryPolicy {
+	res := &types.SRetryPolicy{
+		Interval:    policy.Interval,
+		MaxAttempts: policy.MaxAttempts,
+		MaxInterval: policy.MaxInterval,
+		Multiplier:  policy.Multiplier,
+		Jitter:      policy.Jitter,
+		RetryIf:     policy.RetryIf,
+	}
+	if policy.RetryOn != nil {
+		res.RetryOn = &types.SRetryOn{
+			ExitCodes: policy.RetryOn.ExitCodes,
+			Timeout:   policy.RetryOn.Timeout,
+			Pattern:   policy.RetryOn.Pattern,
+			LogLines:  policy.RetryOn.LogLines,
+		}
+	}
+	return res
+}
+
+func attemptRes(attempt *models.SStepAttempt) *types.SAttemptRes {
+	res := &types.SAttemptRes{
+		Attempt: attempt.Attempt,
+		State:   models.StateMap[*attempt.State],
+		Code:    attempt.Code.Int64(),
+		Message: attempt.Message,
+		Time: &types.STimeRes{
+			Start: attemptTime(attempt.STime),
+			End:   attemptTime(attempt.ETime),
+		},
+	}
+	if attempt.LogStart != nil {
+		res.LogStart = *attempt.LogStart
+	}
+	if attempt.LogEnd != nil {
+		res.LogEnd = *attempt.LogEnd
+	}
+	return res
+}
+
+func attemptTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// AttemptLog 指定执行次数的日志
+func (ss *SStepService) AttemptLog(n int) (base.Code, types.SStepLogsRes, error) {
+	stepStorage := storage.Task(ss.taskName).Step(ss.stepName)
+	attempt, err := stepStorage.Attempt().Get(n)
+	if err != nil {
+		logx.Errorln("step attempt log", ss.taskName, ss.stepName, n, err)
+		return base.CodeNoData, nil, fmt.Errorf("attempt %d not found", n)
+	}
+	if attempt.LogStart == nil {
+		return base.CodeNoData, nil, fmt.Errorf("attempt %d has no log", n)
+	}
+	// 执行中的尝试尚无结束行号, 返回起始行之后的所有日志
+	var end int64 = math.MaxInt64
+	if attempt.LogEnd != nil {
+		end = *attempt.LogEnd
+	}
+	var res types.SStepLogsRes
+	for _, v := range stepStorage.Log().Range(*attempt.LogStart, end) {
+		if v.Content == common.ExecConsoleStart || v.Content == common.ExecConsoleDone {
+			continue
+		}
+		res = append(res, &types.SStepLogRes{
+			Timestamp: v.Timestamp,
+			Line:      *v.Line,
+			Content:   v.Content,
+		})
+	}
+	return ConvertState(*attempt.State), res, errors.New(attempt.Message)
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/expr-lang/expr"
 	"github.com/pkg/errors"
 	"github.com/segmentio/ksuid"
 	"gorm.io/datatypes"
@@ -76,6 +77,25 @@ func (ss *SStepService) review(step *types.SStepReq) error {
 		return fmt.Errorf("duplicate key %v", dup)
 	}
 
+	// 校验执行条件表达式
+	switch when := common.WhenConvert(step.When); when {
+	case common.WhenSuccess, common.WhenFailure, common.WhenAlways:
+	default:
+		if _, err := expr.Compile(when, expr.AsBool(), expr.AllowUndefinedVariables()); err != nil {
+			return fmt.Errorf("invalid when expression: %v", err)
+		}
+	}
+
+	if err := reviewRetryPolicy(step.RetryPolicy); err != nil {
+		return err
+	}
+	if step.Weight < 0 {
+		return errors.New("weight can not be negative")
+	}
+	if err := reviewForeach(step); err != nil {
+		return err
+	}
+
 	step.Depends = utility.RemoveDuplicate(step.Depends)
 	return nil
 }
@@ -88,16 +108,24 @@ func (ss *SStepService) saveStep(seqNo int64, step *types.SStepReq) (err error)
 		}
 	}()
 	data := &models.SStep{
-		TaskName: ss.taskName,
-		Name:     step.Name,
-		Desc:     step.Desc,
-		Type:     step.Type,
-		Content:  step.Content,
-		Action:   step.Action,
-		Rule:     step.Rule,
-		SeqNo:    seqNo,
-		Timeout:  step.Timeout,
-		Disable:  models.Pointer(step.Disable),
+		TaskName:     ss.taskName,
+		Name:         step.Name,
+		Desc:         step.Desc,
+		Type:         step.Type,
+		Content:      step.Content,
+		Action:       step.Action,
+		Rule:         step.Rule,
+		When:         step.When,
+		Handler:      step.Handler,
+		SeqNo:        seqNo,
+		Timeout:      step.Timeout,
+		Priority:     step.Priority,
+		Weight:       step.Weight,
+		Mutex:        step.Mutex,
+		Disable:      models.Pointer(step.Disable),
+		AllowFailure: models.Pointer(step.AllowFailure),
+		Idempotent:   models.Pointer(step.Idempotent),
+		Metadata:     step.Metadata,
 		SStepUpdate: models.SStepUpdate{
 			Message:  "the step is waiting to be scheduled for execution",
 			Code:     models.Pointer(common.ExecCode(0)),
@@ -105,14 +133,23 @@ func (ss *SStepService) saveStep(seqNo int64, step *types.SStepReq) (err error)
 			OldState: models.Pointer(models.StatePending),
 		},
 	}
-	if step.RetryPolicy != nil {
-		data.RetryPolicy = datatypes.NewJSONType(models.SRetryPolicy{
-			Interval:    step.RetryPolicy.Interval,
-			MaxAttempts: step.RetryPolicy.MaxAttempts,
-			MaxInterval: step.RetryPolicy.MaxInterval,
-			Multiplier:  step.RetryPolicy.Multiplier,
+	if step.Instance != nil {
+		data.Matrix = datatypes.NewJSONType(models.SMatrix{
+			Group:       step.Instance.Group,
+			Axes:        step.Matrix,
+			Values:      step.Instance.Values,
+			MaxParallel: step.MaxParallel,
+		})
+	}
+	if step.Foreach != "" {
+		data.Foreach = datatypes.NewJSONType(models.SForeach{
+			From:        step.Foreach,
+			MaxParallel: step.MaxParallel,
 		})
 	}
+	if step.RetryPolicy != nil {
+		data.RetryPolicy = datatypes.NewJSONType(retryPolicyModel(step.RetryPolicy))
+	}
 	err = storage.Task(ss.taskName).StepCreate(data)
 	if err != nil {
 		logx.Errorln("step save", ss.taskName, ss.stepName, err)
@@ -147,28 +184,46 @@ func (ss *SStepService) Detail() (base.Code, *types.SStepRes, error) {
 		return base.CodeFailed, nil, errors.New("step not found")
 	}
 	data := &types.SStepRes{
-		Name:    step.Name,
-		Desc:    step.Desc,
-		State:   models.StateMap[*step.State],
-		Code:    step.Code.Int64(),
-		Message: step.Message,
-		Timeout: step.Timeout,
-		Disable: *step.Disable,
-		Type:    step.Type,
-		Content: step.Content,
-		Action:  step.Action,
-		Rule:    step.Rule,
-		RetryPolicy: &types.SRetryPolicy{
-			Interval:    step.RetryPolicy.Data().Interval,
-			MaxAttempts: step.RetryPolicy.Data().MaxAttempts,
-			MaxInterval: step.RetryPolicy.Data().MaxInterval,
-			Multiplier:  step.RetryPolicy.Data().Multiplier,
-		},
+		Name:         step.Name,
+		Desc:         step.Desc,
+		State:        models.StateMap[*step.State],
+		Code:         step.Code.Int64(),
+		Message:      step.Message,
+		Timeout:      step.Timeout,
+		Priority:     step.Priority,
+		Weight:       step.Weight,
+		Mutex:        step.Mutex,
+		Disable:      *step.Disable,
+		AllowFailure: *step.AllowFailure,
+		Idempotent:   *step.Idempotent,
+		Type:         step.Type,
+		Content:      step.Content,
+		Action:       step.Action,
+		Rule:         step.Rule,
+		When:         step.When,
+		RetryPolicy:  retryPolicyRes(step.RetryPolicy.Data()),
 		Time: &types.STimeRes{
 			Start: step.STimeStr(),
 			End:   step.ETimeStr(),
 		},
 	}
+	data.Metadata = step.Metadata
+	if approval, _err := stepStorage.Approval().Get(); _err == nil {
+		data.Approval = &types.SApprovalRes{
+			Approved: *approval.Approved,
+			Approver: approval.Approver,
+			Comment:  approval.Comment,
+			Time:     approval.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	for _, attempt := range stepStorage.Attempt().List() {
+		data.Attempts = append(data.Attempts, attemptRes(attempt))
+	}
+	data.Group = stepGroup(step)
+	data.Matrix = step.Matrix.Data().Values
+	data.Foreach = step.Foreach.Data().From
+	data.Item = step.Foreach.Data().Item
+	data.Handler = step.Handler
 	data.Depends = storage.Task(ss.taskName).Step(step.Name).Depend().List()
 	envs := stepStorage.Env().List()
 	for _, env := range envs {
@@ -177,6 +232,12 @@ func (ss *SStepService) Detail() (base.Code, *types.SStepRes, error) {
 			Value: env.Value,
 		})
 	}
+	for _, output := range stepStorage.Output().List() {
+		data.Outputs = append(data.Outputs, &types.SEnv{
+			Name:  output.Name,
+			Value: output.Value,
+		})
+	}
 	return base.Code(data.Code), data, nil
 }
 
@@ -186,6 +247,10 @@ func (ss *SStepService) Manager(action string, duration string) error {
 		logx.Errorln("step manager", ss.taskName, ss.stepName, err)
 		return errors.New("task not found")
 	}
+	// 已结束的任务重新执行步骤时, 重新提交任务
+	if action == "retry" && (*task.State == models.StateFailed || *task.State == models.StateStopped) {
+		return ss.retry(task)
+	}
 	if *task.State != models.StateRunning && *task.State != models.StatePending && *task.State != models.StatePaused {
 		return errors.New("task is no running")
 	}
@@ -194,12 +259,81 @@ func (ss *SStepService) Manager(action string, duration string) error {
 		logx.Errorln("step manager", ss.taskName, ss.stepName, err)
 		return errors.New("step not found")
 	}
-	if *step.State != models.StateRunning && *step.State != models.StatePending && *step.State != models.StatePaused {
-		return errors.New("step is no running")
+	switch action {
+	case "retry":
+		if *step.State != models.StateFailed {
+			return errors.New("only failed steps can be retried")
+		}
+		// 没有执行中的步骤时任务即将结束, 需在任务结束后重新执行
+		if !ss.inProgress() {
+			return errors.New("no steps are in progress, retry the step after the task has finished")
+		}
+	default:
+		if *step.State != models.StateRunning && *step.State != models.StatePending && *step.State != models.StatePaused && *step.State != models.StateWaiting {
+			return errors.New("step is no running")
+		}
 	}
 	return pubsub.PublishManager(task.Node, utility.JoinWithInvisibleChar(ss.taskName, ss.stepName, action, duration))
 }
 
+// inProgress 任务中是否有其他未结束的步骤
+func (ss *SStepService) inProgress() bool {
+	for name, state := range storage.Task(ss.taskName).StepStateList(storage.All) {
+		if name == ss.stepName {
+			continue
+		}
+		switch state {
+		case models.StateRunning, models.StatePending, models.StatePaused, models.StateWaiting:
+			return true
+		}
+	}
+	return false
+}
+
+// retry 重新执行已结束任务中的步骤及其未成功的下游步骤, 其他步骤保持上次的结果
+func (ss *SStepService) retry(task *models.STask) error {
+	db := storage.Task(ss.taskName)
+	steps := db.StepList(storage.All)
+	var stepMap = make(map[string]*models.SStep, len(steps))
+	var dependents = make(map[string][]string)
+	for _, step := range steps {
+		stepMap[step.Name] = step
+		for _, depend := range db.Step(step.Name).Depend().List() {
+			dependents[depend] = append(dependents[depend], step.Name)
+		}
+	}
+	step, ok := stepMap[ss.stepName]
+	if !ok {
+		return errors.New("step not found")
+	}
+	// foreach生成的子步骤随父步骤重新执行, 已成功的子步骤保留结果
+	if parent := step.Foreach.Data().Parent; parent != "" {
+		if step, ok = stepMap[parent]; !ok {
+			return errors.New("step not found")
+		}
+	}
+	if *step.State == models.StatePending {
+		return errors.New("step has not been executed")
+	}
+
+	var reset = models.SSteps{step}
+	var seen = map[string]bool{step.Name: true}
+	for i := 0; i < len(reset); i++ {
+		for _, child := range dependents[reset[i].Name] {
+			if seen[child] || *stepMap[child].State == models.StateStopped {
+				continue
+			}
+			seen[child] = true
+			reset = append(reset, stepMap[child])
+		}
+	}
+	if err := Task(ss.taskName).rerun(task, reset, "the task is waiting to retry step "+ss.stepName); err != nil {
+		logx.Errorln("step retry", ss.taskName, ss.stepName, err)
+		return err
+	}
+	return nil
+}
+
 func (ss *SStepService) Delete() error {
 	return storage.Task(ss.taskName).Step(ss.stepName).ClearAll()
 }
@@ -280,6 +414,7 @@ func (ss *SStepService) LogStream(ctx context.Context, output outputFn) error {
 		models.StatePaused:  ss.createOnceHandler(onceMap[models.StatePaused], base.CodePaused, "step is paused"),
 		models.StateUnknown: ss.createOnceHandler(onceMap[models.StateUnknown], base.CodeNoData, "step status unknown"),
 		models.StateRunning: ss.handleRunningState,
+		models.StateWaiting: ss.handleRunningState,
 		models.StateStopped: ss.handleFinalState(base.CodeSuccess),
 		models.StateFailed:  ss.handleFinalState(base.CodeFailed),
 		models.StateSkipped: ss.handleFinalState(base.CodeSkipped),
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"github.com/busyster996/dagflow/internal/runner/subtask"
+	"github.com/busyster996/dagflow/internal/server/types"
+)
+
+func init() {
+	subtask.SetLauncher(sSubtaskLauncher{})
+}
+
+// sSubtaskLauncher 子任务步骤通过服务层创建及管理子任务
+type sSubtaskLauncher struct{}
+
+func (sSubtaskLauncher) Build(pipeline string, params map[string]any) (string, error) {
+	return Pipeline(pipeline).BuildCreate(&types.SPipelineBuildReq{
+		Params: params,
+	})
+}
+
+func (sSubtaskLauncher) Create(task *types.STaskReq) error {
+	return Task(task.Name).Create(task)
+}
+
+func (sSubtaskLauncher) Manager(task, action, duration string) error {
+	return Task(task).Manager(action, duration)
+}
+
+func (sSubtaskLauncher) Validate(task *types.STaskReq) []string {
+	return Validate(task).Errors
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -83,6 +84,24 @@ func (ts *STaskService) Create(task *types.STaskReq) (err error) {
 		logx.Errorln("task review", ts.name, err)
 		return err
 	}
+	// 步骤校验不依赖存储, 在归档上一次执行前完成, 校验失败时不影响已有任务
+	if err = ts.reviewStep(task.Kind, task.Step); err != nil {
+		logx.Errorln("task review step", ts.name, err)
+		return err
+	}
+
+	// 展开矩阵步骤
+	task.Step, err = ts.expandMatrix(task.Step)
+	if err != nil {
+		logx.Errorln("task expand matrix", ts.name, err)
+		return err
+	}
+
+	handlers, err := ts.reviewHandlers(task)
+	if err != nil {
+		logx.Errorln("task review handlers", ts.name, err)
+		return err
+	}
 
 	var db = storage.Task(task.Name)
 	// 检查全局
@@ -95,13 +114,27 @@ func (ts *STaskService) Create(task *types.STaskReq) (err error) {
 		return errors.New("task is running")
 	}
 
-	// 清理旧数据
-	_ = db.ClearAll()
+	// 按并发策略处理同一并发组中的任务
+	if task.ConcurrencyGroup != "" {
+		groupMu.Lock()
+		defer groupMu.Unlock()
+		if err = ts.admit(task.ConcurrencyGroup, task.ConcurrencyPolicy); err != nil {
+			logx.Errorln("task admit", ts.name, err)
+			return err
+		}
+	}
+
+	// 保存上一次执行, 清理旧数据时保留历史执行记录及构建记录
+	if err = ts.saveRun(nil); err != nil {
+		logx.Errorln("task save run", ts.name, err)
+		return fmt.Errorf("save previous run error: %s", err)
+	}
+	_ = db.Reset()
 
 	defer func() {
 		if err != nil {
 			// rollback
-			_ = db.ClearAll()
+			_ = db.Reset()
 		}
 	}()
 
@@ -110,13 +143,7 @@ func (ts *STaskService) Create(task *types.STaskReq) (err error) {
 		return err
 	}
 
-	err = ts.reviewStep(task.Kind, task.Step)
-	if err != nil {
-		logx.Errorln("task review step", ts.name, err)
-		return err
-	}
-
-	for k, step := range task.Step {
+	for k, step := range slices.Concat(task.Step, handlers) {
 		var seqNo = int64(k + 1)
 		// save step
 		stepSvc := Step(task.Name, step.Name)
@@ -129,6 +156,15 @@ func (ts *STaskService) Create(task *types.STaskReq) (err error) {
 		logx.Errorln("task create", ts.name, err)
 		return err
 	}
+	// 等待前置任务结束
+	if len(task.DependsOnTasks) != 0 {
+		return ts.resolveDepends()
+	}
+	// 排队等待并发组空闲
+	if task.ConcurrencyGroup != "" {
+		storage.DispatchGroup(task.ConcurrencyGroup)
+		return nil
+	}
 	node := task.Node
 	if node == "" {
 		node = "random"
@@ -156,11 +192,18 @@ func (ts *STaskService) review(task *types.STaskReq) error {
 		return fmt.Errorf("duplicate keys %v", dup)
 	}
 
+	if err := reviewConcurrency(task); err != nil {
+		return err
+	}
+
 	task.Name = reg.ReplaceAllString(task.Name, "")
 	if task.Name == "" {
 		task.Name = ksuid.New().String()
 	}
 	ts.name = task.Name
+	if err := reviewDepends(task); err != nil {
+		return err
+	}
 	// 确保超时时间在合理范围内
 	if task.Timeout <= 0 || task.Timeout >= viper.GetDuration("exec_timeout") {
 		task.Timeout = viper.GetDuration("exec_timeout")
@@ -207,19 +250,32 @@ func (ts *STaskService) uniqStepsName(steps types.SStepsReq) error {
 	return fmt.Errorf("%v", errs)
 }
 func (ts *STaskService) saveTask(task *types.STaskReq) error {
+	var update = models.STaskUpdate{
+		Message:  "the task is waiting to be scheduled for execution",
+		State:    models.Pointer(models.StatePending),
+		OldState: models.Pointer(models.StatePending),
+	}
+	// 有前置任务时保持待执行, 前置任务结束后再提交; 并发组中的任务先排队, 由调度决定何时执行
+	switch {
+	case len(task.DependsOnTasks) != 0:
+		update.Message = dependMessage(dependNames(task.DependsOnTasks))
+	case task.ConcurrencyGroup != "":
+		update.Message = fmt.Sprintf("the task is queued in concurrency group %s", task.ConcurrencyGroup)
+		update.State = models.Pointer(models.StateQueued)
+		update.OldState = models.Pointer(models.StateQueued)
+	}
 	// save task
 	err := storage.TaskCreate(&models.STask{
-		Kind:    task.Kind,
-		Name:    task.Name,
-		Desc:    task.Desc,
-		Node:    task.Node,
-		Timeout: task.Timeout,
-		Disable: models.Pointer(task.Disable),
-		STaskUpdate: models.STaskUpdate{
-			Message:  "the task is waiting to be scheduled for execution",
-			State:    models.Pointer(models.StatePending),
-			OldState: models.Pointer(models.StatePending),
-		},
+		Kind:              task.Kind,
+		Name:              task.Name,
+		Desc:              task.Desc,
+		Node:              task.Node,
+		Timeout:           task.Timeout,
+		Disable:           models.Pointer(task.Disable),
+		FailFast:          models.Pointer(task.FailFast),
+		ConcurrencyGroup:  task.ConcurrencyGroup,
+		ConcurrencyPolicy: task.ConcurrencyPolicy,
+		STaskUpdate:       update,
 	})
 	if err != nil {
 		logx.Errorln("task save", ts.name, err)
@@ -238,6 +294,10 @@ func (ts *STaskService) saveTask(task *types.STaskReq) error {
 		logx.Errorln("task save envs", ts.name, err)
 		return fmt.Errorf("save task env error: %s", err)
 	}
+	if err = ts.saveDepends(task.DependsOnTasks); err != nil {
+		logx.Errorln("task save depends", ts.name, err)
+		return fmt.Errorf("save task depends error: %s", err)
+	}
 	return nil
 }
 
@@ -272,14 +332,18 @@ func (ts *STaskService) Detail() (base.Code, *types.STaskRes, error) {
 	}
 
 	data := &types.STaskRes{
-		Kind:    task.Kind,
-		Name:    task.Name,
-		Desc:    task.Desc,
-		Node:    task.Node,
-		State:   models.StateMap[*task.State],
-		Message: task.Message,
-		Timeout: task.Timeout,
-		Disable: *task.Disable,
+		Kind:              task.Kind,
+		Name:              task.Name,
+		Desc:              task.Desc,
+		Node:              task.Node,
+		State:             models.StateMap[*task.State],
+		Message:           task.Message,
+		Timeout:           task.Timeout,
+		Disable:           *task.Disable,
+		FailFast:          *task.FailFast,
+		ConcurrencyGroup:  task.ConcurrencyGroup,
+		ConcurrencyPolicy: task.ConcurrencyPolicy,
+		DependsOnTasks:    dependRes(db.Depend().List()),
 		Time: &types.STimeRes{
 			Start: task.STimeStr(),
 			End:   task.ETimeStr(),
@@ -313,12 +377,157 @@ func (ts *STaskService) Manager(action string, duration string) error {
 		logx.Errorln("task manager", ts.name, err)
 		return errors.New("task not found")
 	}
+	if *task.State == models.StateQueued {
+		// 排队中的任务尚未调度, 只能取消
+		if action != "kill" {
+			return errors.New("task is queued")
+		}
+		return ts.cancelUnscheduled(models.StateQueued, "the task is cancelled while queued")
+	}
+	if *task.State == models.StatePending && ts.waitingDepends() {
+		// 等待前置任务的任务尚未提交, 只能取消
+		if action != "kill" {
+			return errors.New("task is waiting for dependent tasks")
+		}
+		return ts.cancelUnscheduled(models.StatePending, "the task is cancelled while waiting for dependent tasks")
+	}
 	if *task.State != models.StateRunning && *task.State != models.StatePending && *task.State != models.StatePaused {
 		return errors.New("task is no running")
 	}
 	return pubsub.PublishManager(task.Node, utility.JoinWithInvisibleChar(ts.name, action, duration))
 }
 
+// Resume 从失败处恢复执行, 仅重新执行未成功的步骤, 已成功步骤的状态、日志及输出保持不变
+func (ts *STaskService) Resume() error {
+	db := storage.Task(ts.name)
+	task, err := db.Get()
+	if err != nil {
+		logx.Errorln("task resume", ts.name, err)
+		return errors.New("task not found")
+	}
+	if *task.State != models.StateFailed {
+		return errors.New("only failed tasks can be resumed")
+	}
+	var steps models.SSteps
+	for _, step := range db.StepList(storage.All) {
+		if *step.State != models.StateStopped {
+			steps = append(steps, step)
+		}
+	}
+	if err = ts.rerun(task, steps, "the task is waiting to be resumed"); err != nil {
+		logx.Errorln("task resume", ts.name, err)
+		return err
+	}
+	return nil
+}
+
+// rerun 保存本次执行后重置步骤, 并与创建任务相同按并发策略准入、等待前置任务后重新提交
+func (ts *STaskService) rerun(task *models.STask, steps models.SSteps, message string) error {
+	// 按并发策略处理同一并发组中的任务
+	if task.ConcurrencyGroup != "" {
+		groupMu.Lock()
+		defer groupMu.Unlock()
+		if err := ts.admit(task.ConcurrencyGroup, task.ConcurrencyPolicy); err != nil {
+			return err
+		}
+	}
+
+	// 保存本次执行, 仅归档重新执行的步骤的日志, 其输出及执行记录保留在历史执行中
+	var reset = make(map[string]bool, len(steps))
+	for _, step := range steps {
+		reset[step.Name] = true
+	}
+	if err := ts.saveRun(func(name string) bool {
+		return reset[name]
+	}); err != nil {
+		return fmt.Errorf("save previous run error: %s", err)
+	}
+	db := storage.Task(ts.name)
+	if err := resetSteps(db, steps); err != nil {
+		return err
+	}
+	if err := db.ResetTime(); err != nil {
+		return err
+	}
+
+	// 重新等待前置任务
+	depends := db.Depend().List()
+	if len(depends) == 0 {
+		return submit(db, task, message)
+	}
+	var names []string
+	for _, depend := range depends {
+		names = append(names, depend.Name)
+		if err := db.Depend().Update(depend.Name, models.StatePending, fmt.Sprintf("waiting for task %s", depend.Name)); err != nil {
+			return err
+		}
+	}
+	if err := db.Update(&models.STaskUpdate{
+		Message:  dependMessage(names),
+		State:    models.Pointer(models.StatePending),
+		OldState: task.State,
+	}); err != nil {
+		return err
+	}
+	return ts.resolveDepends()
+}
+
+// resetSteps 清理步骤的输出、导出的环境变量、审批及执行记录, 并重置为待执行, 清理前已保存为历史执行
+func resetSteps(db storage.ITask, steps models.SSteps) error {
+	for _, step := range steps {
+		stepDB := db.Step(step.Name)
+		if err := stepDB.Output().RemoveAll(); err != nil {
+			return err
+		}
+		if err := stepDB.Export().RemoveAll(); err != nil {
+			return err
+		}
+		if err := stepDB.Approval().Remove(); err != nil {
+			return err
+		}
+		if err := stepDB.Attempt().RemoveAll(); err != nil {
+			return err
+		}
+		if err := stepDB.Update(&models.SStepUpdate{
+			Message:  "the step is waiting to be scheduled for execution",
+			Code:     models.Pointer(common.ExecCode(0)),
+			State:    models.Pointer(models.StatePending),
+			OldState: step.State,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// submit 提交待执行的任务, 并发组中的任务排队等待组空闲, 已结束的步骤沿用上次的结果
+func submit(db storage.ITask, task *models.STask, message string) error {
+	// 并发组中的任务重新排队
+	if task.ConcurrencyGroup != "" {
+		if err := db.Update(&models.STaskUpdate{
+			Message:  message,
+			State:    models.Pointer(models.StateQueued),
+			OldState: task.State,
+		}); err != nil {
+			return err
+		}
+		storage.DispatchGroup(task.ConcurrencyGroup)
+		return nil
+	}
+	if err := db.Update(&models.STaskUpdate{
+		Message:  message,
+		State:    models.Pointer(models.StatePending),
+		OldState: task.State,
+	}); err != nil {
+		return err
+	}
+	node := task.Node
+	if node == "" {
+		node = "random"
+	}
+	return pubsub.PublishTask(node, task.Name)
+}
+
 func (ts *STaskService) Dump() (*types.STaskReq, error) {
 	task, err := storage.Task(ts.name).Get()
 	if err != nil {
@@ -326,12 +535,21 @@ func (ts *STaskService) Dump() (*types.STaskReq, error) {
 		return nil, errors.New("task not found")
 	}
 	res := &types.STaskReq{
-		Kind:    task.Kind,
-		Name:    task.Name,
-		Desc:    task.Desc,
-		Node:    task.Node,
-		Timeout: task.Timeout,
-		Disable: *task.Disable,
+		Kind:              task.Kind,
+		Name:              task.Name,
+		Desc:              task.Desc,
+		Node:              task.Node,
+		Timeout:           task.Timeout,
+		Disable:           *task.Disable,
+		FailFast:          *task.FailFast,
+		ConcurrencyGroup:  task.ConcurrencyGroup,
+		ConcurrencyPolicy: task.ConcurrencyPolicy,
+	}
+	for _, depend := range storage.Task(ts.name).Depend().List() {
+		res.DependsOnTasks = append(res.DependsOnTasks, &types.STaskDependReq{
+			Name: depend.Name,
+			When: depend.When,
+		})
 	}
 	for _, env := range storage.Task(ts.name).Env().List() {
 		res.Env = append(res.Env, &types.SEnv{
@@ -340,32 +558,84 @@ func (ts *STaskService) Dump() (*types.STaskReq, error) {
 		})
 	}
 	steps := storage.Task(ts.name).StepList(storage.All)
+	// 矩阵实例折叠为原始步骤
+	var groups = make(map[string]string)
 	for _, step := range steps {
+		if group := step.Matrix.Data().Group; group != "" {
+			groups[step.Name] = group
+		}
+	}
+	var dumped = make(map[string]bool)
+	for _, step := range steps {
+		// foreach生成的子步骤在执行时重新生成
+		if step.Foreach.Data().Parent != "" {
+			continue
+		}
+		matrix := step.Matrix.Data()
+		if matrix.Group != "" {
+			if dumped[matrix.Group] {
+				continue
+			}
+			dumped[matrix.Group] = true
+		}
 		stepRes := &types.SStepReq{
-			Name:    step.Name,
-			Desc:    step.Desc,
-			Type:    step.Type,
-			Content: step.Content,
-			Timeout: step.Timeout,
-			Disable: *step.Disable,
-			Action:  step.Action,
-			Rule:    step.Rule,
-			RetryPolicy: &types.SRetryPolicy{
-				Interval:    step.RetryPolicy.Data().Interval,
-				MaxAttempts: step.RetryPolicy.Data().MaxAttempts,
-				MaxInterval: step.RetryPolicy.Data().MaxInterval,
-				Multiplier:  step.RetryPolicy.Data().Multiplier,
-			},
+			Name:         step.Name,
+			Desc:         step.Desc,
+			Type:         step.Type,
+			Content:      step.Content,
+			Timeout:      step.Timeout,
+			Priority:     step.Priority,
+			Weight:       step.Weight,
+			Mutex:        step.Mutex,
+			Disable:      *step.Disable,
+			AllowFailure: *step.AllowFailure,
+			Idempotent:   *step.Idempotent,
+			Action:       step.Action,
+			Rule:         step.Rule,
+			When:         step.When,
+			Metadata:     step.Metadata,
+			RetryPolicy:  retryPolicyRes(step.RetryPolicy.Data()),
+		}
+		if foreach := step.Foreach.Data(); foreach.From != "" {
+			stepRes.Foreach = foreach.From
+			stepRes.MaxParallel = foreach.MaxParallel
+		}
+		var matrixEnv = make(map[string]bool)
+		if matrix.Group != "" {
+			stepRes.Name = matrix.Group
+			stepRes.Matrix = matrix.Axes
+			stepRes.MaxParallel = matrix.MaxParallel
+			for axis := range matrix.Values {
+				matrixEnv[matrixEnvName(axis)] = true
+			}
 		}
 		envs := storage.Task(ts.name).Step(step.Name).Env().List()
 		for _, env := range envs {
+			if matrixEnv[env.Name] {
+				continue
+			}
 			stepRes.Env = append(stepRes.Env, &types.SEnv{
 				Name:  env.Name,
 				Value: env.Value,
 			})
 		}
-		stepRes.Depends = storage.Task(ts.name).Step(step.Name).Depend().List()
-		res.Step = append(res.Step, stepRes)
+		for _, depend := range storage.Task(ts.name).Step(step.Name).Depend().List() {
+			if group, ok := groups[depend]; ok {
+				depend = group
+			}
+			stepRes.Depends = append(stepRes.Depends, depend)
+		}
+		stepRes.Depends = utility.RemoveDuplicate(stepRes.Depends)
+		switch step.Handler {
+		case common.HandlerSuccess:
+			res.OnSuccess = append(res.OnSuccess, stepRes)
+		case common.HandlerFailure:
+			res.OnFailure = append(res.OnFailure, stepRes)
+		case common.HandlerFinally:
+			res.Finally = append(res.Finally, stepRes)
+		default:
+			res.Step = append(res.Step, stepRes)
+		}
 	}
 	return res, nil
 }
@@ -393,6 +663,10 @@ func (ts *STaskService) Steps() (code base.Code, data types.SStepsRes, err error
 			State:   models.StateMap[*step.State],
 			Code:    step.Code.Int64(),
 			Message: step.Message,
+			Group:   stepGroup(step),
+			Matrix:  step.Matrix.Data().Values,
+			Item:    step.Foreach.Data().Item,
+			Handler: step.Handler,
 			Time: &types.STimeRes{
 				Start: step.STimeStr(),
 				End:   step.ETimeStr(),
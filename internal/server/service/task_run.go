@@ -0,0 +1,320 @@
+package service
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/server/router/base"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// sTaskSnapshot 任务当前执行的快照, 清理任务时归档, request 可直接重新提交
+type sTaskSnapshot struct {
+	Task    *types.STaskRes               `json:"task"`
+	Request *types.STaskReq               `json:"request"`
+	Steps   types.SStepsRes               `json:"steps"`
+	Logs    map[string]types.SStepLogsRes `json:"logs"`
+}
+
+// snapshotTask 当前任务及其步骤详情和日志, 步骤按深度排序
+func snapshotTask(name string) (*sTaskSnapshot, error) {
+	ts := Task(name)
+	_, task, err := ts.Detail()
+	if err != nil {
+		return nil, err
+	}
+	req, err := ts.Dump()
+	if err != nil {
+		return nil, err
+	}
+	_, steps, _ := ts.Steps()
+	var snapshot = &sTaskSnapshot{
+		Task:    task,
+		Request: req,
+		Logs:    make(map[string]types.SStepLogsRes, len(steps)),
+	}
+	for _, step := range steps {
+		ss := Step(name, step.Name)
+		if _, detail, _err := ss.Detail(); _err == nil {
+			// 列表中的分组等信息详情中同样包含
+			step = detail
+		}
+		snapshot.Steps = append(snapshot.Steps, step)
+		snapshot.Logs[step.Name], _ = ss.log(nil)
+	}
+	return snapshot, nil
+}
+
+// encode 写入 gzip 压缩的 JSON
+func (s *sTaskSnapshot) encode(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(s); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// saveRun 重新提交前保存任务上一次执行的详情及步骤, 步骤日志在日志后端中归档为该次执行, 任务不存在时跳过.
+// archive 不为空时仅归档其选中的步骤的日志, 其余步骤的日志保留在当前执行中
+func (ts *STaskService) saveRun(archive func(step string) bool) error {
+	db := storage.Task(ts.name)
+	task, err := db.Get()
+	if err != nil {
+		return nil
+	}
+	_, detail, err := ts.Detail()
+	if err != nil {
+		return err
+	}
+	req, err := ts.Dump()
+	if err != nil {
+		return err
+	}
+	_, steps, _ := ts.Steps()
+	var rows = make(models.STaskRunSteps, 0, len(steps))
+	for k, step := range steps {
+		if _, v, _err := Step(ts.name, step.Name).Detail(); _err == nil {
+			// 列表中的分组等信息详情中同样包含
+			step = v
+		}
+		data, err := json.Marshal(step)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, &models.STaskRunStep{
+			StepName: step.Name,
+			Seq:      int64(k + 1),
+			State:    models.Pointer(stateOf(step.State)),
+			Message:  step.Message,
+			Detail:   string(data),
+		})
+	}
+	request, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return err
+	}
+	// 消息与详情一致, 包含各状态的步骤
+	task.Message = detail.Message
+	var run = &models.STaskRun{
+		Count:       int64(len(rows)),
+		Request:     string(request),
+		Detail:      string(data),
+		STaskUpdate: task.STaskUpdate,
+	}
+	if err = db.Run().Insert(run, rows); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if archive != nil && !archive(row.StepName) {
+			continue
+		}
+		if _err := db.Step(row.StepName).Log().Archive(run.Run); _err != nil {
+			err = multierr.Append(err, fmt.Errorf("archive step %s log error: %s", row.StepName, _err))
+		}
+	}
+	return err
+}
+
+// Runs 任务的所有执行, 当前任务为最近一次
+func (ts *STaskService) Runs() (base.Code, types.STaskRunsRes, error) {
+	db := storage.Task(ts.name)
+	var res types.STaskRunsRes
+	if _, task, err := ts.Detail(); err == nil {
+		res = append(res, &types.STaskRunRes{
+			Run:     db.Run().Latest() + 1,
+			Latest:  true,
+			State:   task.State,
+			Count:   task.Count,
+			Message: task.Message,
+			Time:    task.Time,
+		})
+	}
+	for _, run := range db.Run().List() {
+		res = append(res, &types.STaskRunRes{
+			Run:     run.Run,
+			State:   models.StateMap[*run.State],
+			Count:   run.Count,
+			Message: run.Message,
+			Time: &types.STimeRes{
+				Start: run.STimeStr(),
+				End:   run.ETimeStr(),
+			},
+		})
+	}
+	if len(res) == 0 {
+		return base.CodeNoData, nil, errors.New("task not found")
+	}
+	return base.CodeSuccess, res, nil
+}
+
+type STaskRunService struct {
+	taskName string
+	run      int64
+}
+
+func TaskRun(taskName string, run int64) *STaskRunService {
+	return &STaskRunService{
+		taskName: taskName,
+		run:      run,
+	}
+}
+
+// latest 最近一次执行即当前任务, 直接查询任务及步骤
+func (rs *STaskRunService) latest() bool {
+	return rs.run == storage.Task(rs.taskName).Run().Latest()+1
+}
+
+func (rs *STaskRunService) load() (*models.STaskRun, error) {
+	run, err := storage.Task(rs.taskName).Run().Get(rs.run)
+	if err != nil {
+		logx.Errorln("task run", rs.taskName, rs.run, err)
+		return nil, fmt.Errorf("run %d not found", rs.run)
+	}
+	return run, nil
+}
+
+func (rs *STaskRunService) step(name string) (*types.SStepRes, error) {
+	row, err := storage.Task(rs.taskName).Run().Step(rs.run, name)
+	if err != nil {
+		logx.Errorln("task run step", rs.taskName, rs.run, name, err)
+		return nil, errors.New("step not found")
+	}
+	var step = new(types.SStepRes)
+	if err = json.Unmarshal([]byte(row.Detail), step); err != nil {
+		logx.Errorln("task run step", rs.taskName, rs.run, name, err)
+		return nil, fmt.Errorf("run %d step %s is corrupted", rs.run, name)
+	}
+	return step, nil
+}
+
+func (rs *STaskRunService) Detail() (base.Code, *types.STaskRes, error) {
+	if rs.latest() {
+		return Task(rs.taskName).Detail()
+	}
+	run, err := rs.load()
+	if err != nil {
+		return base.CodeNoData, nil, err
+	}
+	var task = new(types.STaskRes)
+	if err = json.Unmarshal([]byte(run.Detail), task); err != nil {
+		logx.Errorln("task run", rs.taskName, rs.run, err)
+		return base.CodeNoData, nil, fmt.Errorf("run %d is corrupted", rs.run)
+	}
+	return ConvertState(*run.State), task, nil
+}
+
+// Request 该次执行提交的任务, 可直接重新提交
+func (rs *STaskRunService) Request() (*types.STaskReq, error) {
+	if rs.latest() {
+		return Task(rs.taskName).Dump()
+	}
+	run, err := rs.load()
+	if err != nil {
+		return nil, err
+	}
+	var req = new(types.STaskReq)
+	if err = json.Unmarshal([]byte(run.Request), req); err != nil {
+		logx.Errorln("task run", rs.taskName, rs.run, err)
+		return nil, fmt.Errorf("run %d is corrupted", rs.run)
+	}
+	return req, nil
+}
+
+func (rs *STaskRunService) Steps() (base.Code, types.SStepsRes, error) {
+	if rs.latest() {
+		return Task(rs.taskName).Steps()
+	}
+	run, err := rs.load()
+	if err != nil {
+		return base.CodeNoData, nil, err
+	}
+	var steps types.SStepsRes
+	for _, row := range storage.Task(rs.taskName).Run().Steps(rs.run) {
+		var step = new(types.SStepRes)
+		if _err := json.Unmarshal([]byte(row.Detail), step); _err != nil {
+			logx.Errorln("task run step", rs.taskName, rs.run, row.StepName, _err)
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return ConvertState(*run.State), steps, errors.New(run.Message)
+}
+
+func (rs *STaskRunService) StepDetail(name string) (base.Code, *types.SStepRes, error) {
+	if rs.latest() {
+		return Step(rs.taskName, name).Detail()
+	}
+	step, err := rs.step(name)
+	if err != nil {
+		return base.CodeNoData, nil, err
+	}
+	return ConvertState(stateOf(step.State)), step, nil
+}
+
+// StepLog 步骤的日志, attempt 大于0时仅返回该次尝试的日志
+func (rs *STaskRunService) StepLog(name string, attempt int) (base.Code, types.SStepLogsRes, error) {
+	if rs.latest() {
+		if attempt > 0 {
+			return Step(rs.taskName, name).AttemptLog(attempt)
+		}
+		return Step(rs.taskName, name).Log()
+	}
+	step, err := rs.step(name)
+	if err != nil {
+		return base.CodeNoData, nil, err
+	}
+	log := storage.Task(rs.taskName).Step(name).Log()
+	if attempt <= 0 {
+		return ConvertState(stateOf(step.State)), convertLogs(log.History(rs.run, 0, -1)), errors.New(step.Message)
+	}
+	for _, v := range step.Attempts {
+		if v.Attempt != attempt {
+			continue
+		}
+		// 未结束的尝试没有结束行号
+		var end int64 = -1
+		if v.LogEnd > 0 {
+			end = v.LogEnd
+		}
+		return ConvertState(stateOf(v.State)), convertLogs(log.History(rs.run, v.LogStart, end)), errors.New(v.Message)
+	}
+	return base.CodeNoData, nil, fmt.Errorf("attempt %d not found", attempt)
+}
+
+// convertLogs 转换历史执行的日志, 忽略控制台的开始及结束标记
+func convertLogs(logs models.SStepLogs) (res types.SStepLogsRes) {
+	for _, v := range logs {
+		if v.Content == common.ExecConsoleStart || v.Content == common.ExecConsoleDone {
+			continue
+		}
+		res = append(res, &types.SStepLogRes{
+			Timestamp: v.Timestamp,
+			Line:      *v.Line,
+			Content:   v.Content,
+		})
+	}
+	return
+}
+
+// stateOf 状态名称对应的状态
+func stateOf(name string) models.State {
+	for state, v := range models.StateMap {
+		if v == name {
+			return state
+		}
+	}
+	return models.StateUnknown
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/viper"
+
+	"github.com/busyster996/dagflow/internal/runner"
+	"github.com/busyster996/dagflow/internal/server/types"
+	"github.com/busyster996/dagflow/internal/utility"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+)
+
+// Validate 静态校验任务并生成执行计划, 与创建任务的检查一致, 但不创建任何数据且尽可能报告所有问题
+func Validate(task *types.STaskReq) *types.STaskPlanRes {
+	var plan = new(types.STaskPlanRes)
+	var errorf = func(format string, args ...any) {
+		plan.Errors = append(plan.Errors, fmt.Sprintf(format, args...))
+	}
+	var warnf = func(format string, args ...any) {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf(format, args...))
+	}
+	defer func() {
+		plan.Valid = len(plan.Errors) == 0
+	}()
+
+	task.Kind = strings.ToLower(task.Kind)
+	plan.Kind = task.Kind
+	plan.Name = reg.ReplaceAllString(task.Name, "")
+	switch {
+	case plan.Name == "":
+		plan.Name = ksuid.New().String()
+		warnf("task name is empty, %s will be used", plan.Name)
+	case plan.Name != task.Name:
+		warnf("task name %s will be normalized to %s", task.Name, plan.Name)
+	}
+	task.Name = plan.Name
+
+	if dup := utility.CheckDuplicate(envNames(task.Env)); dup != nil {
+		errorf("duplicate env keys %v", dup)
+	}
+	if err := reviewConcurrency(task); err != nil {
+		errorf("%v", err)
+	}
+	if err := reviewDepends(task); err != nil {
+		errorf("%v", err)
+	}
+
+	// 超时时间与创建任务时的修正规则一致
+	limit := viper.GetDuration("exec_timeout")
+	switch {
+	case task.Timeout <= 0:
+		task.Timeout = limit
+	case task.Timeout >= limit:
+		warnf("task timeout %s will be clamped to %s", task.Timeout, limit)
+		task.Timeout = limit
+	}
+	plan.Timeout = task.Timeout
+
+	if len(task.Step) == 0 {
+		errorf("steps can not be empty")
+		return plan
+	}
+	for k, step := range task.Step {
+		name := reg.ReplaceAllString(step.Name, "")
+		switch {
+		case name == "":
+			name = ksuid.New().String()
+			warnf("step #%d has no name, %s will be used", k+1, name)
+		case name != step.Name:
+			warnf("step name %s will be normalized to %s", step.Name, name)
+		}
+		step.Name = name
+	}
+
+	var ts = Task(task.Name)
+	if err := ts.reviewStep(task.Kind, task.Step); err != nil {
+		errorf("duplicate step names: %v", err)
+		return plan
+	}
+	handlers, err := ts.reviewHandlers(task)
+	if err != nil {
+		errorf("%v", err)
+	}
+	for _, step := range slices.Concat(task.Step, handlers) {
+		if err := Step(task.Name, step.Name).review(step); err != nil {
+			errorf("step %s: %v", step.Name, err)
+		}
+		if step.Type != "" && step.Content != "" {
+			if err := runner.Validate(step.Type, step.Content); err != nil {
+				errorf("step %s: %v", step.Name, err)
+			}
+		}
+		if step.Timeout > task.Timeout {
+			warnf("step %s timeout %s will be clamped to %s", step.Name, step.Timeout, task.Timeout)
+		}
+		if step.Timeout <= 0 || step.Timeout > task.Timeout {
+			step.Timeout = task.Timeout
+		}
+	}
+
+	steps, err := ts.expandMatrix(task.Step)
+	if err != nil {
+		errorf("%v", err)
+		return plan
+	}
+	// 执行时禁用的步骤不参与调度, 依赖它的步骤视为该依赖已满足
+	var disabled = make(map[string]bool)
+	for _, step := range steps {
+		if step.Disable {
+			disabled[step.Name] = true
+		}
+	}
+	var deps = make(map[string][]string, len(steps))
+	for _, step := range steps {
+		deps[step.Name] = make([]string, 0, len(step.Depends))
+		for _, dep := range step.Depends {
+			if disabled[dep] {
+				warnf("step %s depends on disabled step %s, the dependency is treated as satisfied", step.Name, dep)
+				continue
+			}
+			deps[step.Name] = append(deps[step.Name], dep)
+		}
+	}
+	graph, err := dagcuter.NewGraph(deps)
+	if err != nil {
+		var joined interface{ Unwrap() []error }
+		if errors.As(err, &joined) {
+			for _, e := range joined.Unwrap() {
+				errorf("%v", e)
+			}
+		} else {
+			errorf("%v", err)
+		}
+		return plan
+	}
+
+	plan.Levels = graph.Levels()
+	heights := graph.Heights()
+	var levels = make(map[string]int, len(steps))
+	for level, names := range plan.Levels {
+		for _, name := range names {
+			levels[name] = level
+		}
+	}
+	for _, step := range steps {
+		res := &types.SPlanStepRes{
+			Name:     step.Name,
+			Type:     step.Type,
+			Level:    levels[step.Name],
+			Timeout:  step.Timeout,
+			Priority: step.Priority,
+			Height:   heights[step.Name],
+			Disable:  step.Disable,
+			Depends:  graph.Dependencies(step.Name),
+		}
+		if step.Instance != nil {
+			res.Group = step.Instance.Group
+		}
+		plan.Steps = append(plan.Steps, res)
+	}
+	return plan
+}
+
+func envNames(envs types.SEnvs) []string {
+	var names []string
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	return names
+}
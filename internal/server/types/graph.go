@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+type STaskGraphRes struct {
+	Nodes        []*SGraphNodeRes `json:"nodes" yaml:"nodes"`
+	Edges        []*SGraphEdgeRes `json:"edges" yaml:"edges"`
+	Levels       [][]string       `json:"levels" yaml:"levels"`
+	CriticalPath []string         `json:"criticalPath" yaml:"criticalPath"`
+	Duration     time.Duration    `json:"duration" yaml:"duration"`
+}
+
+type SGraphNodeRes struct {
+	Name     string        `json:"name" yaml:"name"`
+	State    string        `json:"state" yaml:"state"`
+	Node     string        `json:"node,omitempty" yaml:"node,omitempty"`
+	Level    int           `json:"level" yaml:"level"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+	Critical bool          `json:"critical,omitempty" yaml:"critical,omitempty"`
+	Time     *STimeRes     `json:"time,omitempty" yaml:"time,omitempty"`
+}
+
+type SGraphEdgeRes struct {
+	From     string `json:"from" yaml:"from"`
+	To       string `json:"to" yaml:"to"`
+	Critical bool   `json:"critical,omitempty" yaml:"critical,omitempty"`
+}
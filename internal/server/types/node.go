@@ -0,0 +1,44 @@
+package types
+
+import "time"
+
+type SNodeRes struct {
+	Name     string       `json:"name" yaml:"name"`
+	Budget   *SBudgetRes  `json:"budget" yaml:"budget"`
+	Janitor  *SJanitorRes `json:"janitor,omitempty" yaml:"janitor,omitempty"`
+	UpdateAt time.Time    `json:"updateAt" yaml:"updateAt"`
+}
+
+type SNodesRes []*SNodeRes
+
+type SBudgetRes struct {
+	Size    int64               `json:"size" yaml:"size"`
+	Used    int64               `json:"used" yaml:"used"`
+	Holders []*SBudgetHolderRes `json:"holders,omitempty" yaml:"holders,omitempty"`
+	Waiters []*SBudgetHolderRes `json:"waiters,omitempty" yaml:"waiters,omitempty"`
+}
+
+type SBudgetHolderRes struct {
+	Task   string    `json:"task" yaml:"task"`
+	Step   string    `json:"step" yaml:"step"`
+	Weight int64     `json:"weight" yaml:"weight"`
+	Mutex  string    `json:"mutex,omitempty" yaml:"mutex,omitempty"`
+	Since  time.Time `json:"since" yaml:"since"`
+}
+
+type SJanitorRes struct {
+	LastRun time.Time    `json:"lastRun" yaml:"lastRun"`
+	Last    *SReclaimRes `json:"last" yaml:"last"`
+	Total   *SReclaimRes `json:"total" yaml:"total"`
+}
+
+type SReclaimRes struct {
+	Tasks    int64 `json:"tasks" yaml:"tasks"`
+	Archived int64 `json:"archived" yaml:"archived"`
+	Builds   int64 `json:"builds" yaml:"builds"`
+	Runs     int64 `json:"runs" yaml:"runs"`
+	LogLines int64 `json:"logLines" yaml:"logLines"`
+	Uploads  int64 `json:"uploads" yaml:"uploads"`
+	Dirs     int64 `json:"dirs" yaml:"dirs"`
+	Bytes    int64 `json:"bytes" yaml:"bytes"`
+}
@@ -3,37 +3,70 @@ package types
 import "time"
 
 type SStepRes struct {
-	Name        string        `json:"name" yaml:"name"`
-	State       string        `json:"state" yaml:"state"`
-	Code        int64         `json:"code" yaml:"code"`
-	Desc        string        `json:"desc,omitempty" yaml:"desc,omitempty"`
-	Timeout     time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	Disable     bool          `json:"disable,omitempty" yaml:"disable,omitempty"`
-	Depends     []string      `json:"depends,omitempty" yaml:"depends,omitempty"`
-	Message     string        `json:"message" yaml:"message"`
-	Env         SEnvs         `json:"env,omitempty" yaml:"env,omitempty"`
-	Type        string        `json:"type,omitempty" yaml:"type,omitempty"`
-	Content     string        `json:"content,omitempty" yaml:"content,omitempty"`
-	Action      string        `json:"action,omitempty" yaml:"action,omitempty"`
-	Rule        string        `json:"rule,omitempty" yaml:"rule,omitempty"`
-	RetryPolicy *SRetryPolicy `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
-	Time        *STimeRes     `json:"time,omitempty" yaml:"time,omitempty"`
+	Name         string            `json:"name" yaml:"name"`
+	State        string            `json:"state" yaml:"state"`
+	Code         int64             `json:"code" yaml:"code"`
+	Desc         string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Timeout      time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Priority     int               `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Weight       int64             `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Mutex        string            `json:"mutex,omitempty" yaml:"mutex,omitempty"`
+	Disable      bool              `json:"disable,omitempty" yaml:"disable,omitempty"`
+	AllowFailure bool              `json:"allowFailure,omitempty" yaml:"allowFailure,omitempty"`
+	Idempotent   bool              `json:"idempotent,omitempty" yaml:"idempotent,omitempty"`
+	Depends      []string          `json:"depends,omitempty" yaml:"depends,omitempty"`
+	Message      string            `json:"message" yaml:"message"`
+	Env          SEnvs             `json:"env,omitempty" yaml:"env,omitempty"`
+	Outputs      SEnvs             `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Type         string            `json:"type,omitempty" yaml:"type,omitempty"`
+	Content      string            `json:"content,omitempty" yaml:"content,omitempty"`
+	Action       string            `json:"action,omitempty" yaml:"action,omitempty"`
+	Rule         string            `json:"rule,omitempty" yaml:"rule,omitempty"`
+	When         string            `json:"when,omitempty" yaml:"when,omitempty"`
+	Group        string            `json:"group,omitempty" yaml:"group,omitempty"`
+	Matrix       map[string]string `json:"matrix,omitempty" yaml:"matrix,omitempty"`
+	Foreach      string            `json:"foreach,omitempty" yaml:"foreach,omitempty"`
+	Item         string            `json:"item,omitempty" yaml:"item,omitempty"`
+	Handler      string            `json:"handler,omitempty" yaml:"handler,omitempty"`
+	RetryPolicy  *SRetryPolicy     `json:"retryPolicy,omitempty" yaml:"retryPolicy,omitempty"`
+	Metadata     map[string]any    `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Approval     *SApprovalRes     `json:"approval,omitempty" yaml:"approval,omitempty"`
+	Attempts     []*SAttemptRes    `json:"attempts,omitempty" yaml:"attempts,omitempty"`
+	Time         *STimeRes         `json:"time,omitempty" yaml:"time,omitempty"`
 }
 
 type SStepsRes []*SStepRes
 
 type SStepReq struct {
-	Name        string        `json:"name,omitempty" form:"name" yaml:"name,omitempty"`
-	Desc        string        `json:"desc,omitempty" form:"desc" yaml:"desc,omitempty"`
-	Timeout     time.Duration `json:"timeout,omitempty" form:"timeout" yaml:"timeout,omitempty"`
-	Disable     bool          `json:"disable,omitempty" form:"disable" yaml:"disable,omitempty"`
-	Depends     []string      `json:"depends,omitempty" form:"depends" yaml:"depends,omitempty"`
-	Env         SEnvs         `json:"env,omitempty" form:"env" yaml:"env,omitempty"`
-	Type        string        `json:"type,omitempty" form:"type" yaml:"type,omitempty" binding:"required"`
-	Content     string        `json:"content,omitempty" form:"content" yaml:"content,omitempty" binding:"required"`
-	Action      string        `json:"action,omitempty" form:"action" yaml:"action,omitempty"`
-	Rule        string        `json:"rule,omitempty" form:"rule" yaml:"rule,omitempty"`
-	RetryPolicy *SRetryPolicy `json:"retryPolicy,omitempty" form:"retryPolicy" yaml:"retryPolicy,omitempty"`
+	Name         string              `json:"name,omitempty" form:"name" yaml:"name,omitempty"`
+	Desc         string              `json:"desc,omitempty" form:"desc" yaml:"desc,omitempty"`
+	Timeout      time.Duration       `json:"timeout,omitempty" form:"timeout" yaml:"timeout,omitempty"`
+	Priority     int                 `json:"priority,omitempty" form:"priority" yaml:"priority,omitempty"`
+	Weight       int64               `json:"weight,omitempty" form:"weight" yaml:"weight,omitempty"`
+	Mutex        string              `json:"mutex,omitempty" form:"mutex" yaml:"mutex,omitempty"`
+	Disable      bool                `json:"disable,omitempty" form:"disable" yaml:"disable,omitempty"`
+	AllowFailure bool                `json:"allowFailure,omitempty" form:"allowFailure" yaml:"allowFailure,omitempty"`
+	Idempotent   bool                `json:"idempotent,omitempty" form:"idempotent" yaml:"idempotent,omitempty"`
+	Depends      []string            `json:"depends,omitempty" form:"depends" yaml:"depends,omitempty"`
+	Env          SEnvs               `json:"env,omitempty" form:"env" yaml:"env,omitempty"`
+	Type         string              `json:"type,omitempty" form:"type" yaml:"type,omitempty" binding:"required"`
+	Content      string              `json:"content,omitempty" form:"content" yaml:"content,omitempty" binding:"required"`
+	Action       string              `json:"action,omitempty" form:"action" yaml:"action,omitempty"`
+	Rule         string              `json:"rule,omitempty" form:"rule" yaml:"rule,omitempty"`
+	When         string              `json:"when,omitempty" form:"when" yaml:"when,omitempty"`
+	Matrix       map[string][]string `json:"matrix,omitempty" form:"matrix" yaml:"matrix,omitempty"`
+	MaxParallel  int                 `json:"maxParallel,omitempty" form:"maxParallel" yaml:"maxParallel,omitempty"`
+	Instance     *SMatrixInstance    `json:"-" form:"-" yaml:"-"`
+	Foreach      string              `json:"foreach,omitempty" form:"foreach" yaml:"foreach,omitempty"`
+	Handler      string              `json:"-" form:"-" yaml:"-"`
+	RetryPolicy  *SRetryPolicy       `json:"retryPolicy,omitempty" form:"retryPolicy" yaml:"retryPolicy,omitempty"`
+	Metadata     map[string]any      `json:"metadata,omitempty" form:"metadata" yaml:"metadata,omitempty"`
+}
+
+// SMatrixInstance 矩阵展开后的实例信息
+type SMatrixInstance struct {
+	Group  string
+	Values map[string]string
 }
 
 type SRetryPolicy struct {
@@ -41,10 +74,32 @@ type SRetryPolicy struct {
 	MaxInterval time.Duration `json:"maxInterval" yaml:"maxInterval" description:"最大间隔时间"`
 	MaxAttempts int           `json:"maxAttempts" yaml:"maxAttempts" description:"最大尝试次数"`
 	Multiplier  float64       `json:"multiplier" yaml:"multiplier" description:"乘数"`
+	Jitter      string        `json:"jitter,omitempty" yaml:"jitter,omitempty" description:"随机抖动, full或equal"`
+	RetryOn     *SRetryOn     `json:"retryOn,omitempty" yaml:"retryOn,omitempty" description:"重试条件, 满足任一条件才重试"`
+	RetryIf     string        `json:"retryIf,omitempty" yaml:"retryIf,omitempty" description:"重试条件表达式"`
+}
+
+type SRetryOn struct {
+	ExitCodes []int64 `json:"exitCodes,omitempty" yaml:"exitCodes,omitempty" description:"退出码"`
+	Timeout   bool    `json:"timeout,omitempty" yaml:"timeout,omitempty" description:"超时"`
+	Pattern   string  `json:"pattern,omitempty" yaml:"pattern,omitempty" description:"匹配错误信息或最后几行日志的正则"`
+	LogLines  int     `json:"logLines,omitempty" yaml:"logLines,omitempty" description:"正则匹配的日志行数, 默认20"`
 }
 
 type SStepsReq []*SStepReq
 
+type SApprovalReq struct {
+	Approver string `json:"approver" form:"approver" yaml:"approver" binding:"required"`
+	Comment  string `json:"comment,omitempty" form:"comment" yaml:"comment,omitempty"`
+}
+
+type SApprovalRes struct {
+	Approved bool   `json:"approved" yaml:"approved"`
+	Approver string `json:"approver" yaml:"approver"`
+	Comment  string `json:"comment,omitempty" yaml:"comment,omitempty"`
+	Time     string `json:"time" yaml:"time"`
+}
+
 type SStepLogRes struct {
 	Timestamp int64  `json:"timestamp" yaml:"timestamp"`
 	Line      int64  `json:"line" yaml:"line"`
@@ -52,3 +107,13 @@ type SStepLogRes struct {
 }
 
 type SStepLogsRes []*SStepLogRes
+
+type SAttemptRes struct {
+	Attempt  int       `json:"attempt" yaml:"attempt"`
+	State    string    `json:"state" yaml:"state"`
+	Code     int64     `json:"code" yaml:"code"`
+	Message  string    `json:"message" yaml:"message"`
+	LogStart int64     `json:"logStart" yaml:"logStart"`
+	LogEnd   int64     `json:"logEnd" yaml:"logEnd"`
+	Time     *STimeRes `json:"time,omitempty" yaml:"time,omitempty"`
+}
@@ -15,27 +15,86 @@ type STaskListDetailRes struct {
 type STasksRes []*STaskRes
 
 type STaskRes struct {
-	Kind    string        `json:"kind" yaml:"kind"`
-	Name    string        `json:"name" yaml:"name"`
-	State   string        `json:"state" yaml:"state"`
-	Count   int64         `json:"count,omitempty" yaml:"count,omitempty"`
-	Desc    string        `json:"desc,omitempty" yaml:"desc,omitempty"`
-	Node    string        `json:"node,omitempty" yaml:"node,omitempty"`
-	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
-	Disable bool          `json:"disable,omitempty" yaml:"disable,omitempty"`
-	Message string        `json:"message" yaml:"message"`
-	Env     SEnvs         `json:"env,omitempty" yaml:"env,omitempty"`
-	Time    *STimeRes     `json:"time,omitempty" yaml:"time,omitempty"`
+	Kind              string            `json:"kind" yaml:"kind"`
+	Name              string            `json:"name" yaml:"name"`
+	State             string            `json:"state" yaml:"state"`
+	Count             int64             `json:"count,omitempty" yaml:"count,omitempty"`
+	Desc              string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Node              string            `json:"node,omitempty" yaml:"node,omitempty"`
+	Timeout           time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Disable           bool              `json:"disable,omitempty" yaml:"disable,omitempty"`
+	FailFast          bool              `json:"failFast,omitempty" yaml:"failFast,omitempty"`
+	Message           string            `json:"message" yaml:"message"`
+	ConcurrencyGroup  string            `json:"concurrencyGroup,omitempty" yaml:"concurrencyGroup,omitempty"`
+	ConcurrencyPolicy string            `json:"concurrencyPolicy,omitempty" yaml:"concurrencyPolicy,omitempty"`
+	DependsOnTasks    []*STaskDependRes `json:"dependsOnTasks,omitempty" yaml:"dependsOnTasks,omitempty"`
+	Env               SEnvs             `json:"env,omitempty" yaml:"env,omitempty"`
+	Time              *STimeRes         `json:"time,omitempty" yaml:"time,omitempty"`
+}
+
+type STaskRunsRes []*STaskRunRes
+
+// STaskRunRes 任务的一次执行, 最近一次为当前任务
+type STaskRunRes struct {
+	Run     int64     `json:"run" yaml:"run"`
+	Latest  bool      `json:"latest,omitempty" yaml:"latest,omitempty"`
+	State   string    `json:"state" yaml:"state"`
+	Count   int64     `json:"count,omitempty" yaml:"count,omitempty"`
+	Message string    `json:"message" yaml:"message"`
+	Time    *STimeRes `json:"time,omitempty" yaml:"time,omitempty"`
 }
 
 type STaskReq struct {
-	Delayed time.Time     `json:"delayed,omitempty" form:"delayed" yaml:"delayed,omitempty"`
-	Kind    string        `json:"kind,omitempty" form:"kind" yaml:"kind,omitempty"`
-	Name    string        `json:"name,omitempty" form:"name" yaml:"name,omitempty"`
-	Desc    string        `json:"desc,omitempty" form:"desc" yaml:"desc,omitempty"`
-	Node    string        `json:"node,omitempty" form:"node" yaml:"node,omitempty"`
-	Disable bool          `json:"disable,omitempty" form:"disable" yaml:"disable,omitempty"`
-	Timeout time.Duration `json:"timeout,omitempty" form:"timeout,omitempty" yaml:"timeout,omitempty"`
-	Env     SEnvs         `json:"env,omitempty" form:"env" yaml:"env,omitempty"`
-	Step    SStepsReq     `json:"step,omitempty" form:"step" yaml:"step,omitempty" binding:"required"`
+	Delayed           time.Time         `json:"delayed,omitempty" form:"delayed" yaml:"delayed,omitempty"`
+	Kind              string            `json:"kind,omitempty" form:"kind" yaml:"kind,omitempty"`
+	Name              string            `json:"name,omitempty" form:"name" yaml:"name,omitempty"`
+	Desc              string            `json:"desc,omitempty" form:"desc" yaml:"desc,omitempty"`
+	Node              string            `json:"node,omitempty" form:"node" yaml:"node,omitempty"`
+	Disable           bool              `json:"disable,omitempty" form:"disable" yaml:"disable,omitempty"`
+	FailFast          bool              `json:"failFast,omitempty" form:"failFast" yaml:"failFast,omitempty"`
+	Timeout           time.Duration     `json:"timeout,omitempty" form:"timeout,omitempty" yaml:"timeout,omitempty"`
+	ConcurrencyGroup  string            `json:"concurrencyGroup,omitempty" form:"concurrencyGroup" yaml:"concurrencyGroup,omitempty"`
+	ConcurrencyPolicy string            `json:"concurrencyPolicy,omitempty" form:"concurrencyPolicy" yaml:"concurrencyPolicy,omitempty"`
+	DependsOnTasks    []*STaskDependReq `json:"dependsOnTasks,omitempty" form:"dependsOnTasks" yaml:"dependsOnTasks,omitempty"`
+	Env               SEnvs             `json:"env,omitempty" form:"env" yaml:"env,omitempty"`
+	Step              SStepsReq         `json:"step,omitempty" form:"step" yaml:"step,omitempty" binding:"required"`
+	OnSuccess         SStepsReq         `json:"onSuccess,omitempty" form:"onSuccess" yaml:"onSuccess,omitempty"`
+	OnFailure         SStepsReq         `json:"onFailure,omitempty" form:"onFailure" yaml:"onFailure,omitempty"`
+	Finally           SStepsReq         `json:"finally,omitempty" form:"finally" yaml:"finally,omitempty"`
+}
+
+// STaskDependReq 前置任务, when为on_success(默认)、on_failure或always
+type STaskDependReq struct {
+	Name string `json:"name" form:"name" yaml:"name" binding:"required"`
+	When string `json:"when,omitempty" form:"when" yaml:"when,omitempty"`
+}
+
+type STaskDependRes struct {
+	Name    string `json:"name" yaml:"name"`
+	When    string `json:"when" yaml:"when"`
+	State   string `json:"state" yaml:"state"`
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type STaskPlanRes struct {
+	Name     string          `json:"name" yaml:"name"`
+	Kind     string          `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Timeout  time.Duration   `json:"timeout" yaml:"timeout"`
+	Valid    bool            `json:"valid" yaml:"valid"`
+	Errors   []string        `json:"errors,omitempty" yaml:"errors,omitempty"`
+	Warnings []string        `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Levels   [][]string      `json:"levels,omitempty" yaml:"levels,omitempty"`
+	Steps    []*SPlanStepRes `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+type SPlanStepRes struct {
+	Name     string        `json:"name" yaml:"name"`
+	Type     string        `json:"type" yaml:"type"`
+	Level    int           `json:"level" yaml:"level"`
+	Timeout  time.Duration `json:"timeout" yaml:"timeout"`
+	Priority int           `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Height   int           `json:"height" yaml:"height"`
+	Disable  bool          `json:"disable,omitempty" yaml:"disable,omitempty"`
+	Group    string        `json:"group,omitempty" yaml:"group,omitempty"`
+	Depends  []string      `json:"depends,omitempty" yaml:"depends,omitempty"`
 }
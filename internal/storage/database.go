@@ -3,6 +3,7 @@ package storage
 import (
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -45,7 +46,17 @@ func (d *sDatabase) Name() string {
 	return d.DB.Name()
 }
 
-func (d *sDatabase) FixDatabase(node string) (err error) {
+// 重启时无需处理的任务状态, 排队中的任务尚未调度, 继续排队
+var finishedStates = []models.State{models.StateStopped, models.StateSkipped, models.StateFailed, models.StateQueued}
+
+// dependWaiting 仍有前置任务未结束的任务, 尚未提交执行
+func dependWaiting(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.STaskDepend{}).
+		Select("task_name").
+		Where("state = ?", models.StatePending)
+}
+
+func (d *sDatabase) FixDatabase(node string) (adopted []string, err error) {
 	// 开始事务
 	tx := d.Begin()
 	defer func() {
@@ -56,41 +67,122 @@ func (d *sDatabase) FixDatabase(node string) (err error) {
 		}
 	}()
 
+	// 本节点上执行中或挂起的任务, 重新接管执行
+	if err = tx.Model(&models.STask{}).
+		Where("node = ? AND (state = ? OR state = ?)", node, models.StateRunning, models.StatePaused).
+		Pluck("name", &adopted).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// 其他未结束的任务
+	unfinished := tx.Model(&models.STask{}).Select("name").
+		Where("(node IS NULL OR node = ?) AND state NOT IN (?)", node, finishedStates).
+		Where("name NOT IN (?)", dependWaiting(tx))
+	if len(adopted) != 0 {
+		unfinished = unfinished.Where("name NOT IN (?)", adopted)
+	}
+
 	// 更新所有符合条件的步骤状态为失败
 	if err = tx.Model(&models.SStep{}).
-		Where("task_name IN (?)",
-			d.Model(&models.STask{}).Select("name").
-				Where("(node IS NULL OR node = ?) AND (state <> ? AND state <> ? AND state <> ?)", node, models.StateStopped, models.StateSkipped, models.StateFailed),
-		).
-		Where("state = ? OR state = ?", models.StateRunning, models.StatePaused).
+		Where("task_name IN (?)", unfinished).
+		Where("state IN (?)", []models.State{models.StateRunning, models.StatePaused, models.StateWaiting}).
 		Updates(map[string]interface{}{
 			"state":   models.StateFailed,
 			"code":    common.ExecCodeSystemErr,
 			"message": "execution failed due to system error",
 		}).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	// 更新所有符合条件的任务状态为失败
-	if err = tx.Model(&models.STask{}).
-		Where("(node IS NULL OR node = ?) AND (state <> ? AND state <> ? AND state <> ?)", node, models.StateStopped, models.StateSkipped, models.StateFailed).
-		Updates(map[string]interface{}{
-			"node":    node,
-			"state":   models.StateFailed,
-			"message": "execution failed due to system error",
-		}).Error; err != nil {
+	failed := tx.Model(&models.STask{}).
+		Where("(node IS NULL OR node = ?) AND state NOT IN (?)", node, finishedStates).
+		Where("name NOT IN (?)", dependWaiting(tx))
+	if len(adopted) != 0 {
+		failed = failed.Where("name NOT IN (?)", adopted)
+	}
+	if err = failed.Updates(map[string]interface{}{
+		"node":    node,
+		"state":   models.StateFailed,
+		"message": "execution failed due to system error",
+	}).Error; err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
+	}
+
+	if len(adopted) != 0 {
+		if err = adoptSteps(tx, adopted); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		// 重新接管的任务等待再次执行, 挂起的任务保持挂起, 解挂后继续执行
+		if err = tx.Model(&models.STask{}).
+			Where("name IN (?)", adopted).
+			Where("state = ?", models.StatePaused).
+			Updates(map[string]interface{}{
+				"old_state": models.StateRunning,
+				"message":   "the task is re-adopted after worker restart and remains paused",
+			}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err = tx.Model(&models.STask{}).
+			Where("name IN (?)", adopted).
+			Where("state <> ?", models.StatePaused).
+			Updates(map[string]interface{}{
+				"state":     models.StatePending,
+				"old_state": models.StateRunning,
+				"message":   "the task is re-adopted after worker restart",
+			}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	// 提交事务
 	if err = tx.Commit().Error; err != nil {
-		return err
+		return nil, err
 	}
 	return
 }
 
+// adoptSteps 重置重新接管任务中未结束的步骤, 中断的幂等步骤重新执行, 其他中断的步骤标记为失败
+func adoptSteps(tx *gorm.DB, adopted []string) error {
+	// 等待审批的步骤重新等待, 已有的审批记录继续生效
+	if err := tx.Model(&models.SStep{}).
+		Where("task_name IN (?)", adopted).
+		Where("state = ? OR (state = ? AND old_state = ?)", models.StateWaiting, models.StatePaused, models.StateWaiting).
+		Updates(map[string]interface{}{
+			"state":   models.StatePending,
+			"message": "the step will wait for approval again after worker restart",
+		}).Error; err != nil {
+		return err
+	}
+	interrupted := "state = ? OR (state = ? AND old_state = ?)"
+	if err := tx.Model(&models.SStep{}).
+		Where("task_name IN (?)", adopted).
+		Where(interrupted, models.StateRunning, models.StatePaused, models.StateRunning).
+		Where("idempotent = ?", true).
+		Updates(map[string]interface{}{
+			"state":   models.StatePending,
+			"code":    common.ExecCodeSuccess,
+			"message": "the step will be re-executed after worker restart",
+		}).Error; err != nil {
+		return err
+	}
+	// 挂起但未开始执行的步骤保持挂起, 由接管后的任务恢复
+	return tx.Model(&models.SStep{}).
+		Where("task_name IN (?)", adopted).
+		Where(interrupted, models.StateRunning, models.StatePaused, models.StateRunning).
+		Updates(map[string]interface{}{
+			"state":   models.StateFailed,
+			"code":    common.ExecCodeSystemErr,
+			"message": "execution interrupted by worker restart",
+		}).Error
+}
+
 func (d *sDatabase) NodeTasks(node string) (res []ITask) {
 	var tasks []string
 	d.Model(&models.STask{}).Where("node = ?", node).Pluck("name", &tasks)
@@ -103,6 +195,18 @@ func (d *sDatabase) NodeTasks(node string) (res []ITask) {
 	return
 }
 
+func (d *sDatabase) Node(name string) INode {
+	return &sNode{
+		DB:   d.DB,
+		name: name,
+	}
+}
+
+func (d *sDatabase) NodeList() (res models.SNodes) {
+	d.Model(&models.SNode{}).Order("name ASC").Find(&res)
+	return
+}
+
 func (d *sDatabase) Task(name string) ITask {
 	return &sTask{
 		DB:    d.DB,
@@ -147,6 +251,109 @@ func (d *sDatabase) TaskList(page, pageSize int64, str string) (res models.STask
 	return
 }
 
+// 并发组中占用执行权的任务状态
+var groupActiveStates = []models.State{models.StateRunning, models.StatePending, models.StatePaused}
+
+func (d *sDatabase) GroupTasks(group string) (res models.STasks) {
+	d.Model(&models.STask{}).
+		Where("concurrency_group = ?", group).
+		Where("state IN (?)", append(groupActiveStates, models.StateQueued)).
+		Order("id ASC").
+		Find(&res)
+	return
+}
+
+func (d *sDatabase) GroupDequeue(group string) (task *models.STask, err error) {
+	err = d.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&models.STask{}).
+			Where("concurrency_group = ?", group).
+			Where("state IN (?)", groupActiveStates).
+			Where("name NOT IN (?)", dependWaiting(tx)).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active != 0 {
+			return nil
+		}
+		var next = new(models.STask)
+		if err := tx.Model(&models.STask{}).
+			Where("concurrency_group = ? AND state = ?", group, models.StateQueued).
+			Order("id ASC").
+			Limit(1).
+			Find(next).Error; err != nil {
+			return err
+		}
+		if next.Name == "" {
+			return nil
+		}
+		// 仅排队状态的任务可被取出, 防止重复调度
+		res := tx.Model(&models.STask{}).
+			Where("name = ? AND state = ?", next.Name, models.StateQueued).
+			Updates(map[string]interface{}{
+				"state":     models.StatePending,
+				"old_state": models.StateQueued,
+				"message":   "the task is waiting to be scheduled for execution",
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			task = next
+		}
+		return nil
+	})
+	return
+}
+
+func (d *sDatabase) QueuedGroups() (groups []string) {
+	d.Model(&models.STask{}).
+		Where("state = ? AND concurrency_group <> ''", models.StateQueued).
+		Distinct("concurrency_group").
+		Pluck("concurrency_group", &groups)
+	return
+}
+
+func (d *sDatabase) DependTasks() (tasks []string) {
+	d.Model(&models.STask{}).
+		Where("state = ?", models.StatePending).
+		Where("name IN (?)", dependWaiting(d.DB)).
+		Order("id ASC").
+		Pluck("name", &tasks)
+	return
+}
+
+// 已结束的任务状态
+var endedStates = []models.State{models.StateStopped, models.StateSkipped, models.StateFailed}
+
+func (d *sDatabase) ExpiredTasks(before time.Time, limit int) (tasks []string) {
+	d.Model(&models.STask{}).
+		Where("state IN (?)", endedStates).
+		Where("COALESCE(e_time, updated_at) < ?", before).
+		Where("name NOT IN (?)", d.Model(&models.STaskDepend{}).
+			Select("name").
+			Where("state = ?", models.StatePending)).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("name", &tasks)
+	return
+}
+
+func (d *sDatabase) OversizedLogs(lines int64) models.SStepLogs {
+	return logBackend.oversized(d.Model(&models.STask{}).
+		Select("name").
+		Where("state IN (?)", endedStates), lines)
+}
+
+func (d *sDatabase) ExpiredRuns(keep int) (tasks []string) {
+	d.Model(&models.STaskRun{}).
+		Select("task_name").
+		Group("task_name").
+		Having("COUNT(*) > ?", keep).
+		Pluck("task_name", &tasks)
+	return
+}
+
 func (d *sDatabase) Pipeline(name string) IPipeline {
 	return &sPipeline{
 		DB:   d.DB,
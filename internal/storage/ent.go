@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/glebarez/sqlite"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/schema"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskstep"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+const (
+	EngineGorm = "gorm"
+	EngineEnt  = "ent"
+)
+
+// SetEngine 设置任务及步骤的存储引擎
+// ent 时任务、步骤定义、步骤执行记录、环境变量、步骤依赖及日志存储在 ent 的表中,
+// 节点、流水线、前置任务、审批、重试记录及输出变量仍由 gorm 管理
+func SetEngine(kind string) error {
+	db, ok := storage.(*sDatabase)
+	if !ok {
+		return fmt.Errorf("storage is not initialized")
+	}
+	switch kind {
+	case EngineGorm, "":
+		return nil
+	case EngineEnt:
+		client, err := openEnt(db.DB)
+		if err != nil {
+			return err
+		}
+		if err = client.Schema.Create(context.Background()); err != nil {
+			_ = client.Close()
+			return fmt.Errorf("migrate ent schema error: %s", err)
+		}
+		storage = &sEntStorage{sDatabase: db, client: client}
+		logBackend = &sEntLog{Client: client, runs: &sDBLog{DB: db.DB}}
+		return nil
+	default:
+		return fmt.Errorf("unsupported storage engine %s", kind)
+	}
+}
+
+// openEnt 创建 ent 客户端, sqlite 使用独立的连接池以开启外键约束, 其他数据库复用 gorm 的连接池
+func openEnt(gdb *gorm.DB) (*entv1.Client, error) {
+	switch gdb.Name() {
+	case TypeSqlite:
+		dsn := gdb.Dialector.(*sqlite.Dialector).DSN
+		return entv1.Open(dialect.SQLite, dsn)
+	case TypeMysql, TypePostgres:
+		sqlDB, err := gdb.DB()
+		if err != nil {
+			return nil, err
+		}
+		return entv1.NewClient(entv1.Driver(entsql.OpenDB(gdb.Name(), sqlDB))), nil
+	default:
+		return nil, fmt.Errorf("storage engine %s does not support %s", EngineEnt, gdb.Name())
+	}
+}
+
+// CloseEngine 关闭 ent 单独打开的 sqlite 连接, 在关闭数据库前调用
+func CloseEngine() error {
+	if s, ok := storage.(*sEntStorage); ok && s.Name() == TypeSqlite {
+		return s.client.Close()
+	}
+	return nil
+}
+
+var stateValues = func() map[string]models.State {
+	res := make(map[string]models.State, len(models.StateMap))
+	for k, v := range models.StateMap {
+		res[v] = k
+	}
+	return res
+}()
+
+// toEnum 转换为 ent 的状态枚举
+func toEnum[T ~string](state models.State) T {
+	if v, ok := models.StateMap[state]; ok {
+		return T(v)
+	}
+	return T(schema.Unknown)
+}
+
+// fromEnum 转换为 gorm 模型中的状态
+func fromEnum[T ~string](state *T) *models.State {
+	if state == nil {
+		return models.Pointer(models.StateUnknown)
+	}
+	if v, ok := stateValues[string(*state)]; ok {
+		return &v
+	}
+	return models.Pointer(models.StateUnknown)
+}
+
+// convert 字段一致的结构体之间转换
+func convert[T any](from any) (res T) {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &res)
+	return
+}
+
+func entTaskModel(t *entv1.Task) *models.STask {
+	res := &models.STask{
+		SBase: models.SBase{
+			ID: t.ID,
+		},
+		Name:              t.Name,
+		Kind:              entv1.UnPointer(t.Kind),
+		Desc:              entv1.UnPointer(t.Desc),
+		Node:              entv1.UnPointer(t.Node),
+		Timeout:           time.Duration(entv1.UnPointer(t.Timeout)),
+		Disable:           models.Pointer(entv1.UnPointer(t.Disabled)),
+		FailFast:          models.Pointer(entv1.UnPointer(t.FailFast)),
+		ConcurrencyGroup:  entv1.UnPointer(t.ConcurrencyGroup),
+		ConcurrencyPolicy: entv1.UnPointer(t.ConcurrencyPolicy),
+		Metadata:          t.Metadata,
+		STaskUpdate: models.STaskUpdate{
+			Message:  entv1.UnPointer(t.Message),
+			State:    fromEnum(t.State),
+			OldState: fromEnum(t.PreviousState),
+			STime:    t.StartTime,
+			ETime:    t.EndTime,
+		},
+	}
+	if t.CreatedAt != nil {
+		res.CreatedAt = *t.CreatedAt
+	}
+	if t.UpdatedAt != nil {
+		res.UpdatedAt = *t.UpdatedAt
+	}
+	return res
+}
+
+// entStepModel 合并步骤定义及其在任务中的执行记录
+func entStepModel(tName string, ts *entv1.TaskStep) *models.SStep {
+	def := ts.Edges.Step
+	if def == nil {
+		def = new(entv1.Step)
+	}
+	res := &models.SStep{
+		SBase: models.SBase{
+			ID: ts.ID,
+		},
+		TaskName:     tName,
+		Name:         def.Name,
+		Desc:         entv1.UnPointer(def.Desc),
+		Type:         entv1.UnPointer(def.Kind),
+		Content:      entv1.UnPointer(def.Content),
+		Action:       entv1.UnPointer(def.Action),
+		Rule:         entv1.UnPointer(def.Rule),
+		When:         entv1.UnPointer(def.RunWhen),
+		Handler:      entv1.UnPointer(def.Handler),
+		SeqNo:        entv1.UnPointer(ts.SeqNo),
+		Priority:     entv1.UnPointer(def.Priority),
+		Weight:       entv1.UnPointer(def.Weight),
+		Mutex:        entv1.UnPointer(def.Mutex),
+		Timeout:      time.Duration(entv1.UnPointer(def.Timeout)),
+		Disable:      models.Pointer(entv1.UnPointer(ts.Disabled)),
+		AllowFailure: models.Pointer(entv1.UnPointer(def.AllowFailure)),
+		Idempotent:   models.Pointer(entv1.UnPointer(def.Idempotent)),
+		Metadata:     def.Metadata,
+		SStepUpdate: models.SStepUpdate{
+			Message:  entv1.UnPointer(ts.Message),
+			State:    fromEnum(ts.State),
+			OldState: fromEnum(ts.PreviousState),
+			Code:     models.Pointer(common.ExecCode(entv1.UnPointer(ts.Code))),
+			STime:    ts.StartTime,
+			ETime:    ts.EndTime,
+		},
+	}
+	if def.RetryPolicy != nil {
+		res.RetryPolicy = datatypes.NewJSONType(convert[models.SRetryPolicy](def.RetryPolicy))
+	}
+	if def.Matrix != nil {
+		res.Matrix = datatypes.NewJSONType(convert[models.SMatrix](def.Matrix))
+	}
+	if def.Foreach != nil {
+		res.Foreach = datatypes.NewJSONType(convert[models.SForeach](def.Foreach))
+	}
+	if ts.CreatedAt != nil {
+		res.CreatedAt = *ts.CreatedAt
+	}
+	if ts.UpdatedAt != nil {
+		res.UpdatedAt = *ts.UpdatedAt
+	}
+	return res
+}
+
+// stepExec 任务中指定步骤的执行记录
+func stepExec(tName, sName string) []predicate.TaskStep {
+	return []predicate.TaskStep{
+		taskstep.HasTaskWith(task.Name(tName)),
+		taskstep.HasStepWith(step.Name(sName)),
+	}
+}
+
+// stepWas 处于 state 状态或在 state 状态时被挂起的步骤
+func stepWas(state models.State) predicate.TaskStep {
+	return taskstep.Or(
+		taskstep.StateEQ(toEnum[taskstep.State](state)),
+		taskstep.And(
+			taskstep.StateEQ(toEnum[taskstep.State](models.StatePaused)),
+			taskstep.PreviousStateEQ(toEnum[taskstep.PreviousState](state)),
+		),
+	)
+}
+
+// withTx 在事务中执行 fn, 出错或 panic 时回滚
+func withTx(ctx context.Context, client *entv1.Client, fn func(tx *entv1.Tx) error) (err error) {
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logx.Errorln(r, string(debug.Stack()))
+			_ = tx.Rollback()
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
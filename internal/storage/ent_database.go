@@ -0,0 +1,411 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskstep"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility/sid"
+)
+
+// sEntStorage 任务及步骤存储在 ent 的表中, 其他数据沿用 gorm
+type sEntStorage struct {
+	*sDatabase
+	client *entv1.Client
+}
+
+func taskStates(states ...models.State) []task.State {
+	res := make([]task.State, 0, len(states))
+	for _, state := range states {
+		res = append(res, toEnum[task.State](state))
+	}
+	return res
+}
+
+func stepStates(states ...models.State) []taskstep.State {
+	res := make([]taskstep.State, 0, len(states))
+	for _, state := range states {
+		res = append(res, toEnum[taskstep.State](state))
+	}
+	return res
+}
+
+// dependWaitingNames 仍有前置任务未结束的任务名称
+func (d *sEntStorage) dependWaitingNames(tx *gorm.DB) (res []string) {
+	dependWaiting(tx).Pluck("task_name", &res)
+	return
+}
+
+// paginate 与 models.Paginate 一致的分页参数, limit 为 0 时不分页
+func paginate(page, pageSize int64) (offset, limit int) {
+	if page == -1 {
+		return 0, 0
+	}
+	if page == 0 {
+		page = 1
+	}
+	switch {
+	case pageSize > 500:
+		pageSize = 500
+	case pageSize <= 0:
+		pageSize = 10
+	}
+	return int((page - 1) * pageSize), int(pageSize)
+}
+
+func (d *sEntStorage) FixDatabase(node string) (adopted []string, err error) {
+	ctx := context.Background()
+	waiting := d.dependWaitingNames(d.DB)
+	err = withTx(ctx, d.client, func(tx *entv1.Tx) error {
+		// 本节点上执行中或挂起的任务, 重新接管执行
+		var err error
+		adopted, err = tx.Task.Query().
+			Where(
+				task.Node(node),
+				task.StateIn(taskStates(models.StateRunning, models.StatePaused)...),
+			).
+			Select(task.FieldName).
+			Strings(ctx)
+		if err != nil {
+			return err
+		}
+
+		// 其他未结束的任务
+		unfinished := []predicate.Task{
+			task.Or(task.NodeIsNil(), task.Node(node)),
+			task.StateNotIn(taskStates(finishedStates...)...),
+			task.NameNotIn(waiting...),
+			task.NameNotIn(adopted...),
+		}
+
+		// 更新所有符合条件的步骤状态为失败
+		if _, err = tx.TaskStep.Update().
+			Where(
+				taskstep.HasTaskWith(unfinished...),
+				taskstep.StateIn(stepStates(models.StateRunning, models.StatePaused, models.StateWaiting)...),
+			).
+			SetState(toEnum[taskstep.State](models.StateFailed)).
+			SetCode(int64(common.ExecCodeSystemErr)).
+			SetMessage("execution failed due to system error").
+			Save(ctx); err != nil {
+			return err
+		}
+
+		// 更新所有符合条件的任务状态为失败
+		if _, err = tx.Task.Update().
+			Where(unfinished...).
+			SetNode(node).
+			SetState(toEnum[task.State](models.StateFailed)).
+			SetMessage("execution failed due to system error").
+			Save(ctx); err != nil {
+			return err
+		}
+
+		if len(adopted) == 0 {
+			return nil
+		}
+		if err = entAdoptSteps(ctx, tx, adopted); err != nil {
+			return err
+		}
+		// 重新接管的任务等待再次执行, 挂起的任务保持挂起, 解挂后继续执行
+		paused := toEnum[task.State](models.StatePaused)
+		if _, err = tx.Task.Update().
+			Where(task.NameIn(adopted...), task.StateEQ(paused)).
+			SetPreviousState(toEnum[task.PreviousState](models.StateRunning)).
+			SetMessage("the task is re-adopted after worker restart and remains paused").
+			Save(ctx); err != nil {
+			return err
+		}
+		_, err = tx.Task.Update().
+			Where(task.NameIn(adopted...), task.StateNEQ(paused)).
+			SetState(toEnum[task.State](models.StatePending)).
+			SetPreviousState(toEnum[task.PreviousState](models.StateRunning)).
+			SetMessage("the task is re-adopted after worker restart").
+			Save(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// entAdoptSteps 与 adoptSteps 一致, 重置重新接管任务中未结束的步骤
+func entAdoptSteps(ctx context.Context, tx *entv1.Tx, adopted []string) error {
+	inTasks := taskstep.HasTaskWith(task.NameIn(adopted...))
+	// 等待审批的步骤重新等待, 已有的审批记录继续生效
+	if _, err := tx.TaskStep.Update().
+		Where(inTasks, stepWas(models.StateWaiting)).
+		SetState(toEnum[taskstep.State](models.StatePending)).
+		SetMessage("the step will wait for approval again after worker restart").
+		Save(ctx); err != nil {
+		return err
+	}
+	// 步骤定义中标记为幂等的步骤
+	idempotent, err := tx.TaskStep.Query().
+		Where(inTasks, stepWas(models.StateRunning), taskstep.HasStepWith(step.Idempotent(true))).
+		IDs(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.TaskStep.Update().
+		Where(taskstep.IDIn(idempotent...)).
+		SetState(toEnum[taskstep.State](models.StatePending)).
+		SetCode(int64(common.ExecCodeSuccess)).
+		SetMessage("the step will be re-executed after worker restart").
+		Save(ctx); err != nil {
+		return err
+	}
+	// 挂起但未开始执行的步骤保持挂起, 由接管后的任务恢复
+	_, err = tx.TaskStep.Update().
+		Where(inTasks, stepWas(models.StateRunning)).
+		SetState(toEnum[taskstep.State](models.StateFailed)).
+		SetCode(int64(common.ExecCodeSystemErr)).
+		SetMessage("execution interrupted by worker restart").
+		Save(ctx)
+	return err
+}
+
+func (d *sEntStorage) NodeTasks(node string) (res []ITask) {
+	tasks, _ := d.client.Task.Query().
+		Where(task.Node(node)).
+		Select(task.FieldName).
+		Strings(context.Background())
+	for _, name := range tasks {
+		res = append(res, d.Task(name))
+	}
+	return
+}
+
+func (d *sEntStorage) Task(name string) ITask {
+	return &sEntTask{
+		DB:     d.DB,
+		client: d.client,
+		tName:  name,
+	}
+}
+
+func (d *sEntStorage) TaskCreate(t *models.STask) (err error) {
+	if t.Node == "random" {
+		t.Node = ""
+	}
+	if t.Timeout == 0 {
+		t.Timeout = 24 * time.Hour
+	}
+	if t.ID == 0 {
+		if t.ID, err = sid.NextID(task.Table); err != nil {
+			return err
+		}
+	}
+	create := d.client.Task.Create().
+		SetID(t.ID).
+		SetName(t.Name).
+		SetKind(t.Kind).
+		SetDesc(t.Desc).
+		SetTimeout(int64(t.Timeout)).
+		SetDisabled(entv1.UnPointer(t.Disable)).
+		SetFailFast(entv1.UnPointer(t.FailFast)).
+		SetConcurrencyGroup(t.ConcurrencyGroup).
+		SetConcurrencyPolicy(t.ConcurrencyPolicy).
+		SetMetadata(t.Metadata).
+		SetMessage(t.Message).
+		SetNillableStartTime(t.STime).
+		SetNillableEndTime(t.ETime)
+	if t.Node != "" {
+		create.SetNode(t.Node)
+	}
+	if t.State != nil {
+		create.SetState(toEnum[task.State](*t.State))
+	}
+	if t.OldState != nil {
+		create.SetPreviousState(toEnum[task.PreviousState](*t.OldState))
+	}
+	if !t.CreatedAt.IsZero() {
+		create.SetCreatedAt(t.CreatedAt)
+	}
+	if err = create.Exec(context.Background()); err != nil {
+		return fmt.Errorf("save task %d error: %s", t.ID, err)
+	}
+	return
+}
+
+func (d *sEntStorage) TaskCount(state models.State) (res int64) {
+	query := d.client.Task.Query()
+	if state != models.StateAll {
+		query.Where(task.StateEQ(toEnum[task.State](state)))
+	}
+	count, _ := query.Count(context.Background())
+	return int64(count)
+}
+
+func (d *sEntStorage) TaskList(page, pageSize int64, str string) (res models.STasks, total int64) {
+	ctx := context.Background()
+	count, err := d.client.Task.Query().Count(ctx)
+	if err != nil {
+		return
+	}
+	total = int64(count)
+	query := d.client.Task.Query().
+		Order(task.ByID(entsql.OrderDesc())).
+		Select(
+			task.FieldID,
+			task.FieldName,
+			task.FieldKind,
+			task.FieldState,
+			task.FieldMessage,
+			task.FieldStartTime,
+			task.FieldEndTime,
+		)
+	if str != "" {
+		query.Where(task.NameHasPrefix(str))
+	}
+	if offset, limit := paginate(page, pageSize); limit != 0 {
+		query.Offset(offset).Limit(limit)
+	}
+	tasks, _ := query.All(ctx)
+	for _, t := range tasks {
+		res = append(res, entTaskModel(t))
+	}
+	return
+}
+
+func (d *sEntStorage) GroupTasks(group string) (res models.STasks) {
+	tasks, _ := d.client.Task.Query().
+		Where(
+			task.ConcurrencyGroup(group),
+			task.StateIn(taskStates(append(groupActiveStates, models.StateQueued)...)...),
+		).
+		Order(task.ByID()).
+		All(context.Background())
+	for _, t := range tasks {
+		res = append(res, entTaskModel(t))
+	}
+	return
+}
+
+func (d *sEntStorage) GroupDequeue(group string) (res *models.STask, err error) {
+	ctx := context.Background()
+	waiting := d.dependWaitingNames(d.DB)
+	err = withTx(ctx, d.client, func(tx *entv1.Tx) error {
+		active, err := tx.Task.Query().
+			Where(
+				task.ConcurrencyGroup(group),
+				task.StateIn(taskStates(groupActiveStates...)...),
+				task.NameNotIn(waiting...),
+			).
+			Count(ctx)
+		if err != nil || active != 0 {
+			return err
+		}
+		next, err := tx.Task.Query().
+			Where(
+				task.ConcurrencyGroup(group),
+				task.StateEQ(toEnum[task.State](models.StateQueued)),
+			).
+			Order(task.ByID()).
+			First(ctx)
+		if err != nil {
+			if entv1.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		// 仅排队状态的任务可被取出, 防止重复调度
+		affected, err := tx.Task.Update().
+			Where(
+				task.Name(next.Name),
+				task.StateEQ(toEnum[task.State](models.StateQueued)),
+			).
+			SetState(toEnum[task.State](models.StatePending)).
+			SetPreviousState(toEnum[task.PreviousState](models.StateQueued)).
+			SetMessage("the task is waiting to be scheduled for execution").
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		if affected == 1 {
+			res = entTaskModel(next)
+		}
+		return nil
+	})
+	return
+}
+
+func (d *sEntStorage) QueuedGroups() (groups []string) {
+	groups, _ = d.client.Task.Query().
+		Where(
+			task.StateEQ(toEnum[task.State](models.StateQueued)),
+			task.ConcurrencyGroupNEQ(""),
+		).
+		Unique(true).
+		Select(task.FieldConcurrencyGroup).
+		Strings(context.Background())
+	return
+}
+
+func (d *sEntStorage) DependTasks() (tasks []string) {
+	waiting := d.dependWaitingNames(d.DB)
+	if len(waiting) == 0 {
+		return
+	}
+	tasks, _ = d.client.Task.Query().
+		Where(
+			task.StateEQ(toEnum[task.State](models.StatePending)),
+			task.NameIn(waiting...),
+		).
+		Order(task.ByID()).
+		Select(task.FieldName).
+		Strings(context.Background())
+	return
+}
+
+func (d *sEntStorage) ExpiredTasks(before time.Time, limit int) (tasks []string) {
+	var depended []string
+	d.Model(&models.STaskDepend{}).
+		Where("state = ?", models.StatePending).
+		Pluck("name", &depended)
+	tasks, _ = d.client.Task.Query().
+		Where(
+			task.StateIn(taskStates(endedStates...)...),
+			task.Or(
+				task.EndTimeLT(before),
+				task.And(task.EndTimeIsNil(), task.UpdatedAtLT(before)),
+			),
+			task.NameNotIn(depended...),
+		).
+		Order(task.ByID()).
+		Limit(limit).
+		Select(task.FieldName).
+		Strings(context.Background())
+	return
+}
+
+func (d *sEntStorage) OversizedLogs(lines int64) models.SStepLogs {
+	var ended []string
+	for _, state := range endedStates {
+		ended = append(ended, models.StateMap[state])
+	}
+	return logBackend.oversized(d.Table(task.Table).
+		Select("name").
+		Where("state IN (?)", ended), lines)
+}
+
+func (d *sEntStorage) Pipeline(name string) IPipeline {
+	return &sEntPipeline{
+		sPipeline: &sPipeline{
+			DB:   d.DB,
+			name: name,
+		},
+		storage: d,
+	}
+}
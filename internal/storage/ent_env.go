@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/param"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/stepparam"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskparam"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility/sid"
+)
+
+// sEntParams 任务或步骤的参数, 通过 task_params/step_params 关联
+type sEntParams struct {
+	client *entv1.Client
+	// owner 参数的归属条件
+	owner predicate.Param
+	// link 将新建的参数关联到任务或步骤
+	link func(ctx context.Context, tx *entv1.Tx, paramID uint64) error
+}
+
+func (p *sEntParams) list() (res models.SEnvs) {
+	params, _ := p.client.Param.Query().
+		Where(p.owner).
+		Order(param.ByID()).
+		All(context.Background())
+	for _, v := range params {
+		res = append(res, &models.SEnv{
+			Name:  v.Name,
+			Value: entv1.UnPointer(v.Value),
+		})
+	}
+	return
+}
+
+// insert 已存在的参数更新值
+func (p *sEntParams) insert(envs ...*models.SEnv) error {
+	if len(envs) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	return withTx(ctx, p.client, func(tx *entv1.Tx) error {
+		for _, env := range envs {
+			affected, err := tx.Param.Update().
+				Where(p.owner, param.Name(env.Name)).
+				SetValue(env.Value).
+				Save(ctx)
+			if err != nil {
+				return err
+			}
+			if affected != 0 {
+				continue
+			}
+			id, err := sid.NextID(param.Table)
+			if err != nil {
+				return err
+			}
+			if err = tx.Param.Create().
+				SetID(id).
+				SetName(env.Name).
+				SetValue(env.Value).
+				Exec(ctx); err != nil {
+				return err
+			}
+			if err = p.link(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (p *sEntParams) update(env *models.SEnv) error {
+	_, err := p.client.Param.Update().
+		Where(p.owner, param.Name(env.Name)).
+		SetValue(env.Value).
+		Save(context.Background())
+	return err
+}
+
+func (p *sEntParams) get(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+	v, err := p.client.Param.Query().
+		Where(p.owner, param.Name(name)).
+		First(context.Background())
+	if err != nil {
+		if entv1.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return entv1.UnPointer(v.Value), nil
+}
+
+func (p *sEntParams) remove(preds ...predicate.Param) error {
+	ctx := context.Background()
+	return withTx(ctx, p.client, func(tx *entv1.Tx) error {
+		return entRemoveParams(ctx, tx, append(preds, p.owner)...)
+	})
+}
+
+// entRemoveParams 删除参数及其与任务、步骤的关联
+func entRemoveParams(ctx context.Context, tx *entv1.Tx, preds ...predicate.Param) error {
+	ids, err := tx.Param.Query().Where(preds...).IDs(ctx)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	if _, err = tx.TaskParam.Delete().Where(taskparam.ParamIDIn(ids...)).Exec(ctx); err != nil {
+		return err
+	}
+	if _, err = tx.StepParam.Delete().Where(stepparam.ParamIDIn(ids...)).Exec(ctx); err != nil {
+		return err
+	}
+	_, err = tx.Param.Delete().Where(param.IDIn(ids...)).Exec(ctx)
+	return err
+}
+
+type sEntTaskEnv struct {
+	client *entv1.Client
+	tName  string
+}
+
+func (e *sEntTaskEnv) params() *sEntParams {
+	return &sEntParams{
+		client: e.client,
+		owner:  param.HasTasksWith(task.Name(e.tName)),
+		link: func(ctx context.Context, tx *entv1.Tx, paramID uint64) error {
+			taskID, err := tx.Task.Query().Where(task.Name(e.tName)).OnlyID(ctx)
+			if err != nil {
+				return err
+			}
+			id, err := sid.NextID(taskparam.Table)
+			if err != nil {
+				return err
+			}
+			return tx.TaskParam.Create().
+				SetID(id).
+				SetTaskID(taskID).
+				SetParamID(paramID).
+				Exec(ctx)
+		},
+	}
+}
+
+func (e *sEntTaskEnv) List() (res models.SEnvs) {
+	return e.params().list()
+}
+
+func (e *sEntTaskEnv) Insert(envs ...*models.SEnv) (err error) {
+	return e.params().insert(envs...)
+}
+
+func (e *sEntTaskEnv) Update(env *models.SEnv) (err error) {
+	return e.params().update(env)
+}
+
+func (e *sEntTaskEnv) Get(name string) (res string, err error) {
+	return e.params().get(name)
+}
+
+func (e *sEntTaskEnv) Remove(name string) (err error) {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	return e.params().remove(param.Name(name))
+}
+
+func (e *sEntTaskEnv) RemoveAll() (err error) {
+	return e.params().remove()
+}
+
+type sEntStepEnv struct {
+	client *entv1.Client
+	tName  string
+	sName  string
+}
+
+func (e *sEntStepEnv) params() *sEntParams {
+	return &sEntParams{
+		client: e.client,
+		owner:  param.HasStepsWith(stepDefine(e.tName, e.sName)),
+		link: func(ctx context.Context, tx *entv1.Tx, paramID uint64) error {
+			stepID, err := tx.Step.Query().Where(stepDefine(e.tName, e.sName)).OnlyID(ctx)
+			if err != nil {
+				return err
+			}
+			id, err := sid.NextID(stepparam.Table)
+			if err != nil {
+				return err
+			}
+			return tx.StepParam.Create().
+				SetID(id).
+				SetStepID(stepID).
+				SetParamID(paramID).
+				Exec(ctx)
+		},
+	}
+}
+
+func (e *sEntStepEnv) List() (res models.SEnvs) {
+	return e.params().list()
+}
+
+func (e *sEntStepEnv) Insert(envs ...*models.SEnv) (err error) {
+	return e.params().insert(envs...)
+}
+
+func (e *sEntStepEnv) Update(env *models.SEnv) (err error) {
+	return e.params().update(env)
+}
+
+func (e *sEntStepEnv) Get(name string) (res string, err error) {
+	return e.params().get(name)
+}
+
+func (e *sEntStepEnv) Remove(name string) (err error) {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	return e.params().remove(param.Name(name))
+}
+
+func (e *sEntStepEnv) RemoveAll() (err error) {
+	return e.params().remove()
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// MigrateToEnt 将 t_task/t_step/t_step_log 及任务、步骤的环境变量和步骤依赖复制到 ent 的表中
+// ent 中已存在的任务跳过, 可重复执行; gorm 表中的数据保留, 确认无误后可自行删除
+func MigrateToEnt(ctx context.Context) (migrated int, err error) {
+	var db *sDatabase
+	var client *entv1.Client
+	switch s := storage.(type) {
+	case *sDatabase:
+		db = s
+		if client, err = openEnt(db.DB); err != nil {
+			return
+		}
+		defer client.Close()
+		if err = client.Schema.Create(ctx); err != nil {
+			return 0, fmt.Errorf("migrate ent schema error: %s", err)
+		}
+	case *sEntStorage:
+		db, client = s.sDatabase, s.client
+	default:
+		return 0, fmt.Errorf("storage is not initialized")
+	}
+	src := db
+	dst := &sEntStorage{sDatabase: db, client: client}
+
+	var lastID uint64
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		var tasks models.STasks
+		if err = src.Model(&models.STask{}).
+			Where("id > ?", lastID).
+			Order("id ASC").
+			Limit(100).
+			Find(&tasks).Error; err != nil {
+			return
+		}
+		if len(tasks) == 0 {
+			return
+		}
+		lastID = tasks[len(tasks)-1].ID
+		for _, t := range tasks {
+			exist, err := client.Task.Query().Where(task.Name(t.Name)).Exist(ctx)
+			if err != nil {
+				return migrated, err
+			}
+			if exist {
+				logx.Infof("migrate task %s: already exists, skip", t.Name)
+				continue
+			}
+			if err = migrateTask(src, dst, t); err != nil {
+				// 清理复制了一部分的任务, 下次重新复制
+				_ = dst.Task(t.Name).ClearAll()
+				return migrated, fmt.Errorf("migrate task %s error: %s", t.Name, err)
+			}
+			migrated++
+			logx.Infof("migrate task %s: done", t.Name)
+		}
+	}
+}
+
+func migrateTask(src *sDatabase, dst *sEntStorage, t *models.STask) error {
+	from, to := src.Task(t.Name), dst.Task(t.Name)
+	if err := dst.TaskCreate(t); err != nil {
+		return err
+	}
+	if err := to.Env().Insert(from.Env().List()...); err != nil {
+		return err
+	}
+	steps := from.StepList(All)
+	for _, s := range steps {
+		if err := to.StepCreate(s); err != nil {
+			return err
+		}
+	}
+	for _, s := range steps {
+		fromStep, toStep := from.Step(s.Name), to.Step(s.Name)
+		if err := toStep.Env().Insert(fromStep.Env().List()...); err != nil {
+			return err
+		}
+		if err := toStep.Depend().Insert(fromStep.Depend().List()...); err != nil {
+			return err
+		}
+		if err := migrateLogs(src, dst, t.Name, s.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateLogs 复制数据库中的步骤日志, 行号保持不变; 文件存储的日志无需复制
+func migrateLogs(src *sDatabase, dst *sEntStorage, tName, sName string) error {
+	from := &sDBLog{DB: src.DB}
+	to := &sEntLog{Client: dst.client, runs: from}
+	var latest int64 = -1
+	for {
+		logs := from.list(tName, sName, &latest)
+		if len(logs) == 0 {
+			return nil
+		}
+		if err := to.write(logs); err != nil {
+			return err
+		}
+		latest = *logs[len(logs)-1].Line
+	}
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type testMigrateTask struct {
+	name  string
+	steps []string
+	deps  map[string][]string
+	lines int64
+}
+
+func createTestTask(t *testing.T, task testMigrateTask) {
+	t.Helper()
+	err := TaskCreate(&models.STask{
+		Kind: "dag",
+		Name: task.name,
+		STaskUpdate: models.STaskUpdate{
+			State:    models.Pointer(models.StateStopped),
+			OldState: models.Pointer(models.StateRunning),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := Task(task.name)
+	if err = ts.Env().Insert(&models.SEnv{Name: "TASK", Value: task.name}); err != nil {
+		t.Fatal(err)
+	}
+	logs := &sDBLog{DB: storage.(*sDatabase).DB}
+	for k, name := range task.steps {
+		err = ts.StepCreate(&models.SStep{
+			TaskName: task.name,
+			Name:     name,
+			Type:     "sh",
+			Content:  "echo " + name,
+			SeqNo:    int64(k + 1),
+			SStepUpdate: models.SStepUpdate{
+				Code:     models.Pointer(common.ExecCode(0)),
+				State:    models.Pointer(models.StateStopped),
+				OldState: models.Pointer(models.StateRunning),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss := ts.Step(name)
+		if err = ss.Env().Insert(&models.SEnv{Name: "STEP", Value: name}); err != nil {
+			t.Fatal(err)
+		}
+		if err = ss.Depend().Insert(task.deps[name]...); err != nil {
+			t.Fatal(err)
+		}
+		if err = logs.write(testLogs(task.name, name, 0, task.lines)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMigrateToEnt(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []testMigrateTask
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "single step",
+			tasks: []testMigrateTask{
+				{name: "t1", steps: []string{"a"}, lines: 3},
+			},
+		},
+		{
+			name: "steps with dependencies",
+			tasks: []testMigrateTask{
+				{
+					name:  "t1",
+					steps: []string{"a", "b", "c"},
+					deps:  map[string][]string{"b": {"a"}, "c": {"a", "b"}},
+					lines: logBatchSize + 10,
+				},
+				{name: "t2", steps: []string{"x"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			for _, task := range tt.tasks {
+				createTestTask(t, task)
+			}
+			// 历史执行的日志保留在 gorm 的日志表中, 不复制
+			if err := db.Create(&models.SStepLog{
+				TaskName: "t1",
+				StepName: "a",
+				Run:      1,
+				Line:     models.Pointer(int64(0)),
+				Content:  "archived",
+			}).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			migrated, err := MigrateToEnt(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if migrated != len(tt.tasks) {
+				t.Errorf("migrated %d tasks, want %d", migrated, len(tt.tasks))
+			}
+			// 已存在的任务跳过
+			if migrated, err = MigrateToEnt(ctx); err != nil || migrated != 0 {
+				t.Errorf("migrate again = %d, %v, want 0", migrated, err)
+			}
+
+			if err = SetEngine(EngineEnt); err != nil {
+				t.Fatal(err)
+			}
+			for _, task := range tt.tasks {
+				ts := Task(task.name)
+				if env, _ := ts.Env().Get("TASK"); env != task.name {
+					t.Errorf("task %s env = %q", task.name, env)
+				}
+				var names []string
+				for _, step := range ts.StepList(All) {
+					names = append(names, step.Name)
+				}
+				if !slices.Equal(names, task.steps) {
+					t.Errorf("task %s steps = %v, want %v", task.name, names, task.steps)
+				}
+				for _, name := range task.steps {
+					ss := ts.Step(name)
+					if env, _ := ss.Env().Get("STEP"); env != name {
+						t.Errorf("step %s env = %q", name, env)
+					}
+					deps := ss.Depend().List()
+					slices.Sort(deps)
+					if want := task.deps[name]; !slices.Equal(deps, want) && len(deps)+len(want) != 0 {
+						t.Errorf("step %s depends = %v, want %v", name, deps, want)
+					}
+					key := fmt.Sprintf("%s/%s", task.name, name)
+					logs := logBackend.list(task.name, name, nil)
+					if int64(len(logs)) != task.lines {
+						t.Errorf("step %s got %d log lines, want %d", key, len(logs), task.lines)
+						continue
+					}
+					checkLines(t, logs, 0, task.lines)
+				}
+			}
+		})
+	}
+}
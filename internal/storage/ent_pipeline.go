@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+// sEntPipeline 流水线仍由 gorm 管理, 构建对应的任务存储在 ent 的表中
+type sEntPipeline struct {
+	*sPipeline
+	storage *sEntStorage
+}
+
+func (p *sEntPipeline) ClearAll() error {
+	// clear pipeline
+	if err := p.Remove(); err != nil {
+		return err
+	}
+	// clear task
+	list, _ := p.Build().List(-1, -1)
+	for _, build := range list {
+		_ = p.Task(build.TaskName).ClearAll()
+	}
+	// clear build
+	return p.Build().ClearAll()
+}
+
+func (p *sEntPipeline) Build() IPipelineBuild {
+	return &sEntPipelineBuild{
+		sPipelineBuild: &sPipelineBuild{
+			pName: p.name,
+			DB:    p.DB,
+		},
+		storage: p.storage,
+	}
+}
+
+func (p *sEntPipeline) Task(name string) ITask {
+	return p.storage.Task(name)
+}
+
+// sEntPipelineBuild 构建的任务状态从 ent 的表中获取
+type sEntPipelineBuild struct {
+	*sPipelineBuild
+	storage *sEntStorage
+}
+
+// fill 填充构建对应任务的状态, 与联表查询一致, 丢弃任务已不存在的构建
+func (p *sEntPipelineBuild) fill(builds []*models.SPipelineBuild) (res models.SPipelineBuilds) {
+	var names []string
+	for _, build := range builds {
+		names = append(names, build.TaskName)
+	}
+	tasks, _ := p.storage.client.Task.Query().
+		Where(task.NameIn(names...)).
+		All(context.Background())
+	var states = make(map[string]models.STaskUpdate, len(tasks))
+	for _, t := range tasks {
+		states[t.Name] = entTaskModel(t).STaskUpdate
+	}
+	for _, build := range builds {
+		state, ok := states[build.TaskName]
+		if !ok {
+			continue
+		}
+		res = append(res, &models.SPipelineBuildRes{
+			SPipelineBuild: *build,
+			STaskUpdate:    state,
+		})
+	}
+	return
+}
+
+func (p *sEntPipelineBuild) List(page, size int64) (res models.SPipelineBuilds, total int64) {
+	err := p.Model(&models.SPipelineBuild{}).Where("pipeline_name = ?", p.pName).Count(&total).Error
+	if err != nil {
+		return
+	}
+	var builds []*models.SPipelineBuild
+	query := p.Model(&models.SPipelineBuild{}).
+		Select("id, pipeline_name, task_name").
+		Where(map[string]interface{}{
+			"pipeline_name": p.pName,
+		}).
+		Order("id DESC")
+	if page > 0 && size > 0 {
+		query.Scopes(func(db *gorm.DB) *gorm.DB {
+			return models.Paginate(db, page, size)
+		})
+	}
+	query.Find(&builds)
+	return p.fill(builds), total
+}
+
+func (p *sEntPipelineBuild) Get(name string) (res *models.SPipelineBuildRes, err error) {
+	var builds []*models.SPipelineBuild
+	err = p.Model(&models.SPipelineBuild{}).
+		Select("id, pipeline_name, task_name, params").
+		Where(map[string]interface{}{
+			"pipeline_name": p.pName,
+			"task_name":     name,
+		}).Find(&builds).Error
+	if err != nil {
+		return
+	}
+	if list := p.fill(builds); len(list) != 0 {
+		return list[0], nil
+	}
+	return new(models.SPipelineBuildRes), nil
+}
+
+func (p *sEntPipelineBuild) Expired(keep int) (tasks []string) {
+	var names []string
+	p.Model(&models.SPipelineBuild{}).
+		Where("pipeline_name = ?", p.pName).
+		Order("id DESC").
+		Pluck("task_name", &names)
+	if len(names) <= keep {
+		return nil
+	}
+	// 每次最多处理最早的 500 个
+	names = names[max(keep, len(names)-500):]
+	tasks, _ = p.storage.client.Task.Query().
+		Where(
+			task.NameIn(names...),
+			task.StateIn(taskStates(endedStates...)...),
+		).
+		Select(task.FieldName).
+		Strings(context.Background())
+	return
+}
@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/param"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/stepdepend"
+	"github.com/busyster996/dagflow/internal/entv1/stepparam"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskstep"
+	"github.com/busyster996/dagflow/internal/entv1/taskstepoutput"
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sEntStep struct {
+	*gorm.DB
+	client *entv1.Client
+	genv   IEnv
+	tName  string
+	sName  string
+
+	env      IEnv
+	output   IEnv
+	depend   IDepend
+	log      ILog
+	approval IApproval
+	attempt  IAttempt
+}
+
+func (s *sEntStep) Name() string {
+	return s.sName
+}
+
+// exec 步骤在任务中的执行记录及其定义
+func (s *sEntStep) exec() (*entv1.TaskStep, error) {
+	return s.client.TaskStep.Query().
+		Where(stepExec(s.tName, s.sName)...).
+		WithStep().
+		Only(context.Background())
+}
+
+// scan 与 gorm 的 Scan 一致, 执行记录不存在时返回空记录而不是错误
+func (s *sEntStep) scan() (*entv1.TaskStep, error) {
+	exec, err := s.exec()
+	if entv1.IsNotFound(err) {
+		return new(entv1.TaskStep), nil
+	}
+	return exec, err
+}
+
+// define 步骤定义, 不存在时返回空定义
+func (s *sEntStep) define() (*entv1.Step, error) {
+	exec, err := s.scan()
+	if err != nil || exec.Edges.Step == nil {
+		return new(entv1.Step), err
+	}
+	return exec.Edges.Step, nil
+}
+
+func (s *sEntStep) ClearAll() error {
+	// 日志先于执行记录删除, 丢弃缓冲中尚未写入的日志
+	if err := s.Log().RemoveAll(); err != nil {
+		return err
+	}
+	if err := s.Output().RemoveAll(); err != nil {
+		return err
+	}
+	if err := s.Export().RemoveAll(); err != nil {
+		return err
+	}
+	if err := s.Approval().Remove(); err != nil {
+		return err
+	}
+	if err := s.Attempt().RemoveAll(); err != nil {
+		return err
+	}
+	return s.Remove()
+}
+
+// Remove 删除执行记录、步骤定义以及依赖于定义的参数、依赖关系和输出
+func (s *sEntStep) Remove() (err error) {
+	exec, err := s.exec()
+	if err != nil {
+		if entv1.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	ctx := context.Background()
+	return withTx(ctx, s.client, func(tx *entv1.Tx) error {
+		if _, err := tx.TaskStepOutput.Delete().
+			Where(taskstepoutput.ExecID(exec.ID)).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if err := entRemoveParams(ctx, tx, param.HasStepParamsWith(stepparam.StepID(exec.StepID))); err != nil {
+			return err
+		}
+		if _, err := tx.StepDepend.Delete().
+			Where(stepdepend.Or(
+				stepdepend.StepID(exec.StepID),
+				stepdepend.DependentID(exec.StepID),
+			)).
+			Exec(ctx); err != nil {
+			return err
+		}
+		if err := tx.TaskStep.DeleteOneID(exec.ID).Exec(ctx); err != nil {
+			return err
+		}
+		return tx.Step.DeleteOneID(exec.StepID).Exec(ctx)
+	})
+}
+
+func (s *sEntStep) State() (state models.State, err error) {
+	exec, err := s.scan()
+	if err != nil {
+		return
+	}
+	return *fromEnum(exec.State), nil
+}
+
+func (s *sEntStep) IsDisable() (disable bool) {
+	exec, err := s.scan()
+	if err != nil {
+		return
+	}
+	return entv1.UnPointer(exec.Disabled)
+}
+
+func (s *sEntStep) AllowFailure() (allow bool) {
+	def, _ := s.define()
+	return entv1.UnPointer(def.AllowFailure)
+}
+
+func (s *sEntStep) Env() IEnv {
+	if s.env == nil {
+		s.env = &sEntStepEnv{
+			client: s.client,
+			tName:  s.tName,
+			sName:  s.sName,
+		}
+	}
+	return s.env
+}
+
+func (s *sEntStep) Output() IEnv {
+	if s.output == nil {
+		s.output = &sStepOutput{
+			DB:    s.DB,
+			tName: s.tName,
+			sName: s.sName,
+		}
+	}
+	return s.output
+}
+
+func (s *sEntStep) TaskName() string {
+	return s.tName
+}
+
+func (s *sEntStep) Timeout() (res time.Duration, err error) {
+	def, err := s.define()
+	return time.Duration(entv1.UnPointer(def.Timeout)), err
+}
+
+func (s *sEntStep) Priority() (res int, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Priority), err
+}
+
+func (s *sEntStep) Weight() (res int64, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Weight), err
+}
+
+func (s *sEntStep) Mutex() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Mutex), err
+}
+
+func (s *sEntStep) Type() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Kind), err
+}
+
+func (s *sEntStep) Content() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Content), err
+}
+
+func (s *sEntStep) Action() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Action), err
+}
+
+func (s *sEntStep) Rule() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.Rule), err
+}
+
+func (s *sEntStep) When() (res string, err error) {
+	def, err := s.define()
+	return entv1.UnPointer(def.RunWhen), err
+}
+
+func (s *sEntStep) RetryPolicy() (res models.SRetryPolicy, err error) {
+	def, err := s.define()
+	if err != nil || def.RetryPolicy == nil {
+		return
+	}
+	return convert[models.SRetryPolicy](def.RetryPolicy), nil
+}
+
+func (s *sEntStep) Get() (res *models.SStep, err error) {
+	exec, err := s.exec()
+	if err != nil {
+		return nil, err
+	}
+	return entStepModel(s.tName, exec), nil
+}
+
+// Update 与 gorm 一致, 仅更新非零值字段
+func (s *sEntStep) Update(value *models.SStepUpdate) (err error) {
+	if value == nil {
+		return
+	}
+	update := s.client.TaskStep.Update().
+		Where(stepExec(s.tName, s.sName)...).
+		SetNillableStartTime(value.STime).
+		SetNillableEndTime(value.ETime)
+	if value.Message != "" {
+		update.SetMessage(value.Message)
+	}
+	if value.State != nil {
+		update.SetState(toEnum[taskstep.State](*value.State))
+	}
+	if value.OldState != nil {
+		update.SetPreviousState(toEnum[taskstep.PreviousState](*value.OldState))
+	}
+	if value.Code != nil {
+		update.SetCode(int64(*value.Code))
+	}
+	_, err = update.Save(context.Background())
+	return
+}
+
+func (s *sEntStep) GlobalEnv() IEnv {
+	if s.genv == nil {
+		s.genv = &sEntTaskEnv{
+			client: s.client,
+			tName:  s.tName,
+		}
+	}
+	return s.genv
+}
+
+func (s *sEntStep) Depend() IDepend {
+	if s.depend == nil {
+		s.depend = &sEntStepDepend{
+			client: s.client,
+			tName:  s.tName,
+			sName:  s.sName,
+		}
+	}
+	return s.depend
+}
+
+func (s *sEntStep) Log() ILog {
+	if s.log == nil {
+		s.log = &sStepLog{
+			backend: logBackend,
+			tName:   s.tName,
+			sName:   s.sName,
+		}
+	}
+	return s.log
+}
+
+func (s *sEntStep) Approval() IApproval {
+	if s.approval == nil {
+		s.approval = &sStepApproval{
+			DB:    s.DB,
+			tName: s.tName,
+			sName: s.sName,
+		}
+	}
+	return s.approval
+}
+
+// Export 导出的环境变量与审批记录相同保存在 gorm 的表中
+func (s *sEntStep) Export() IEnv {
+	return &sStepExport{
+		DB:    s.DB,
+		tName: s.tName,
+		sName: s.sName,
+	}
+}
+
+func (s *sEntStep) UpstreamEnv() models.SEnvs {
+	return upstreamEnv(s.DB, s.tName, s.sName)
+}
+
+func (s *sEntStep) Attempt() IAttempt {
+	if s.attempt == nil {
+		s.attempt = &sStepAttempt{
+			DB:    s.DB,
+			tName: s.tName,
+			sName: s.sName,
+		}
+	}
+	return s.attempt
+}
+
+// stepDefine 任务中指定名称的步骤定义
+func stepDefine(tName, sName string) predicate.Step {
+	return step.And(
+		step.Name(sName),
+		step.HasTaskStepsWith(taskstep.HasTaskWith(task.Name(tName))),
+	)
+}
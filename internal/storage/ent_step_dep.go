@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/stepdepend"
+	"github.com/busyster996/dagflow/internal/utility/sid"
+)
+
+type sEntStepDepend struct {
+	client *entv1.Client
+	tName  string
+	sName  string
+}
+
+// List 获取当前步骤所依赖的步骤
+func (d *sEntStepDepend) List() (res []string) {
+	depends, _ := d.client.StepDepend.Query().
+		Where(stepdepend.HasStepWith(stepDefine(d.tName, d.sName))).
+		WithDependStep(func(q *entv1.StepQuery) {
+			q.Select(step.FieldName)
+		}).
+		Order(stepdepend.ByID()).
+		All(context.Background())
+	for _, v := range depends {
+		if v.Edges.DependStep != nil {
+			res = append(res, v.Edges.DependStep.Name)
+		}
+	}
+	return
+}
+
+// Insert 依赖关系关联步骤定义, 被依赖的步骤尚未创建时先创建占位记录
+func (d *sEntStepDepend) Insert(depends ...string) (err error) {
+	if len(depends) == 0 {
+		return
+	}
+	ctx := context.Background()
+	return withTx(ctx, d.client, func(tx *entv1.Tx) error {
+		stepID, err := tx.Step.Query().Where(stepDefine(d.tName, d.sName)).OnlyID(ctx)
+		if err != nil {
+			return err
+		}
+		for _, depend := range depends {
+			dependID, err := tx.Step.Query().Where(stepDefine(d.tName, depend)).OnlyID(ctx)
+			if entv1.IsNotFound(err) {
+				dependID, err = entPlaceholder(ctx, tx, d.tName, depend)
+			}
+			if err != nil {
+				return err
+			}
+			id, err := sid.NextID(stepdepend.Table)
+			if err != nil {
+				return err
+			}
+			if err = tx.StepDepend.Create().
+				SetID(id).
+				SetStepID(stepID).
+				SetDependentID(dependID).
+				Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *sEntStepDepend) RemoveAll() (err error) {
+	_, err = d.client.StepDepend.Delete().
+		Where(stepdepend.HasStepWith(stepDefine(d.tName, d.sName))).
+		Exec(context.Background())
+	return
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskstep"
+	"github.com/busyster996/dagflow/internal/entv1/taskstepoutput"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility/sid"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// sEntLog 日志逐行存储在 task_step_outputs 表中, 关联步骤的执行记录,
+// 步骤执行记录随任务重置删除, 历史执行的日志归档到 gorm 的日志表中
+type sEntLog struct {
+	*entv1.Client
+	runs *sDBLog
+}
+
+func (e *sEntLog) execID(tName, sName string) (uint64, error) {
+	return e.TaskStep.Query().
+		Where(stepExec(tName, sName)...).
+		OnlyID(context.Background())
+}
+
+func (e *sEntLog) next(tName, sName string) (int64, error) {
+	execID, err := e.execID(tName, sName)
+	if err != nil {
+		return 0, err
+	}
+	last, err := e.TaskStepOutput.Query().
+		Where(taskstepoutput.ExecID(execID)).
+		Order(taskstepoutput.ByLine(entsql.OrderDesc())).
+		First(context.Background())
+	if err != nil {
+		if entv1.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return last.Line + 1, nil
+}
+
+func (e *sEntLog) write(logs models.SStepLogs) error {
+	ctx := context.Background()
+	var execs = make(map[string]uint64)
+	var builders []*entv1.TaskStepOutputCreate
+	for _, log := range logs {
+		key := logKey(log.TaskName, log.StepName)
+		execID, ok := execs[key]
+		if !ok {
+			var err error
+			if execID, err = e.execID(log.TaskName, log.StepName); err != nil {
+				// 步骤已删除
+				logx.Warnln("write step logs", key, err)
+			}
+			execs[key] = execID
+		}
+		if execID == 0 {
+			continue
+		}
+		id, err := sid.NextID(taskstepoutput.Table)
+		if err != nil {
+			return err
+		}
+		builders = append(builders, e.TaskStepOutput.Create().
+			SetID(id).
+			SetExecID(execID).
+			SetLine(entv1.UnPointer(log.Line)).
+			SetTimestamp(log.Timestamp).
+			SetContent(log.Content))
+	}
+	return withTx(ctx, e.Client, func(tx *entv1.Tx) error {
+		for start := 0; start < len(builders); start += logBatchSize {
+			end := min(start+logBatchSize, len(builders))
+			if err := tx.TaskStepOutput.CreateBulk(builders[start:end]...).Exec(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (e *sEntLog) outputs(tName, sName string) *entv1.TaskStepOutputQuery {
+	return e.TaskStepOutput.Query().
+		Where(taskstepoutput.HasTaskStepWith(stepExec(tName, sName)...)).
+		Order(taskstepoutput.ByLine())
+}
+
+func (e *sEntLog) model(tName, sName string, outputs []*entv1.TaskStepOutput) (res models.SStepLogs) {
+	for _, v := range outputs {
+		res = append(res, &models.SStepLog{
+			SBase: models.SBase{
+				ID: v.ID,
+			},
+			TaskName:  tName,
+			StepName:  sName,
+			Timestamp: entv1.UnPointer(v.Timestamp),
+			Line:      models.Pointer(v.Line),
+			Content:   entv1.UnPointer(v.Content),
+		})
+	}
+	return
+}
+
+func (e *sEntLog) list(tName, sName string, latestLine *int64) (res models.SStepLogs) {
+	query := e.outputs(tName, sName)
+	if latestLine != nil {
+		// 如果 latestLine 不为空，只查询行号大于 latestLine 的日志
+		query.Where(taskstepoutput.LineGT(*latestLine)).Limit(logListLimit)
+	}
+	outputs, _ := query.All(context.Background())
+	return e.model(tName, sName, outputs)
+}
+
+func (e *sEntLog) lines(tName, sName string, start, end int64) (res models.SStepLogs) {
+	outputs, _ := e.outputs(tName, sName).
+		Where(taskstepoutput.LineGTE(start), taskstepoutput.LineLTE(end)).
+		All(context.Background())
+	return e.model(tName, sName, outputs)
+}
+
+func (e *sEntLog) remove(tName, sName string) error {
+	_, err := e.TaskStepOutput.Delete().
+		Where(taskstepoutput.HasTaskStepWith(stepExec(tName, sName)...)).
+		Exec(context.Background())
+	return err
+}
+
+func (e *sEntLog) trim(tName, sName string, keep int64) (lines, size int64, err error) {
+	next, err := e.next(tName, sName)
+	if err != nil || next <= keep {
+		return 0, 0, err
+	}
+	ctx := context.Background()
+	execID, err := e.execID(tName, sName)
+	if err != nil {
+		return 0, 0, err
+	}
+	expired := []predicate.TaskStepOutput{
+		taskstepoutput.ExecID(execID),
+		taskstepoutput.LineLT(next - keep),
+	}
+	length, err := e.TaskStepOutput.Query().
+		Where(expired...).
+		Modify(func(s *entsql.Selector) {
+			s.Select("COALESCE(SUM(LENGTH(" + s.C(taskstepoutput.FieldContent) + ")), 0)")
+		}).
+		Int(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	deleted, err := e.TaskStepOutput.Delete().Where(expired...).Exec(ctx)
+	return int64(deleted), int64(length), err
+}
+
+// oversized ent 的表中直接按任务状态过滤, 不使用 tasks
+func (e *sEntLog) oversized(_ *gorm.DB, lines int64) (res models.SStepLogs) {
+	ctx := context.Background()
+	var rows []struct {
+		ExecID uint64 `json:"exec_id"`
+		Count  int64  `json:"count"`
+	}
+	if err := e.TaskStepOutput.Query().
+		Where(taskstepoutput.HasTaskStepWith(
+			taskstep.HasTaskWith(task.StateIn(taskStates(endedStates...)...)),
+		)).
+		GroupBy(taskstepoutput.FieldExecID).
+		Aggregate(entv1.Count()).
+		Scan(ctx, &rows); err != nil {
+		return
+	}
+	var counts = make(map[uint64]int64)
+	var ids []uint64
+	for _, row := range rows {
+		if row.Count > lines {
+			counts[row.ExecID] = row.Count
+			ids = append(ids, row.ExecID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	execs, _ := e.TaskStep.Query().
+		Where(taskstep.IDIn(ids...)).
+		WithTask(func(q *entv1.TaskQuery) {
+			q.Select(task.FieldName)
+		}).
+		WithStep(func(q *entv1.StepQuery) {
+			q.Select(step.FieldName)
+		}).
+		All(ctx)
+	for _, v := range execs {
+		if v.Edges.Task == nil || v.Edges.Step == nil {
+			continue
+		}
+		res = append(res, &models.SStepLog{
+			TaskName: v.Edges.Task.Name,
+			StepName: v.Edges.Step.Name,
+			Line:     models.Pointer(counts[v.ID]),
+		})
+	}
+	return
+}
+
+// archive 分批将当前执行的日志移动到 gorm 的日志表中
+func (e *sEntLog) archive(tName, sName string, run int64) error {
+	ctx := context.Background()
+	execID, err := e.execID(tName, sName)
+	if err != nil {
+		if entv1.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	var last int64 = -1
+	for {
+		outputs, err := e.TaskStepOutput.Query().
+			Where(taskstepoutput.ExecID(execID), taskstepoutput.LineGT(last)).
+			Order(taskstepoutput.ByLine()).
+			Limit(logBatchSize).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		if len(outputs) == 0 {
+			break
+		}
+		logs := e.model(tName, sName, outputs)
+		for _, log := range logs {
+			log.ID = 0
+			log.Run = run
+		}
+		if err = e.runs.Create(logs).Error; err != nil {
+			return err
+		}
+		last = outputs[len(outputs)-1].Line
+	}
+	_, err = e.TaskStepOutput.Delete().
+		Where(taskstepoutput.ExecID(execID)).
+		Exec(ctx)
+	return err
+}
+
+func (e *sEntLog) history(tName, sName string, run, start, end int64) models.SStepLogs {
+	return e.runs.history(tName, sName, run, start, end)
+}
+
+func (e *sEntLog) removeRuns(tName string, upto int64) (int64, error) {
+	return e.runs.removeRuns(tName, upto)
+}
@@ -0,0 +1,415 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/entv1"
+	"github.com/busyster996/dagflow/internal/entv1/predicate"
+	"github.com/busyster996/dagflow/internal/entv1/schema"
+	"github.com/busyster996/dagflow/internal/entv1/step"
+	"github.com/busyster996/dagflow/internal/entv1/task"
+	"github.com/busyster996/dagflow/internal/entv1/taskstep"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility/sid"
+)
+
+type sEntTask struct {
+	*gorm.DB
+	client *entv1.Client
+	tName  string
+
+	env IEnv
+}
+
+func (t *sEntTask) Name() string {
+	return t.tName
+}
+
+func (t *sEntTask) get() (*entv1.Task, error) {
+	return t.client.Task.Query().
+		Where(task.Name(t.tName)).
+		Only(context.Background())
+}
+
+// scan 与 gorm 的 Scan 一致, 任务不存在时返回空记录而不是错误
+func (t *sEntTask) scan() (*entv1.Task, error) {
+	v, err := t.get()
+	if entv1.IsNotFound(err) {
+		return new(entv1.Task), nil
+	}
+	return v, err
+}
+
+func (t *sEntTask) ClearAll() error {
+	if err := t.Reset(); err != nil {
+		return err
+	}
+	if err := t.Run().RemoveAll(); err != nil {
+		return err
+	}
+	// 清理build表
+	t.Where("task_name", t.tName).Delete(&models.SPipelineBuild{})
+	return nil
+}
+
+func (t *sEntTask) Reset() error {
+	// 先删除步骤(包括占位记录), 步骤定义及依赖不随任务级联删除
+	names, err := t.client.Step.Query().
+		Where(step.HasTaskStepsWith(taskstep.HasTaskWith(task.Name(t.tName)))).
+		Select(step.FieldName).
+		Strings(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err = t.Step(name).ClearAll(); err != nil {
+			return err
+		}
+	}
+	if err := t.Env().RemoveAll(); err != nil {
+		return err
+	}
+	if err := t.Depend().RemoveAll(); err != nil {
+		return err
+	}
+	return t.Remove()
+}
+
+func (t *sEntTask) Remove() (err error) {
+	_, err = t.client.Task.Delete().
+		Where(task.Name(t.tName)).
+		Exec(context.Background())
+	return
+}
+
+func (t *sEntTask) Kind() (res string, err error) {
+	v, err := t.scan()
+	if err != nil {
+		return
+	}
+	return entv1.UnPointer(v.Kind), nil
+}
+
+func (t *sEntTask) State() (state models.State, err error) {
+	v, err := t.scan()
+	if err != nil {
+		return
+	}
+	return *fromEnum(v.State), nil
+}
+
+func (t *sEntTask) FailFast() (enable bool) {
+	v, err := t.scan()
+	if err != nil {
+		return
+	}
+	return entv1.UnPointer(v.FailFast)
+}
+
+func (t *sEntTask) IsDisable() (disable bool) {
+	v, err := t.scan()
+	if err != nil {
+		return
+	}
+	return entv1.UnPointer(v.Disabled)
+}
+
+func (t *sEntTask) Env() IEnv {
+	if t.env == nil {
+		t.env = &sEntTaskEnv{
+			client: t.client,
+			tName:  t.tName,
+		}
+	}
+	return t.env
+}
+
+func (t *sEntTask) Depend() ITaskDepend {
+	return &sTaskDepend{
+		DB:    t.DB,
+		tName: t.tName,
+	}
+}
+
+func (t *sEntTask) Run() IRun {
+	return &sTaskRun{
+		DB:    t.DB,
+		tName: t.tName,
+	}
+}
+
+func (t *sEntTask) Timeout() (res time.Duration, err error) {
+	v, err := t.scan()
+	if err != nil {
+		return
+	}
+	return time.Duration(entv1.UnPointer(v.Timeout)), nil
+}
+
+func (t *sEntTask) Get() (res *models.STask, err error) {
+	v, err := t.get()
+	if err != nil {
+		return nil, err
+	}
+	return entTaskModel(v), nil
+}
+
+func (t *sEntTask) UpdateNode(node string) error {
+	if node == "" {
+		return errors.New("node name can not be empty")
+	}
+	_, err := t.client.Task.Update().
+		Where(task.Name(t.tName)).
+		SetNode(node).
+		Save(context.Background())
+	return err
+}
+
+// Update 与 gorm 一致, 仅更新非零值字段
+func (t *sEntTask) ResetTime() (err error) {
+	_, err = t.client.Task.Update().
+		Where(task.Name(t.tName)).
+		ClearStartTime().
+		ClearEndTime().
+		Save(context.Background())
+	return
+}
+
+func (t *sEntTask) Update(value *models.STaskUpdate) (err error) {
+	if value == nil {
+		return
+	}
+	update := t.client.Task.Update().
+		Where(task.Name(t.tName)).
+		SetNillableStartTime(value.STime).
+		SetNillableEndTime(value.ETime)
+	if value.Message != "" {
+		update.SetMessage(value.Message)
+	}
+	if value.State != nil {
+		update.SetState(toEnum[task.State](*value.State))
+	}
+	if value.OldState != nil {
+		update.SetPreviousState(toEnum[task.PreviousState](*value.OldState))
+	}
+	_, err = update.Save(context.Background())
+	return
+}
+
+func (t *sEntTask) Step(name string) IStep {
+	return &sEntStep{
+		DB:     t.DB,
+		client: t.client,
+		genv:   t.Env(),
+		tName:  t.tName,
+		sName:  name,
+	}
+}
+
+// StepCreate 创建步骤定义及其在任务中的执行记录
+// 被依赖的步骤可能先以占位记录的形式创建(见 sEntStepDepend.Insert), 此时补全占位记录
+func (t *sEntTask) StepCreate(s *models.SStep) (err error) {
+	s.TaskName = t.tName
+	ctx := context.Background()
+	err = withTx(ctx, t.client, func(tx *entv1.Tx) error {
+		tk, err := tx.Task.Query().Where(task.Name(t.tName)).Only(ctx)
+		if err != nil {
+			return err
+		}
+		exec, err := tx.TaskStep.Query().
+			Where(stepExec(t.tName, s.Name)...).
+			WithStep().
+			Only(ctx)
+		switch {
+		case entv1.IsNotFound(err):
+			defID, err := sid.NextID(step.Table)
+			if err != nil {
+				return err
+			}
+			if err = entStepDefine(tx.Step.Create().SetID(defID), s).Exec(ctx); err != nil {
+				return err
+			}
+			if s.ID == 0 {
+				if s.ID, err = sid.NextID(taskstep.Table); err != nil {
+					return err
+				}
+			}
+			return entStepExec(tx.TaskStep.Create().
+				SetID(s.ID).
+				SetTaskID(tk.ID).
+				SetStepID(defID), s).
+				Exec(ctx)
+		case err != nil:
+			return err
+		case exec.Edges.Step.Kind != nil:
+			return fmt.Errorf("step %s already exists", s.Name)
+		}
+		if err = entStepDefine(tx.Step.UpdateOneID(exec.StepID), s).Exec(ctx); err != nil {
+			return err
+		}
+		s.ID = exec.ID
+		return entStepExec(tx.TaskStep.UpdateOneID(exec.ID), s).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save step %d error: %s", s.ID, err)
+	}
+	return
+}
+
+// entPlaceholder 尚未创建的被依赖步骤, 以没有类型的步骤定义占位
+func entPlaceholder(ctx context.Context, tx *entv1.Tx, tName, sName string) (uint64, error) {
+	tk, err := tx.Task.Query().Where(task.Name(tName)).Only(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defID, err := sid.NextID(step.Table)
+	if err != nil {
+		return 0, err
+	}
+	if err = tx.Step.Create().SetID(defID).SetName(sName).Exec(ctx); err != nil {
+		return 0, err
+	}
+	execID, err := sid.NextID(taskstep.Table)
+	if err != nil {
+		return 0, err
+	}
+	return defID, tx.TaskStep.Create().
+		SetID(execID).
+		SetTaskID(tk.ID).
+		SetStepID(defID).
+		SetState(toEnum[taskstep.State](models.StateUnknown)).
+		Exec(ctx)
+}
+
+// iEntStepDefine 创建及更新步骤定义共用的设置方法
+type iEntStepDefine[T any] interface {
+	SetName(string) T
+	SetDesc(string) T
+	SetKind(string) T
+	SetContent(string) T
+	SetTimeout(int64) T
+	SetAction(string) T
+	SetRule(string) T
+	SetRunWhen(string) T
+	SetHandler(string) T
+	SetPriority(int) T
+	SetWeight(int64) T
+	SetMutex(string) T
+	SetAllowFailure(bool) T
+	SetIdempotent(bool) T
+	SetRetryPolicy(*schema.RetryPolicy) T
+	SetMatrix(*schema.Matrix) T
+	SetForeach(*schema.Foreach) T
+	SetMetadata(map[string]interface{}) T
+}
+
+func entStepDefine[T iEntStepDefine[T]](b T, s *models.SStep) T {
+	// 与 gorm 的列默认值一致
+	timeout, weight := s.Timeout, s.Weight
+	if timeout == 0 {
+		timeout = 24 * time.Hour
+	}
+	if weight == 0 {
+		weight = 1
+	}
+	b.SetName(s.Name).
+		SetDesc(s.Desc).
+		SetKind(s.Type).
+		SetContent(s.Content).
+		SetTimeout(int64(timeout)).
+		SetAction(s.Action).
+		SetRule(s.Rule).
+		SetRunWhen(s.When).
+		SetHandler(s.Handler).
+		SetPriority(s.Priority).
+		SetWeight(weight).
+		SetMutex(s.Mutex).
+		SetAllowFailure(entv1.UnPointer(s.AllowFailure)).
+		SetIdempotent(entv1.UnPointer(s.Idempotent)).
+		SetRetryPolicy(convert[*schema.RetryPolicy](s.RetryPolicy.Data())).
+		SetMatrix(convert[*schema.Matrix](s.Matrix.Data())).
+		SetForeach(convert[*schema.Foreach](s.Foreach.Data())).
+		SetMetadata(s.Metadata)
+	return b
+}
+
+// iEntStepExec 创建及更新步骤执行记录共用的设置方法
+type iEntStepExec[T any] interface {
+	SetSeqNo(int64) T
+	SetDisabled(bool) T
+	SetMessage(string) T
+	SetState(taskstep.State) T
+	SetPreviousState(taskstep.PreviousState) T
+	SetCode(int64) T
+	SetNillableStartTime(*time.Time) T
+	SetNillableEndTime(*time.Time) T
+}
+
+func entStepExec[T iEntStepExec[T]](b T, s *models.SStep) T {
+	b.SetSeqNo(s.SeqNo).
+		SetDisabled(entv1.UnPointer(s.Disable)).
+		SetMessage(s.Message).
+		SetNillableStartTime(s.STime).
+		SetNillableEndTime(s.ETime)
+	if s.State != nil {
+		b.SetState(toEnum[taskstep.State](*s.State))
+	}
+	if s.OldState != nil {
+		b.SetPreviousState(toEnum[taskstep.PreviousState](*s.OldState))
+	}
+	if s.Code != nil {
+		b.SetCode(int64(*s.Code))
+	}
+	return b
+}
+
+// steps 任务中已创建完成的步骤, 按序号排列
+func (t *sEntTask) steps(str string) *entv1.TaskStepQuery {
+	defs := []predicate.Step{step.KindNotNil()}
+	if str != "" {
+		defs = append(defs, func(s *entsql.Selector) {
+			s.Where(entsql.Like(s.C(step.FieldName), str))
+		})
+	}
+	return t.client.TaskStep.Query().
+		Where(
+			taskstep.HasTaskWith(task.Name(t.tName)),
+			taskstep.HasStepWith(defs...),
+		).
+		Order(taskstep.BySeqNo())
+}
+
+func (t *sEntTask) StepCount() (res int64) {
+	count, _ := t.steps(All).Count(context.Background())
+	return int64(count)
+}
+
+func (t *sEntTask) StepNameList(str string) (res []string) {
+	for _, v := range t.StepList(str) {
+		res = append(res, v.Name)
+	}
+	return
+}
+
+func (t *sEntTask) StepStateList(str string) (res map[string]models.State) {
+	list := t.StepList(str)
+	res = make(map[string]models.State, len(list))
+	for _, v := range list {
+		res[v.Name] = *v.State
+	}
+	return
+}
+
+func (t *sEntTask) StepList(str string) (res models.SSteps) {
+	execs, _ := t.steps(str).WithStep().All(context.Background())
+	for _, v := range execs {
+		res = append(res, entStepModel(t.tName, v))
+	}
+	return
+}
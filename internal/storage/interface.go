@@ -11,11 +11,15 @@ const All = ""
 type IStorage interface {
 	Name() (name string)
 
-	// FixDatabase fix database
-	FixDatabase(node string) (err error)
+	// FixDatabase fix database, 返回节点上需重新接管执行的任务
+	FixDatabase(node string) (adopted []string, err error)
 
 	// NodeTasks 节点任务接口
 	NodeTasks(node string) (tasks []ITask)
+	// Node 节点接口
+	Node(name string) (node INode)
+	// NodeList 所有节点
+	NodeList() (res models.SNodes)
 	// Task 任务接口
 	Task(name string) (task ITask)
 	// TaskCreate 创建任务
@@ -24,6 +28,20 @@ type IStorage interface {
 	TaskCount(state models.State) (res int64)
 	// TaskList 获取任务,支持分页, 模糊匹配
 	TaskList(page, pageSize int64, str string) (res models.STasks, total int64)
+	// GroupTasks 并发组中未结束的任务, 按创建顺序
+	GroupTasks(group string) (res models.STasks)
+	// GroupDequeue 并发组中没有执行中的任务时, 取出最早排队的任务并置为待执行, 否则返回nil
+	GroupDequeue(group string) (task *models.STask, err error)
+	// QueuedGroups 存在排队任务的并发组
+	QueuedGroups() (groups []string)
+	// DependTasks 等待前置任务结束的任务
+	DependTasks() (tasks []string)
+	// ExpiredTasks 在 before 之前结束且没有后置任务等待的任务, 最多 limit 个
+	ExpiredTasks(before time.Time, limit int) (tasks []string)
+	// OversizedLogs 已结束任务中日志超过 lines 行的步骤, Line 为当前保留的行数
+	OversizedLogs(lines int64) (res models.SStepLogs)
+	// ExpiredRuns 历史执行记录超过 keep 次的任务
+	ExpiredRuns(keep int) (tasks []string)
 
 	// Pipeline 流水线接口
 	Pipeline(name string) (pipeline IPipeline)
@@ -45,14 +63,23 @@ type IBase interface {
 type ITask interface {
 	IBase
 
+	// Reset 清理本次执行的数据, 保留历史执行记录及构建记录
+	Reset() (err error)
+
 	// Kind 获取类型
 	Kind() (res string, err error)
 	// IsDisable 是否禁用
 	IsDisable() (disable bool)
+	// FailFast 任一步骤失败时是否立即终止其他步骤
+	FailFast() (enable bool)
 	// State 获取状态
 	State() (state models.State, err error)
 	// Env 环境变量接口
 	Env() (env IEnv)
+	// Depend 前置任务接口
+	Depend() (depend ITaskDepend)
+	// Run 历史执行记录接口
+	Run() (run IRun)
 
 	// Timeout 超时时间
 	Timeout() (res time.Duration, err error)
@@ -62,6 +89,8 @@ type ITask interface {
 	Update(value *models.STaskUpdate) (err error)
 	// UpdateNode 更新节点
 	UpdateNode(node string) error
+	// ResetTime 清空开始及结束时间, 重新提交时使用
+	ResetTime() (err error)
 
 	// Step 步骤接口
 	Step(name string) IStep
@@ -82,15 +111,29 @@ type IStep interface {
 
 	// IsDisable 是否禁用
 	IsDisable() (disable bool)
+	// AllowFailure 是否允许失败, 失败时不阻塞下游步骤
+	AllowFailure() (allow bool)
 	// State 获取状态
 	State() (state models.State, err error)
 	// Env 环境变量接口
 	Env() (env IEnv)
+	// Output 输出变量接口
+	Output() (output IEnv)
+	// Export 导出给下游步骤的环境变量接口
+	Export() (export IEnv)
+	// UpstreamEnv 上游步骤导出的环境变量
+	UpstreamEnv() (res models.SEnvs)
 
 	// TaskName 任务名称
 	TaskName() (taskName string)
 	// Timeout 超时时间
 	Timeout() (res time.Duration, err error)
+	// Priority 优先级
+	Priority() (res int, err error)
+	// Weight 占用节点预算的槽位
+	Weight() (res int64, err error)
+	// Mutex 节点互斥组
+	Mutex() (res string, err error)
 	// Type 类型
 	Type() (res string, err error)
 	// Content 内容
@@ -99,6 +142,8 @@ type IStep interface {
 	Action() (res string, err error)
 	// Rule 规则
 	Rule() (res string, err error)
+	// When 执行条件
+	When() (res string, err error)
 	// RetryPolicy 重试策略
 	RetryPolicy() (res models.SRetryPolicy, err error)
 	// Get 根据名称获取指定步骤
@@ -111,16 +156,28 @@ type IStep interface {
 	Depend() (depend IDepend)
 	// Log 日志接口
 	Log() (log ILog)
+	// Approval 审批接口
+	Approval() (approval IApproval)
+	// Attempt 执行记录接口
+	Attempt() (attempt IAttempt)
 }
 
 type ILog interface {
 	// List 获取指定任务指定步骤所有日志, 增量查询
 	List(latestLine *int64) (res models.SStepLogs)
+	// Range 获取行号在 [start, end] 之间的日志
+	Range(start, end int64) (res models.SStepLogs)
 	// Insert 插入
 	Insert(log *models.SStepLog) (err error)
 	Write(contents ...string)
 	Writef(format string, args ...interface{})
 	RemoveAll() (err error)
+	// Trim 仅保留最后 keep 行, 返回删除的行数及释放的磁盘空间
+	Trim(keep int64) (lines, size int64, err error)
+	// Archive 当前执行的日志归档为第 run 次执行
+	Archive(run int64) (err error)
+	// History 第 run 次执行行号在 [start, end] 之间的日志, end为负数时不限制
+	History(run, start, end int64) (res models.SStepLogs)
 }
 
 type IEnv interface {
@@ -138,6 +195,57 @@ type IDepend interface {
 	RemoveAll() (err error)
 }
 
+type ITaskDepend interface {
+	List() (res models.STaskDepends)
+	Insert(depends ...*models.STaskDepend) (err error)
+	Update(name string, state models.State, message string) (err error)
+	RemoveAll() (err error)
+}
+
+type IApproval interface {
+	// Get 获取审批记录, 未审批时返回 ErrNotFound
+	Get() (res *models.SStepApproval, err error)
+	Insert(approval *models.SStepApproval) (err error)
+	Remove() (err error)
+}
+
+type IAttempt interface {
+	List() (res models.SStepAttempts)
+	Get(attempt int) (res *models.SStepAttempt, err error)
+	Insert(attempt *models.SStepAttempt) (err error)
+	Update(attempt *models.SStepAttempt) (err error)
+	RemoveAll() (err error)
+}
+
+type IRun interface {
+	// List 历史执行记录, 不含请求及详情, 按序号倒序
+	List() (res models.STaskRuns)
+	// Get 指定序号的执行记录
+	Get(run int64) (res *models.STaskRun, err error)
+	// Latest 最近一次历史执行的序号, 没有时为0
+	Latest() (run int64)
+	// Steps 指定序号的执行中的步骤, 按排序返回
+	Steps(run int64) (res models.STaskRunSteps)
+	// Step 指定序号的执行中的步骤
+	Step(run int64, name string) (res *models.STaskRunStep, err error)
+	// Insert 保存执行记录及步骤, 序号为已有记录的最大序号加一
+	Insert(run *models.STaskRun, steps models.STaskRunSteps) (err error)
+	// Trim 仅保留最近 keep 次, 返回删除的数量及释放的空间
+	Trim(keep int) (runs, size int64, err error)
+	RemoveAll() (err error)
+}
+
+type INode interface {
+	// Name 名称
+	Name() (name string)
+	// Get 获取节点
+	Get() (res *models.SNode, err error)
+	// UpdateBudget 更新步骤并发预算
+	UpdateBudget(budget models.SBudget) (err error)
+	// UpdateJanitor 更新清理统计
+	UpdateJanitor(janitor models.SJanitor) (err error)
+}
+
 type IPipeline interface {
 	IBase
 
@@ -169,4 +277,6 @@ type IPipelineBuild interface {
 	Remove(name string) (err error)
 	// ClearAll 清理
 	ClearAll() (err error)
+	// Expired 最近 keep 次之前且已结束的构建对应的任务
+	Expired(keep int) (tasks []string)
 }
@@ -18,6 +18,8 @@ const (
 	StatePending              // 等待
 	StatePaused               // 挂起
 	StateSkipped              // 跳过
+	StateWaiting              // 等待审批
+	StateQueued               // 并发组排队
 	StateAll     State = -1
 )
 
@@ -29,6 +31,8 @@ var StateMap = map[State]string{
 	StatePending: "pending",
 	StatePaused:  "paused",
 	StateSkipped: "skipped",
+	StateWaiting: "waiting",
+	StateQueued:  "queued",
 }
 
 type SBase struct {
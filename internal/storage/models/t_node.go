@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type SNode struct {
+	SBase
+	Name    string                       `json:"name,omitempty" gorm:"size:256;uniqueIndex;not null;comment:节点名称"`
+	Budget  datatypes.JSONType[SBudget]  `json:"budget,omitempty" gorm:"comment:步骤并发预算"`
+	Janitor datatypes.JSONType[SJanitor] `json:"janitor,omitempty" gorm:"comment:清理统计"`
+}
+
+func (n *SNode) TableName() string {
+	return "t_node"
+}
+
+type SNodes []*SNode
+
+type SBudget struct {
+	Size    int64            `json:"size" description:"总槽位, 0为不限制"`
+	Used    int64            `json:"used" description:"已占用槽位"`
+	Holders []*SBudgetHolder `json:"holders,omitempty" description:"正在执行的步骤"`
+	Waiters []*SBudgetHolder `json:"waiters,omitempty" description:"等待预算的步骤"`
+}
+
+type SBudgetHolder struct {
+	Task   string    `json:"task" description:"任务名称"`
+	Step   string    `json:"step" description:"步骤名称"`
+	Weight int64     `json:"weight" description:"占用槽位"`
+	Mutex  string    `json:"mutex,omitempty" description:"互斥组"`
+	Since  time.Time `json:"since" description:"开始占用或等待的时间"`
+}
+
+// SJanitor 节点的清理统计
+type SJanitor struct {
+	LastRun time.Time `json:"last_run" description:"最近一次清理的时间"`
+	Last    SReclaim  `json:"last" description:"最近一次清理"`
+	Total   SReclaim  `json:"total" description:"累计清理"`
+}
+
+// SReclaim 清理的数量及释放的磁盘空间
+type SReclaim struct {
+	Tasks    int64 `json:"tasks,omitempty" description:"删除的任务"`
+	Archived int64 `json:"archived,omitempty" description:"删除前归档的任务"`
+	Builds   int64 `json:"builds,omitempty" description:"删除的构建"`
+	Runs     int64 `json:"runs,omitempty" description:"删除的历史执行记录"`
+	LogLines int64 `json:"log_lines,omitempty" description:"裁剪的日志行数"`
+	Uploads  int64 `json:"uploads,omitempty" description:"删除的过期上传文件"`
+	Dirs     int64 `json:"dirs,omitempty" description:"删除的残留脚本及工作目录"`
+	Bytes    int64 `json:"bytes,omitempty" description:"释放的空间"`
+}
+
+// Add 累加清理统计
+func (r *SReclaim) Add(o SReclaim) {
+	r.Tasks += o.Tasks
+	r.Archived += o.Archived
+	r.Builds += o.Builds
+	r.Runs += o.Runs
+	r.LogLines += o.LogLines
+	r.Uploads += o.Uploads
+	r.Dirs += o.Dirs
+	r.Bytes += o.Bytes
+}
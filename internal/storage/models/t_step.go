@@ -13,6 +13,31 @@ type SRetryPolicy struct {
 	MaxInterval time.Duration `json:"maxInterval,omitempty" description:"最大间隔时间"`
 	MaxAttempts int           `json:"maxAttempts,omitempty" description:"最大尝试次数"`
 	Multiplier  float64       `json:"multiplier,omitempty" description:"乘数"`
+	Jitter      string        `json:"jitter,omitempty" description:"随机抖动, full或equal"`
+	RetryOn     *SRetryOn     `json:"retryOn,omitempty" description:"重试条件, 满足任一条件才重试"`
+	RetryIf     string        `json:"retryIf,omitempty" description:"重试条件表达式"`
+}
+
+type SRetryOn struct {
+	ExitCodes []int64 `json:"exitCodes,omitempty" description:"退出码"`
+	Timeout   bool    `json:"timeout,omitempty" description:"超时"`
+	Pattern   string  `json:"pattern,omitempty" description:"匹配错误信息或最后几行日志的正则"`
+	LogLines  int     `json:"logLines,omitempty" description:"正则匹配的日志行数"`
+}
+
+type SMatrix struct {
+	Group       string              `json:"group,omitempty" description:"所属矩阵步骤"`
+	Axes        map[string][]string `json:"axes,omitempty" description:"参数轴"`
+	Values      map[string]string   `json:"values,omitempty" description:"当前实例参数"`
+	MaxParallel int                 `json:"maxParallel,omitempty" description:"最大并行数"`
+}
+
+type SForeach struct {
+	From        string `json:"from,omitempty" description:"上游步骤的输出, 格式为<步骤>.<输出>"`
+	MaxParallel int    `json:"maxParallel,omitempty" description:"最大并行数"`
+	Parent      string `json:"parent,omitempty" description:"生成当前步骤的foreach步骤"`
+	Index       int    `json:"index,omitempty" description:"元素序号"`
+	Item        string `json:"item,omitempty" description:"元素"`
 }
 
 type SStep struct {
@@ -24,11 +49,22 @@ type SStep struct {
 	Content     string                           `json:"content,omitempty" gorm:"comment:内容"`
 	Action      string                           `json:"action,omitempty" gorm:"comment:动作"`
 	Rule        string                           `json:"rule,omitempty" gorm:"comment:规则"`
+	When        string                           `json:"when,omitempty" gorm:"column:run_when;comment:执行条件"`
 	RetryPolicy datatypes.JSONType[SRetryPolicy] `json:"retry_policy,omitempty" gorm:"comment:重试策略"`
+	Matrix      datatypes.JSONType[SMatrix]      `json:"matrix,omitempty" gorm:"comment:矩阵"`
+	Foreach     datatypes.JSONType[SForeach]     `json:"foreach,omitempty" gorm:"comment:按上游输出动态生成步骤"`
+	Handler     string                           `json:"handler,omitempty" gorm:"size:32;index;comment:任务处理步骤类型"`
 	SeqNo       int64                            `json:"seq_no,omitempty" gorm:"index;not null;default:0;comment:序号"`
+	Priority    int                              `json:"priority,omitempty" gorm:"not null;default:0;comment:优先级"`
+	Weight      int64                            `json:"weight,omitempty" gorm:"not null;default:1;comment:占用节点预算的槽位"`
+	Mutex       string                           `json:"mutex,omitempty" gorm:"size:256;comment:节点互斥组"`
 	Timeout     time.Duration                    `json:"timeout,omitempty" gorm:"not null;default:86400000000000;comment:超时时间"`
 	Disable     *bool                            `json:"disable,omitempty" gorm:"not null;default:false;comment:禁用"`
+	Idempotent  *bool                            `json:"idempotent,omitempty" gorm:"not null;default:false;comment:幂等, 中断后可重新执行"`
 	Metadata    datatypes.JSONMap                `json:"metadata,omitempty" gorm:"元数据"`
+
+	// AllowFailure 失败时不阻塞下游步骤
+	AllowFailure *bool `json:"allow_failure,omitempty" gorm:"not null;default:false;comment:允许失败"`
 	SStepUpdate
 }
 
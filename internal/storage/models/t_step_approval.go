@@ -0,0 +1,14 @@
+package models
+
+type SStepApproval struct {
+	SBase
+	TaskName string `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_approval;not null;comment:任务名称"`
+	StepName string `json:"step_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_approval;not null;comment:步骤名称"`
+	Approved *bool  `json:"approved,omitempty" gorm:"not null;default:false;comment:是否通过"`
+	Approver string `json:"approver,omitempty" gorm:"size:256;comment:审批人"`
+	Comment  string `json:"comment,omitempty" gorm:"type:text;comment:审批意见"`
+}
+
+func (s *SStepApproval) TableName() string {
+	return "t_step_approval"
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/busyster996/dagflow/internal/common"
+)
+
+type SStepAttempt struct {
+	SBase
+	TaskName string           `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_attempt;not null;comment:任务名称"`
+	StepName string           `json:"step_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_attempt;not null;comment:步骤名称"`
+	Attempt  int              `json:"attempt,omitempty" gorm:"uniqueIndex:idx_step_attempt;not null;comment:尝试次数"`
+	State    *State           `json:"state,omitempty" gorm:"not null;default:0;comment:状态"`
+	Code     *common.ExecCode `json:"code,omitempty" gorm:"not null;default:0;comment:退出码"`
+	Message  string           `json:"message,omitempty" gorm:"comment:消息"`
+	STime    *time.Time       `json:"s_time,omitempty" gorm:"comment:开始时间"`
+	ETime    *time.Time       `json:"e_time,omitempty" gorm:"comment:结束时间"`
+	LogStart *int64           `json:"log_start,omitempty" gorm:"comment:起始日志行号"`
+	LogEnd   *int64           `json:"log_end,omitempty" gorm:"comment:结束日志行号"`
+}
+
+func (a *SStepAttempt) TableName() string {
+	return "t_step_attempt"
+}
+
+type SStepAttempts []*SStepAttempt
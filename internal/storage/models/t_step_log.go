@@ -2,10 +2,11 @@ package models
 
 type SStepLog struct {
 	SBase
-	TaskName  string `json:"task_name,omitempty" gorm:"size:256;index;not null;comment:任务名称"`
-	StepName  string `json:"step_name,omitempty" gorm:"size:256;index;not null;comment:步骤名称"`
+	TaskName  string `json:"task_name,omitempty" gorm:"size:256;index;index:idx_step_log_run,priority:1;not null;comment:任务名称"`
+	StepName  string `json:"step_name,omitempty" gorm:"size:256;index;index:idx_step_log_run,priority:2;not null;comment:步骤名称"`
+	Run       int64  `json:"run,omitempty" gorm:"index:idx_step_log_run,priority:3;not null;default:0;comment:历史执行序号, 0为当前执行"`
 	Timestamp int64  `json:"timestamp,omitempty" gorm:"not null;comment:时间戳"`
-	Line      *int64 `json:"line,omitempty" gorm:"not null;comment:行号"`
+	Line      *int64 `json:"line,omitempty" gorm:"index:idx_step_log_run,priority:4;not null;comment:行号"`
 	Content   string `json:"content,omitempty" gorm:"comment:内容"`
 }
 
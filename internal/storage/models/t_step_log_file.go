@@ -0,0 +1,18 @@
+package models
+
+// SStepLogFile 文件存储的步骤日志元数据
+type SStepLogFile struct {
+	SBase
+	TaskName  string `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_log_file,priority:1;not null;comment:任务名称"`
+	StepName  string `json:"step_name,omitempty" gorm:"size:256;uniqueIndex:idx_step_log_file,priority:2;not null;comment:步骤名称"`
+	FirstLine int64  `json:"first_line,omitempty" gorm:"not null;default:0;comment:保留的起始行号"`
+	LineCount int64  `json:"line_count,omitempty" gorm:"not null;default:0;comment:总行数"`
+	Chunks    int64  `json:"chunks,omitempty" gorm:"not null;default:0;comment:压缩块数"`
+	Size      int64  `json:"size,omitempty" gorm:"not null;default:0;comment:文件大小"`
+}
+
+func (l *SStepLogFile) TableName() string {
+	return "t_step_log_file"
+}
+
+type SStepLogFiles []*SStepLogFile
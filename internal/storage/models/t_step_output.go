@@ -0,0 +1,13 @@
+package models
+
+type SStepOutput struct {
+	SBase
+	TaskName string `json:"task_name,omitempty" gorm:"size:256;index:,unique,composite:key;not null;comment:任务名称"`
+	StepName string `json:"step_name,omitempty" gorm:"size:256;index:,unique,composite:key;not null;comment:步骤名称"`
+	Name     string `json:"name,omitempty" gorm:"size:256;index:,unique,composite:key;not null;comment:名称"`
+	Value    string `json:"value,omitempty" gorm:"type:text;comment:值"`
+}
+
+func (s *SStepOutput) TableName() string {
+	return "t_step_output"
+}
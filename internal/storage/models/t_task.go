@@ -8,13 +8,16 @@ import (
 
 type STask struct {
 	SBase
-	Kind     string            `json:"kind,omitempty" gorm:"size:256;index;comment:类型"`
-	Name     string            `json:"name,omitempty" gorm:"size:256;uniqueIndex;not null;comment:名称"`
-	Desc     string            `json:"desc,omitempty" gorm:"comment:描述"`
-	Node     string            `json:"node,omitempty" gorm:"size:256;index;default:null;comment:节点"`
-	Timeout  time.Duration     `json:"timeout,omitempty" gorm:"not null;default:86400000000000;comment:超时时间"`
-	Disable  *bool             `json:"disable,omitempty" gorm:"not null;default:false;comment:禁用"`
-	Metadata datatypes.JSONMap `json:"metadata,omitempty" gorm:"元数据"`
+	Kind              string            `json:"kind,omitempty" gorm:"size:256;index;comment:类型"`
+	Name              string            `json:"name,omitempty" gorm:"size:256;uniqueIndex;not null;comment:名称"`
+	Desc              string            `json:"desc,omitempty" gorm:"comment:描述"`
+	Node              string            `json:"node,omitempty" gorm:"size:256;index;default:null;comment:节点"`
+	Timeout           time.Duration     `json:"timeout,omitempty" gorm:"not null;default:86400000000000;comment:超时时间"`
+	Disable           *bool             `json:"disable,omitempty" gorm:"not null;default:false;comment:禁用"`
+	FailFast          *bool             `json:"fail_fast,omitempty" gorm:"not null;default:false;comment:快速失败"`
+	ConcurrencyGroup  string            `json:"concurrency_group,omitempty" gorm:"size:256;index;comment:并发组"`
+	ConcurrencyPolicy string            `json:"concurrency_policy,omitempty" gorm:"size:32;comment:并发策略"`
+	Metadata          datatypes.JSONMap `json:"metadata,omitempty" gorm:"元数据"`
 	STaskUpdate
 }
 
@@ -0,0 +1,16 @@
+package models
+
+type STaskDepend struct {
+	SBase
+	TaskName string `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_task_depend;not null;comment:任务名称"`
+	Name     string `json:"name,omitempty" gorm:"size:256;uniqueIndex:idx_task_depend;not null;comment:前置任务名称"`
+	When     string `json:"when,omitempty" gorm:"size:32;comment:前置任务需满足的结束状态"`
+	State    *State `json:"state,omitempty" gorm:"index;not null;comment:状态"`
+	Message  string `json:"message,omitempty" gorm:"comment:消息"`
+}
+
+func (t *STaskDepend) TableName() string {
+	return "t_task_depend"
+}
+
+type STaskDepends []*STaskDepend
@@ -0,0 +1,18 @@
+package models
+
+// STaskRun 任务的历史执行记录, 重新提交任务前保存上一次执行的任务详情及请求
+type STaskRun struct {
+	SBase
+	TaskName string `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_task_run,priority:1;not null;comment:任务名称"`
+	Run      int64  `json:"run,omitempty" gorm:"uniqueIndex:idx_task_run,priority:2;not null;comment:执行序号"`
+	Count    int64  `json:"count,omitempty" gorm:"not null;default:0;comment:步骤数"`
+	Request  string `json:"-" gorm:"comment:提交的任务请求JSON"`
+	Detail   string `json:"-" gorm:"comment:任务详情JSON"`
+	STaskUpdate
+}
+
+func (r *STaskRun) TableName() string {
+	return "t_task_run"
+}
+
+type STaskRuns []*STaskRun
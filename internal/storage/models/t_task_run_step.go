@@ -0,0 +1,19 @@
+package models
+
+// STaskRunStep 历史执行中的步骤, 日志按执行序号保留在日志后端中
+type STaskRunStep struct {
+	SBase
+	TaskName string `json:"task_name,omitempty" gorm:"size:256;uniqueIndex:idx_task_run_step,priority:1;not null;comment:任务名称"`
+	Run      int64  `json:"run,omitempty" gorm:"uniqueIndex:idx_task_run_step,priority:2;not null;comment:执行序号"`
+	StepName string `json:"step_name,omitempty" gorm:"size:256;uniqueIndex:idx_task_run_step,priority:3;not null;comment:步骤名称"`
+	Seq      int64  `json:"seq,omitempty" gorm:"not null;default:0;comment:排序"`
+	State    *State `json:"state,omitempty" gorm:"not null;default:0;comment:状态"`
+	Message  string `json:"message,omitempty" gorm:"comment:消息"`
+	Detail   string `json:"-" gorm:"comment:步骤详情JSON, 包含各次尝试"`
+}
+
+func (r *STaskRunStep) TableName() string {
+	return "t_task_run_step"
+}
+
+type STaskRunSteps []*STaskRunStep
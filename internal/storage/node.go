@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sNode struct {
+	*gorm.DB
+	name string
+}
+
+func (n *sNode) Name() string {
+	return n.name
+}
+
+func (n *sNode) Get() (res *models.SNode, err error) {
+	res = new(models.SNode)
+	err = n.Model(&models.SNode{}).
+		Where("name = ?", n.name).
+		First(res).
+		Error
+	return
+}
+
+// UpdateBudget 保存节点的步骤并发预算, 节点不存在时创建
+func (n *sNode) UpdateBudget(budget models.SBudget) error {
+	return n.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"budget", "updated_at"}),
+	}).Create(&models.SNode{
+		Name:   n.name,
+		Budget: datatypes.NewJSONType(budget),
+	}).Error
+}
+
+// UpdateJanitor 保存节点的清理统计, 节点不存在时创建
+func (n *sNode) UpdateJanitor(janitor models.SJanitor) error {
+	return n.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"janitor", "updated_at"}),
+	}).Create(&models.SNode{
+		Name:    n.name,
+		Janitor: datatypes.NewJSONType(janitor),
+	}).Error
+}
@@ -67,3 +67,21 @@ func (p *sPipelineBuild) Remove(name string) (err error) {
 func (p *sPipelineBuild) ClearAll() error {
 	return p.Where("pipeline_name = ?", p.pName).Delete(&models.SPipelineBuild{}).Error
 }
+
+func (p *sPipelineBuild) Expired(keep int) (tasks []string) {
+	var names []string
+	p.Model(&models.SPipelineBuild{}).
+		Where("pipeline_name = ?", p.pName).
+		Order("id DESC").
+		Pluck("task_name", &names)
+	if len(names) <= keep {
+		return nil
+	}
+	// 每次最多处理最早的 500 个
+	names = names[max(keep, len(names)-500):]
+	p.Model(&models.STask{}).
+		Where("name IN (?)", names).
+		Where("state IN (?)", endedStates).
+		Pluck("name", &tasks)
+	return
+}
@@ -14,9 +14,12 @@ type sStep struct {
 	tName string
 	sName string
 
-	env    IEnv
-	depend IDepend
-	log    ILog
+	env      IEnv
+	output   IEnv
+	depend   IDepend
+	log      ILog
+	approval IApproval
+	attempt  IAttempt
 }
 
 func (s *sStep) Name() string {
@@ -30,9 +33,21 @@ func (s *sStep) ClearAll() error {
 	if err := s.Env().RemoveAll(); err != nil {
 		return err
 	}
+	if err := s.Output().RemoveAll(); err != nil {
+		return err
+	}
+	if err := s.Export().RemoveAll(); err != nil {
+		return err
+	}
 	if err := s.Depend().RemoveAll(); err != nil {
 		return err
 	}
+	if err := s.Approval().Remove(); err != nil {
+		return err
+	}
+	if err := s.Attempt().RemoveAll(); err != nil {
+		return err
+	}
 	return s.Log().RemoveAll()
 }
 
@@ -69,6 +84,20 @@ func (s *sStep) IsDisable() (disable bool) {
 	return
 }
 
+func (s *sStep) AllowFailure() (allow bool) {
+	if s.Model(&models.SStep{}).
+		Select("allow_failure").
+		Where(map[string]interface{}{
+			"task_name": s.tName,
+			"name":      s.sName,
+		}).
+		Scan(&allow).
+		Error != nil {
+		return
+	}
+	return
+}
+
 func (s *sStep) Env() IEnv {
 	if s.env == nil {
 		s.env = &sStepEnv{
@@ -80,6 +109,17 @@ func (s *sStep) Env() IEnv {
 	return s.env
 }
 
+func (s *sStep) Output() IEnv {
+	if s.output == nil {
+		s.output = &sStepOutput{
+			DB:    s.DB,
+			tName: s.tName,
+			sName: s.sName,
+		}
+	}
+	return s.output
+}
+
 func (s *sStep) TaskName() string {
 	return s.tName
 }
@@ -96,6 +136,42 @@ func (s *sStep) Timeout() (res time.Duration, err error) {
 	return
 }
 
+func (s *sStep) Priority() (res int, err error) {
+	err = s.Model(&models.SStep{}).
+		Select("priority").
+		Where(map[string]interface{}{
+			"task_name": s.tName,
+			"name":      s.sName,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
+func (s *sStep) Weight() (res int64, err error) {
+	err = s.Model(&models.SStep{}).
+		Select("weight").
+		Where(map[string]interface{}{
+			"task_name": s.tName,
+			"name":      s.sName,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
+func (s *sStep) Mutex() (res string, err error) {
+	err = s.Model(&models.SStep{}).
+		Select("mutex").
+		Where(map[string]interface{}{
+			"task_name": s.tName,
+			"name":      s.sName,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
 func (s *sStep) Type() (res string, err error) {
 	err = s.Model(&models.SStep{}).
 		Select("type").
@@ -144,6 +220,18 @@ func (s *sStep) Rule() (res string, err error) {
 	return
 }
 
+func (s *sStep) When() (res string, err error) {
+	err = s.Model(&models.SStep{}).
+		Select("run_when").
+		Where(map[string]interface{}{
+			"task_name": s.tName,
+			"name":      s.sName,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
 func (s *sStep) RetryPolicy() (res models.SRetryPolicy, err error) {
 	var tmp models.SStep
 	err = s.Model(&models.SStep{}).
@@ -211,10 +299,44 @@ func (s *sStep) Depend() IDepend {
 func (s *sStep) Log() ILog {
 	if s.log == nil {
 		s.log = &sStepLog{
+			backend: logBackend,
+			tName:   s.tName,
+			sName:   s.sName,
+		}
+	}
+	return s.log
+}
+
+func (s *sStep) Approval() IApproval {
+	if s.approval == nil {
+		s.approval = &sStepApproval{
 			DB:    s.DB,
 			tName: s.tName,
 			sName: s.sName,
 		}
 	}
-	return s.log
+	return s.approval
+}
+
+func (s *sStep) Export() IEnv {
+	return &sStepExport{
+		DB:    s.DB,
+		tName: s.tName,
+		sName: s.sName,
+	}
+}
+
+func (s *sStep) UpstreamEnv() models.SEnvs {
+	return upstreamEnv(s.DB, s.tName, s.sName)
+}
+
+func (s *sStep) Attempt() IAttempt {
+	if s.attempt == nil {
+		s.attempt = &sStepAttempt{
+			DB:    s.DB,
+			tName: s.tName,
+			sName: s.sName,
+		}
+	}
+	return s.attempt
 }
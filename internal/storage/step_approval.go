@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sStepApproval struct {
+	*gorm.DB
+	tName string
+	sName string
+}
+
+// Get 获取审批记录, 未审批时返回 ErrNotFound
+func (a *sStepApproval) Get() (res *models.SStepApproval, err error) {
+	res = new(models.SStepApproval)
+	err = a.Model(&models.SStepApproval{}).
+		Where(map[string]interface{}{
+			"task_name": a.tName,
+			"step_name": a.sName,
+		}).
+		First(res).
+		Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	return
+}
+
+// Insert 写入审批记录, 每个步骤仅允许一条, 重复审批返回错误
+func (a *sStepApproval) Insert(approval *models.SStepApproval) (err error) {
+	approval.TaskName = a.tName
+	approval.StepName = a.sName
+	return a.Create(approval).Error
+}
+
+func (a *sStepApproval) Remove() (err error) {
+	return a.Where(map[string]interface{}{
+		"task_name": a.tName,
+		"step_name": a.sName,
+	}).Delete(&models.SStepApproval{}).Error
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sStepAttempt struct {
+	*gorm.DB
+	tName string
+	sName string
+}
+
+func (a *sStepAttempt) List() (res models.SStepAttempts) {
+	a.Model(&models.SStepAttempt{}).
+		Where(map[string]interface{}{
+			"task_name": a.tName,
+			"step_name": a.sName,
+		}).
+		Order("attempt ASC").
+		Find(&res)
+	return
+}
+
+func (a *sStepAttempt) Get(attempt int) (res *models.SStepAttempt, err error) {
+	res = new(models.SStepAttempt)
+	err = a.Model(&models.SStepAttempt{}).
+		Where(map[string]interface{}{
+			"task_name": a.tName,
+			"step_name": a.sName,
+			"attempt":   attempt,
+		}).
+		First(res).
+		Error
+	return
+}
+
+// Insert 写入执行记录, 恢复执行时同一次尝试的旧记录会被替换
+func (a *sStepAttempt) Insert(attempt *models.SStepAttempt) (err error) {
+	attempt.TaskName = a.tName
+	attempt.StepName = a.sName
+	return a.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(map[string]interface{}{
+			"task_name": a.tName,
+			"step_name": a.sName,
+			"attempt":   attempt.Attempt,
+		}).Delete(&models.SStepAttempt{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(attempt).Error
+	})
+}
+
+func (a *sStepAttempt) Update(attempt *models.SStepAttempt) (err error) {
+	return a.Model(&models.SStepAttempt{}).
+		Where(map[string]interface{}{
+			"task_name": a.tName,
+			"step_name": a.sName,
+			"attempt":   attempt.Attempt,
+		}).
+		Updates(attempt).
+		Error
+}
+
+func (a *sStepAttempt) RemoveAll() (err error) {
+	return a.Where(map[string]interface{}{
+		"task_name": a.tName,
+		"step_name": a.sName,
+	}).Delete(&models.SStepAttempt{}).Error
+}
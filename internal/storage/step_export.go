@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+// sStepExport 步骤导出的环境变量, 仅下游步骤可见, 不属于任务的环境变量
+type sStepExport struct {
+	*gorm.DB
+	tName string
+	sName string
+}
+
+func (e *sStepExport) List() (res models.SEnvs) {
+	e.Model(&models.SStepExport{}).
+		Select("name, value").
+		Where(map[string]interface{}{
+			"task_name": e.tName,
+			"step_name": e.sName,
+		}).
+		Order("id ASC").
+		Find(&res)
+	return
+}
+
+func (e *sStepExport) Insert(envs ...*models.SEnv) (err error) {
+	if len(envs) == 0 {
+		return
+	}
+	var _envs []models.SStepExport
+	for _, env := range envs {
+		_envs = append(_envs, models.SStepExport{
+			TaskName: e.tName,
+			StepName: e.sName,
+			Name:     env.Name,
+			Value:    env.Value,
+		})
+	}
+	return e.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "task_name"},
+			{Name: "step_name"},
+			{Name: "name"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(_envs).Error
+}
+
+func (e *sStepExport) Update(env *models.SEnv) (err error) {
+	return e.Model(&models.SStepExport{}).
+		Where(map[string]interface{}{
+			"task_name": e.tName,
+			"step_name": e.sName,
+			"name":      env.Name,
+		}).
+		Update("value", env.Value).Error
+}
+
+func (e *sStepExport) Get(name string) (res string, err error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+	err = e.Model(&models.SStepExport{}).
+		Select("value").
+		Where(map[string]interface{}{
+			"task_name": e.tName,
+			"step_name": e.sName,
+			"name":      name,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
+func (e *sStepExport) Remove(name string) (err error) {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	return e.Where(map[string]interface{}{
+		"task_name": e.tName,
+		"step_name": e.sName,
+		"name":      name,
+	}).Delete(&models.SStepExport{}).Error
+}
+
+func (e *sStepExport) RemoveAll() (err error) {
+	return e.Where(map[string]interface{}{
+		"task_name": e.tName,
+		"step_name": e.sName,
+	}).Delete(&models.SStepExport{}).Error
+}
+
+// upstreamEnv 所有上游步骤(含间接依赖)导出的环境变量, 同名时以最后导出的为准.
+// foreach 生成的子步骤沿用父步骤的上游
+func upstreamEnv(db *gorm.DB, tName, sName string) (res models.SEnvs) {
+	var upstream []string
+	var visited = map[string]bool{sName: true}
+	var stack = []string{sName}
+	for len(stack) > 0 {
+		name := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		step := Task(tName).Step(name)
+		depends := step.Depend().List()
+		if v, err := step.Get(); err == nil {
+			if parent := v.Foreach.Data().Parent; parent != "" {
+				depends = append(depends, parent)
+			}
+		}
+		for _, depend := range depends {
+			if visited[depend] {
+				continue
+			}
+			visited[depend] = true
+			upstream = append(upstream, depend)
+			stack = append(stack, depend)
+		}
+	}
+	if len(upstream) == 0 {
+		return
+	}
+	var exports []*models.SStepExport
+	db.Model(&models.SStepExport{}).
+		Where("task_name = ? AND step_name IN ?", tName, upstream).
+		Order("updated_at ASC, id ASC").
+		Find(&exports)
+	for _, export := range exports {
+		res = slices.DeleteFunc(res, func(env *models.SEnv) bool {
+			return env.Name == export.Name
+		})
+		res = append(res, &models.SEnv{
+			Name:  export.Name,
+			Value: export.Value,
+		})
+	}
+	return
+}
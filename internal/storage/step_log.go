@@ -3,7 +3,6 @@ package storage
 import (
 	"fmt"
 	"strings"
-	"sync"
 	"time"
 
 	"gorm.io/gorm"
@@ -12,46 +11,83 @@ import (
 	"github.com/busyster996/dagflow/pkg/logx"
 )
 
-type sStepLog struct {
-	*gorm.DB
-	tName string
-	sName string
+const (
+	LogStorageDatabase = "database"
+	LogStorageFile     = "file"
+)
 
-	lock sync.Mutex
+// iLogBackend 步骤日志的持久化后端
+type iLogBackend interface {
+	// next 准备追加写入, 返回步骤的下一个行号
+	next(tName, sName string) (int64, error)
+	// write 写入一批日志, 可包含多个步骤, 同一步骤的日志行号递增
+	write(logs models.SStepLogs) error
+	list(tName, sName string, latestLine *int64) models.SStepLogs
+	lines(tName, sName string, start, end int64) models.SStepLogs
+	remove(tName, sName string) error
+	// trim 删除 keep 行之前的日志
+	trim(tName, sName string, keep int64) (lines, size int64, err error)
+	// oversized tasks 任务中日志超过 lines 行的步骤
+	oversized(tasks *gorm.DB, lines int64) models.SStepLogs
+	// archive 当前执行的日志归档为第 run 次执行, 不复制日志内容
+	archive(tName, sName string, run int64) error
+	// history 第 run 次执行行号在 [start, end] 之间的日志, end为负数时不限制
+	history(tName, sName string, run, start, end int64) models.SStepLogs
+	// removeRuns 删除任务序号不大于 upto 的历史执行日志, 返回释放的空间
+	removeRuns(tName string, upto int64) (size int64, err error)
 }
 
-func (l *sStepLog) List(latestLine *int64) (res models.SStepLogs) {
-	query := l.Model(&models.SStepLog{}).
-		Where(map[string]interface{}{
-			"task_name": l.tName,
-			"step_name": l.sName,
-		}).Order("line ASC")
-	if latestLine != nil {
-		// 如果 latestLine 不为空，只查询行号大于 latestLine 的日志
-		query = query.Where("line > ?", latestLine).Limit(500)
+// logBackend 步骤日志后端, 默认存储在数据库中
+var logBackend iLogBackend
+
+// SetLogStorage 设置步骤日志的存储方式, file 时日志压缩存储在 dir 目录下, 数据库仅保存元数据
+// database 时日志存储在当前存储引擎的日志表中
+func SetLogStorage(kind, dir string) error {
+	var db *sDatabase
+	switch s := storage.(type) {
+	case *sDatabase:
+		db = s
+	case *sEntStorage:
+		db = s.sDatabase
+	default:
+		return fmt.Errorf("storage is not initialized")
 	}
-	query.Find(&res)
-	return
+	switch kind {
+	case LogStorageDatabase, "":
+		if s, ok := storage.(*sEntStorage); ok {
+			logBackend = &sEntLog{Client: s.client, runs: &sDBLog{DB: db.DB}}
+			break
+		}
+		logBackend = &sDBLog{DB: db.DB}
+	case LogStorageFile:
+		logBackend = &sFileLog{DB: db.DB, dir: dir}
+	default:
+		return fmt.Errorf("unsupported step log storage %s", kind)
+	}
+	return nil
+}
+
+type sStepLog struct {
+	backend iLogBackend
+	tName   string
+	sName   string
+}
+
+func (l *sStepLog) List(latestLine *int64) (res models.SStepLogs) {
+	logBuffer.flush(logKey(l.tName, l.sName))
+	return l.backend.list(l.tName, l.sName, latestLine)
+}
+
+func (l *sStepLog) Range(start, end int64) (res models.SStepLogs) {
+	logBuffer.flush(logKey(l.tName, l.sName))
+	return l.backend.lines(l.tName, l.sName, start, end)
 }
 
+// Insert 立即分配行号, 日志批量异步写入
 func (l *sStepLog) Insert(log *models.SStepLog) error {
-	l.lock.Lock()
-	defer l.lock.Unlock()
 	log.TaskName = l.tName
 	log.StepName = l.sName
-	return l.Transaction(func(tx *gorm.DB) error {
-		var count int64
-		if err := l.Model(&models.SStepLog{}).
-			Where(map[string]interface{}{
-				"task_name": l.tName,
-				"step_name": l.sName,
-			}).
-			Count(&count).Error; err != nil {
-			return err
-		}
-		log.Line = models.Pointer(count)
-		return tx.Create(log).Error
-	})
+	return logBuffer.insert(l.backend, log)
 }
 
 func (l *sStepLog) Write(contents ...string) {
@@ -68,8 +104,182 @@ func (l *sStepLog) Writef(format string, args ...interface{}) {
 }
 
 func (l *sStepLog) RemoveAll() (err error) {
-	return l.Where(map[string]interface{}{
-		"task_name": l.tName,
-		"step_name": l.sName,
+	return logBuffer.remove(logKey(l.tName, l.sName), func() error {
+		return l.backend.remove(l.tName, l.sName)
+	})
+}
+
+// Archive 写入缓冲的日志后归档为第 run 次执行, 之后的日志行号从0开始
+func (l *sStepLog) Archive(run int64) error {
+	return logBuffer.archive(logKey(l.tName, l.sName), func() error {
+		return l.backend.archive(l.tName, l.sName, run)
+	})
+}
+
+// History 第 run 次执行行号在 [start, end] 之间的日志, end为负数时不限制
+func (l *sStepLog) History(run, start, end int64) models.SStepLogs {
+	return l.backend.history(l.tName, l.sName, run, start, end)
+}
+
+// Trim 裁剪期间暂停该步骤日志的写入
+func (l *sStepLog) Trim(keep int64) (lines, size int64, err error) {
+	err = logBuffer.locked(logKey(l.tName, l.sName), func() error {
+		lines, size, err = l.backend.trim(l.tName, l.sName, keep)
+		return err
+	})
+	return
+}
+
+// sDBLog 日志逐行存储在数据库中
+type sDBLog struct {
+	*gorm.DB
+}
+
+func (d *sDBLog) next(tName, sName string) (int64, error) {
+	var last int64
+	if err := d.Model(&models.SStepLog{}).
+		Select("COALESCE(MAX(line), -1)").
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       0,
+		}).
+		Scan(&last).Error; err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+func (d *sDBLog) write(logs models.SStepLogs) error {
+	return d.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(logs, logBatchSize).Error
+	})
+}
+
+func (d *sDBLog) list(tName, sName string, latestLine *int64) (res models.SStepLogs) {
+	query := d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       0,
+		}).Order("line ASC")
+	if latestLine != nil {
+		// 如果 latestLine 不为空，只查询行号大于 latestLine 的日志
+		query = query.Where("line > ?", latestLine).Limit(logListLimit)
+	}
+	query.Find(&res)
+	return
+}
+
+func (d *sDBLog) lines(tName, sName string, start, end int64) (res models.SStepLogs) {
+	d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       0,
+		}).
+		Where("line BETWEEN ? AND ?", start, end).
+		Order("line ASC").
+		Find(&res)
+	return
+}
+
+func (d *sDBLog) remove(tName, sName string) error {
+	return d.Where(map[string]interface{}{
+		"task_name": tName,
+		"step_name": sName,
+		"run":       0,
 	}).Delete(&models.SStepLog{}).Error
 }
+
+func (d *sDBLog) trim(tName, sName string, keep int64) (lines, size int64, err error) {
+	next, err := d.next(tName, sName)
+	if err != nil || next <= keep {
+		return 0, 0, err
+	}
+	query := d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       0,
+		}).
+		Where("line < ?", next-keep)
+	length := "LENGTH"
+	if d.Name() == TypeSqlserver {
+		length = "LEN"
+	}
+	if err = query.Session(&gorm.Session{}).
+		Select(fmt.Sprintf("COALESCE(SUM(%s(content)), 0)", length)).
+		Scan(&size).Error; err != nil {
+		return 0, 0, err
+	}
+	res := query.Delete(&models.SStepLog{})
+	return res.RowsAffected, size, res.Error
+}
+
+func (d *sDBLog) oversized(tasks *gorm.DB, lines int64) (res models.SStepLogs) {
+	var rows []struct {
+		TaskName  string
+		StepName  string
+		LineCount int64
+	}
+	d.Model(&models.SStepLog{}).
+		Select("task_name, step_name, COUNT(*) AS line_count").
+		Where("task_name IN (?)", tasks).
+		Where("run = 0").
+		Group("task_name, step_name").
+		Having("COUNT(*) > ?", lines).
+		Scan(&rows)
+	for _, row := range rows {
+		res = append(res, &models.SStepLog{
+			TaskName: row.TaskName,
+			StepName: row.StepName,
+			Line:     models.Pointer(row.LineCount),
+		})
+	}
+	return
+}
+
+func (d *sDBLog) archive(tName, sName string, run int64) error {
+	return d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       0,
+		}).
+		Update("run", run).Error
+}
+
+func (d *sDBLog) history(tName, sName string, run, start, end int64) (res models.SStepLogs) {
+	query := d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+			"run":       run,
+		}).
+		Where("line >= ?", start).
+		Order("line ASC")
+	if end >= 0 {
+		query = query.Where("line <= ?", end)
+	}
+	query.Find(&res)
+	return
+}
+
+func (d *sDBLog) removeRuns(tName string, upto int64) (size int64, err error) {
+	query := d.Model(&models.SStepLog{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+		}).
+		Where("run BETWEEN 1 AND ?", upto)
+	length := "LENGTH"
+	if d.Name() == TypeSqlserver {
+		length = "LEN"
+	}
+	if err = query.Session(&gorm.Session{}).
+		Select(fmt.Sprintf("COALESCE(SUM(%s(content)), 0)", length)).
+		Scan(&size).Error; err != nil {
+		return 0, err
+	}
+	return size, query.Delete(&models.SStepLog{}).Error
+}
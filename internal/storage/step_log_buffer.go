@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"sync"
+	"time"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+const (
+	// 单个步骤缓冲的日志达到该行数时立即写入
+	logBatchSize = 500
+	// 缓冲的日志最长等待写入的时间
+	logFlushInterval = 200 * time.Millisecond
+	// 所有步骤等待写入的日志行数上限, 超过时阻塞写日志的一方, 直到数据库追上
+	logMaxPending = 20000
+	// 连续多少次写入周期没有新日志时释放步骤的行号计数
+	logIdleFlushes = 50
+	// 增量查询单次返回的最大行数
+	logListLimit = 500
+	// 写入失败的日志保留在缓冲中重试, 连续失败超过该次数时丢弃
+	logMaxRetries = 50
+)
+
+// logBuffer 步骤日志的批量写入缓冲, 行号在内存中递增分配
+var logBuffer = newLogBuffer()
+
+type sLogBuffer struct {
+	mu      sync.Mutex // 仅保护内存中的步骤计数及缓冲, 持有期间不做任何读写
+	cond    *sync.Cond
+	flushMu sync.Mutex // 串行化写入, 写入完成前查询会等待, 保证已分配的行号均已落库
+	writers map[string]*sLogWriter
+	pending int
+	wake    chan struct{}
+	once    sync.Once
+}
+
+// sLogWriter 单个步骤的行号计数及待写入的日志
+type sLogWriter struct {
+	backend iLogBackend
+	once    sync.Once // 首次写入时查询已有日志的最大行号
+	err     error
+	next    int64
+	lines   models.SStepLogs
+	idle    int
+	retries int // 连续写入失败的次数
+}
+
+func newLogBuffer() *sLogBuffer {
+	b := &sLogBuffer{
+		writers: make(map[string]*sLogWriter),
+		wake:    make(chan struct{}, 1),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// insert 分配行号并放入缓冲, 等待写入的日志过多时阻塞
+func (b *sLogBuffer) insert(backend iLogBackend, log *models.SStepLog) error {
+	b.once.Do(func() {
+		go b.run()
+	})
+	key := logKey(log.TaskName, log.StepName)
+	for {
+		b.mu.Lock()
+		for b.pending >= logMaxPending {
+			b.notify()
+			b.cond.Wait()
+		}
+		w, ok := b.writers[key]
+		if !ok {
+			w = &sLogWriter{backend: backend}
+			b.writers[key] = w
+		}
+		b.mu.Unlock()
+
+		// 首次写入时从已有日志的最大行号继续, 查询不持有全局锁, 其他步骤不受影响
+		w.once.Do(func() {
+			w.next, w.err = backend.next(log.TaskName, log.StepName)
+		})
+
+		b.mu.Lock()
+		if w.err != nil {
+			if b.writers[key] == w {
+				delete(b.writers, key)
+			}
+			b.mu.Unlock()
+			return w.err
+		}
+		if b.writers[key] != w {
+			// 查询期间步骤日志被重置或计数被释放, 重新查询
+			b.mu.Unlock()
+			continue
+		}
+		log.Line = models.Pointer(w.next)
+		w.next++
+		w.idle = 0
+		w.lines = append(w.lines, log)
+		b.pending++
+		if len(w.lines) >= logBatchSize {
+			b.notify()
+		}
+		b.mu.Unlock()
+		return nil
+	}
+}
+
+func (b *sLogBuffer) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (b *sLogBuffer) run() {
+	ticker := time.NewTicker(logFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.wake:
+		}
+		b.flush("")
+	}
+}
+
+// flush 写入指定步骤的缓冲日志, key为空时写入所有步骤并释放空闲步骤的行号计数
+func (b *sLogBuffer) flush(key string) {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.flushLocked(key)
+}
+
+func (b *sLogBuffer) flushLocked(key string) {
+	var batches = make(map[iLogBackend]models.SStepLogs)
+	var keys = make(map[iLogBackend][]string)
+	var n int
+	b.mu.Lock()
+	for k, w := range b.writers {
+		if key != "" && k != key {
+			continue
+		}
+		if len(w.lines) == 0 {
+			if key == "" {
+				if w.idle++; w.idle >= logIdleFlushes {
+					delete(b.writers, k)
+				}
+			}
+			continue
+		}
+		batches[w.backend] = append(batches[w.backend], w.lines...)
+		keys[w.backend] = append(keys[w.backend], k)
+		n += len(w.lines)
+		w.lines = nil
+	}
+	b.mu.Unlock()
+	if n == 0 {
+		return
+	}
+
+	var failed = make(map[iLogBackend]error)
+	for backend, lines := range batches {
+		if err := backend.write(lines); err != nil {
+			logx.Warnln("flush step logs", len(lines), err)
+			failed[backend] = err
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for backend, lines := range batches {
+		if _, ok := failed[backend]; !ok {
+			b.pending -= len(lines)
+			for _, k := range keys[backend] {
+				if w, ok := b.writers[k]; ok {
+					w.retries = 0
+				}
+			}
+			continue
+		}
+		b.pending -= len(lines) - b.requeue(keys[backend], lines)
+	}
+	b.cond.Broadcast()
+}
+
+// requeue 写入失败的日志放回步骤缓冲等待重试, 期间仍计入等待写入的行数, 返回放回的行数
+func (b *sLogBuffer) requeue(keys []string, lines models.SStepLogs) (n int) {
+	var retry = make(map[string]bool, len(keys))
+	for _, k := range keys {
+		w, ok := b.writers[k]
+		if !ok {
+			// 步骤日志已被删除
+			continue
+		}
+		if w.retries++; w.retries > logMaxRetries {
+			logx.Errorln("drop step logs after retries", k, w.retries)
+			w.retries = 0
+			continue
+		}
+		retry[k] = true
+	}
+	// 放在写入期间新增的日志之前, 保证按行号顺序写入
+	var failed = make(map[string]models.SStepLogs, len(retry))
+	for _, log := range lines {
+		k := logKey(log.TaskName, log.StepName)
+		if retry[k] {
+			failed[k] = append(failed[k], log)
+			n++
+		}
+	}
+	for k, logs := range failed {
+		w := b.writers[k]
+		w.lines = append(logs, w.lines...)
+	}
+	return n
+}
+
+// locked 写入步骤缓冲的日志后执行fn, 期间不会写入新的日志
+func (b *sLogBuffer) locked(key string, fn func() error) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.flushLocked(key)
+	return fn()
+}
+
+// remove 丢弃步骤缓冲的日志并重置行号, fn在持有锁时执行, 期间不会分配新的行号
+func (b *sLogBuffer) remove(key string, fn func() error) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w, ok := b.writers[key]; ok {
+		b.pending -= len(w.lines)
+		delete(b.writers, key)
+		b.cond.Broadcast()
+	}
+	return fn()
+}
+
+// archive 写入步骤缓冲的日志后丢弃计数, fn在持有锁时执行, 之后的日志重新查询行号
+func (b *sLogBuffer) archive(key string, fn func() error) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+	b.flushLocked(key)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if w, ok := b.writers[key]; ok {
+		if len(w.lines) != 0 {
+			logx.Warnln("drop unwritten step logs before archive", key, len(w.lines))
+		}
+		b.pending -= len(w.lines)
+		delete(b.writers, key)
+		b.cond.Broadcast()
+	}
+	return fn()
+}
+
+// FlushLogs 写入所有缓冲的步骤日志, 退出前调用
+func FlushLogs() {
+	logBuffer.flush("")
+}
+
+func logKey(taskName, stepName string) string {
+	return taskName + "/" + stepName
+}
@@ -0,0 +1,513 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/multierr"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+const (
+	// 索引记录: 起始行号(8) 行数(4) 偏移(8) 长度(4)
+	logIndexSize = 24
+	logDataExt   = ".log.gz"
+	logIndexExt  = ".idx"
+	logRunPrefix = "run-"
+)
+
+// sFileLog 日志按步骤追加写入压缩文件, 每批日志为一个独立的gzip块, 可直接用zcat查看,
+// 索引文件记录每个块的起始行号及位置用于定位, 数据库仅保存元数据
+type sFileLog struct {
+	*gorm.DB
+	dir string
+}
+
+// sLogChunk 压缩块的索引
+type sLogChunk struct {
+	first  int64
+	count  uint32
+	offset int64
+	size   uint32
+}
+
+// sLogRecord 压缩块中的单行日志
+type sLogRecord struct {
+	Line      int64  `json:"line"`
+	Timestamp int64  `json:"timestamp"`
+	Content   string `json:"content"`
+}
+
+func (f *sFileLog) path(tName, sName string) string {
+	return filepath.Join(f.dir, logFileName(tName), logFileName(sName))
+}
+
+// runPath 第 run 次执行归档后的日志路径, 位于任务目录的 run-<序号> 目录下
+func (f *sFileLog) runPath(tName, sName string, run int64) string {
+	return filepath.Join(f.dir, logFileName(tName), fmt.Sprintf("%s%d", logRunPrefix, run), logFileName(sName))
+}
+
+// chunks 读取索引, 忽略未写完整的记录
+func (f *sFileLog) chunks(path string) ([]sLogChunk, error) {
+	data, err := os.ReadFile(path + logIndexExt)
+	if err != nil {
+		return nil, err
+	}
+	var res = make([]sLogChunk, 0, len(data)/logIndexSize)
+	for ; len(data) >= logIndexSize; data = data[logIndexSize:] {
+		res = append(res, sLogChunk{
+			first:  int64(binary.LittleEndian.Uint64(data[0:8])),
+			count:  binary.LittleEndian.Uint32(data[8:12]),
+			offset: int64(binary.LittleEndian.Uint64(data[12:20])),
+			size:   binary.LittleEndian.Uint32(data[20:24]),
+		})
+	}
+	return res, nil
+}
+
+func (f *sFileLog) next(tName, sName string) (int64, error) {
+	next, _, _, err := f.tail(f.path(tName, sName))
+	return next, err
+}
+
+// tail 最后一个完整写入的块之后的行号, 数据长度及块数, 并截断异常退出或写入失败时残留的未完成写入
+func (f *sFileLog) tail(path string) (next, end, chunks int64, err error) {
+	stat, err := os.Stat(path + logIndexExt)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, 0, truncate(path+logDataExt, 0)
+		}
+		return 0, 0, 0, err
+	}
+	chunks = stat.Size() / logIndexSize
+	if chunks != 0 {
+		var index *os.File
+		if index, err = os.Open(path + logIndexExt); err != nil {
+			return 0, 0, 0, err
+		}
+		var record [logIndexSize]byte
+		_, err = index.ReadAt(record[:], (chunks-1)*logIndexSize)
+		_ = index.Close()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		next = int64(binary.LittleEndian.Uint64(record[0:8])) + int64(binary.LittleEndian.Uint32(record[8:12]))
+		end = int64(binary.LittleEndian.Uint64(record[12:20])) + int64(binary.LittleEndian.Uint32(record[20:24]))
+	}
+	if err = truncate(path+logIndexExt, chunks*logIndexSize); err != nil {
+		return 0, 0, 0, err
+	}
+	if err = truncate(path+logDataExt, end); err != nil {
+		return 0, 0, 0, err
+	}
+	return next, end, chunks, nil
+}
+
+func (f *sFileLog) write(logs models.SStepLogs) (err error) {
+	var keys []string
+	var groups = make(map[string]models.SStepLogs)
+	for _, log := range logs {
+		key := logKey(log.TaskName, log.StepName)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], log)
+	}
+	for _, key := range keys {
+		err = multierr.Append(err, f.append(groups[key]))
+	}
+	return err
+}
+
+// append 将同一步骤的一批日志写入一个压缩块, 先写数据再写索引, 索引中的块均已完整写入.
+// 写入失败的日志会重试, 已写入索引的行跳过, 保证重试不会重复写入
+func (f *sFileLog) append(logs models.SStepLogs) error {
+	tName, sName := logs[0].TaskName, logs[0].StepName
+	first := *logs[0].Line
+	path := f.path(tName, sName)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	next, end, chunks, err := f.tail(path)
+	if err != nil {
+		return err
+	}
+	for len(logs) != 0 && *logs[0].Line < next {
+		logs = logs[1:]
+	}
+
+	if len(logs) != 0 {
+		var records = make([]*sLogRecord, 0, len(logs))
+		for _, log := range logs {
+			records = append(records, &sLogRecord{
+				Line:      *log.Line,
+				Timestamp: log.Timestamp,
+				Content:   log.Content,
+			})
+		}
+		var chunk []byte
+		if chunk, err = encodeChunk(records); err != nil {
+			return err
+		}
+		if err = appendFile(path+logDataExt, chunk); err != nil {
+			return err
+		}
+		var record [logIndexSize]byte
+		binary.LittleEndian.PutUint64(record[0:8], uint64(*logs[0].Line))
+		binary.LittleEndian.PutUint32(record[8:12], uint32(len(logs)))
+		binary.LittleEndian.PutUint64(record[12:20], uint64(end))
+		binary.LittleEndian.PutUint32(record[20:24], uint32(len(chunk)))
+		if err = appendFile(path+logIndexExt, record[:]); err != nil {
+			return err
+		}
+		next = *logs[len(logs)-1].Line + 1
+		end += int64(len(chunk))
+		chunks++
+	}
+
+	return f.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_name"}, {Name: "step_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"line_count", "chunks", "size", "updated_at"}),
+	}).Create(&models.SStepLogFile{
+		TaskName:  tName,
+		StepName:  sName,
+		FirstLine: first,
+		LineCount: next,
+		Chunks:    chunks,
+		Size:      end,
+	}).Error
+}
+
+// appendFile 追加写入文件
+func appendFile(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f *sFileLog) list(tName, sName string, latestLine *int64) models.SStepLogs {
+	if latestLine == nil {
+		return f.read(f.path(tName, sName), tName, sName, 0, -1, 0)
+	}
+	return f.read(f.path(tName, sName), tName, sName, *latestLine+1, -1, logListLimit)
+}
+
+func (f *sFileLog) lines(tName, sName string, start, end int64) models.SStepLogs {
+	if end < start {
+		return nil
+	}
+	return f.read(f.path(tName, sName), tName, sName, start, end, 0)
+}
+
+// read 读取 path 中行号在 [start, end] 之间的日志, end为负数时不限制, limit为0时不限制行数
+func (f *sFileLog) read(path, tName, sName string, start, end int64, limit int) (res models.SStepLogs) {
+	chunks, err := f.chunks(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logx.Warnln(tName, sName, err)
+		}
+		return nil
+	}
+	data, err := os.Open(path + logDataExt)
+	if err != nil {
+		logx.Warnln(tName, sName, err)
+		return nil
+	}
+	defer data.Close()
+	for _, chunk := range chunks {
+		if chunk.first+int64(chunk.count) <= start {
+			continue
+		}
+		if end >= 0 && chunk.first > end {
+			break
+		}
+		records, err := readChunk(data, chunk)
+		if err != nil {
+			logx.Warnln(tName, sName, err)
+			return
+		}
+		for _, record := range records {
+			if record.Line < start || (end >= 0 && record.Line > end) {
+				continue
+			}
+			res = append(res, &models.SStepLog{
+				TaskName:  tName,
+				StepName:  sName,
+				Timestamp: record.Timestamp,
+				Line:      models.Pointer(record.Line),
+				Content:   record.Content,
+			})
+			if limit > 0 && len(res) >= limit {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (f *sFileLog) remove(tName, sName string) error {
+	path := f.path(tName, sName)
+	for _, file := range []string{path + logIndexExt, path + logDataExt} {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	// 任务下没有其他步骤日志时删除目录
+	_ = os.Remove(filepath.Dir(path))
+	return f.Where(map[string]interface{}{
+		"task_name": tName,
+		"step_name": sName,
+	}).Delete(&models.SStepLogFile{}).Error
+}
+
+func (f *sFileLog) trim(tName, sName string, keep int64) (lines, size int64, err error) {
+	path := f.path(tName, sName)
+	chunks, err := f.chunks(path)
+	if err != nil || len(chunks) == 0 {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return 0, 0, err
+	}
+	last := chunks[len(chunks)-1]
+	end := last.offset + int64(last.size)
+	cut := last.first + int64(last.count) - keep
+	if cut <= chunks[0].first {
+		return 0, 0, nil
+	}
+	for _, chunk := range chunks {
+		lines += int64(chunk.count)
+	}
+	// 跳过不包含保留行的块
+	var k int
+	for k < len(chunks) && chunks[k].first+int64(chunks[k].count) <= cut {
+		k++
+	}
+
+	src, err := os.Open(path + logDataExt)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer src.Close()
+	// 跨越裁剪位置的块重新压缩, 仅保留 cut 之后的行
+	var head []byte
+	var rest = chunks[k:]
+	if len(rest) != 0 && rest[0].first < cut {
+		records, err := readChunk(src, rest[0])
+		if err != nil {
+			return 0, 0, err
+		}
+		var kept []*sLogRecord
+		for _, record := range records {
+			if record.Line >= cut {
+				kept = append(kept, record)
+			}
+		}
+		if head, err = encodeChunk(kept); err != nil {
+			return 0, 0, err
+		}
+		rest[0] = sLogChunk{
+			first:  cut,
+			count:  uint32(len(kept)),
+			offset: rest[0].offset + int64(rest[0].size) - int64(len(head)),
+			size:   uint32(len(head)),
+		}
+	}
+
+	// 写入新文件后替换, 保留的块整体前移
+	var start = end
+	if len(rest) != 0 {
+		start = rest[0].offset
+	}
+	dst, err := os.Create(path + logDataExt + ".tmp")
+	if err != nil {
+		return 0, 0, err
+	}
+	_, err = dst.Write(head)
+	if err == nil {
+		var from = start + int64(len(head))
+		_, err = io.Copy(dst, io.NewSectionReader(src, from, end-from))
+	}
+	if err = multierr.Combine(err, dst.Close()); err != nil {
+		return 0, 0, err
+	}
+	var index = make([]byte, 0, len(rest)*logIndexSize)
+	for _, chunk := range rest {
+		index = binary.LittleEndian.AppendUint64(index, uint64(chunk.first))
+		index = binary.LittleEndian.AppendUint32(index, chunk.count)
+		index = binary.LittleEndian.AppendUint64(index, uint64(chunk.offset-start))
+		index = binary.LittleEndian.AppendUint32(index, chunk.size)
+	}
+	if err = os.WriteFile(path+logIndexExt+".tmp", index, 0644); err != nil {
+		return 0, 0, err
+	}
+	if err = os.Rename(path+logDataExt+".tmp", path+logDataExt); err != nil {
+		return 0, 0, err
+	}
+	if err = os.Rename(path+logIndexExt+".tmp", path+logIndexExt); err != nil {
+		return 0, 0, err
+	}
+
+	for _, chunk := range rest {
+		lines -= int64(chunk.count)
+	}
+	size = start
+	return lines, size, f.Model(&models.SStepLogFile{}).
+		Where(map[string]interface{}{
+			"task_name": tName,
+			"step_name": sName,
+		}).
+		Updates(map[string]interface{}{
+			"first_line": cut,
+			"chunks":     len(rest),
+			"size":       end - start,
+		}).Error
+}
+
+func (f *sFileLog) oversized(tasks *gorm.DB, lines int64) (res models.SStepLogs) {
+	var rows models.SStepLogFiles
+	f.Model(&models.SStepLogFile{}).
+		Where("task_name IN (?)", tasks).
+		Where("line_count - first_line > ?", lines).
+		Find(&rows)
+	for _, row := range rows {
+		res = append(res, &models.SStepLog{
+			TaskName: row.TaskName,
+			StepName: row.StepName,
+			Line:     models.Pointer(row.LineCount - row.FirstLine),
+		})
+	}
+	return
+}
+
+// archive 将日志文件移动到归档目录, 已归档的日志不再裁剪, 不在元数据表中记录
+func (f *sFileLog) archive(tName, sName string, run int64) error {
+	path, dst := f.path(tName, sName), f.runPath(tName, sName, run)
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	for _, ext := range []string{logDataExt, logIndexExt} {
+		if err := os.Rename(path+ext, dst+ext); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return f.Where(map[string]interface{}{
+		"task_name": tName,
+		"step_name": sName,
+	}).Delete(&models.SStepLogFile{}).Error
+}
+
+func (f *sFileLog) history(tName, sName string, run, start, end int64) models.SStepLogs {
+	return f.read(f.runPath(tName, sName, run), tName, sName, start, end, 0)
+}
+
+func (f *sFileLog) removeRuns(tName string, upto int64) (size int64, err error) {
+	dir := filepath.Join(f.dir, logFileName(tName))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return 0, err
+	}
+	for _, entry := range entries {
+		name, ok := strings.CutPrefix(entry.Name(), logRunPrefix)
+		if !ok || !entry.IsDir() {
+			continue
+		}
+		run, _err := strconv.ParseInt(name, 10, 64)
+		if _err != nil || run > upto {
+			continue
+		}
+		files, _ := os.ReadDir(filepath.Join(dir, entry.Name()))
+		for _, file := range files {
+			if info, _err := file.Info(); _err == nil {
+				size += info.Size()
+			}
+		}
+		err = multierr.Append(err, os.RemoveAll(filepath.Join(dir, entry.Name())))
+	}
+	// 任务下没有其他日志时删除目录
+	_ = os.Remove(dir)
+	return size, err
+}
+
+// encodeChunk 将日志压缩为一个gzip块, 每行一条JSON记录
+func encodeChunk(records []*sLogRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func readChunk(data io.ReaderAt, chunk sLogChunk) ([]*sLogRecord, error) {
+	zr, err := gzip.NewReader(io.NewSectionReader(data, chunk.offset, int64(chunk.size)))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	var res = make([]*sLogRecord, 0, chunk.count)
+	dec := json.NewDecoder(zr)
+	for {
+		var record = new(sLogRecord)
+		if err = dec.Decode(record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, err
+		}
+		res = append(res, record)
+	}
+}
+
+// truncate 文件超出指定大小时截断
+func truncate(path string, size int64) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if stat.Size() <= size {
+		return nil
+	}
+	return os.Truncate(path, size)
+}
+
+// logFileName 名称转义为文件名, 避免出现 . 及 .. 等特殊路径
+func logFileName(name string) string {
+	name = url.PathEscape(name)
+	if strings.HasPrefix(name, ".") {
+		name = "%2E" + name[1:]
+	}
+	return name
+}
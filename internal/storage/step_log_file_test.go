@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+func testLogs(tName, sName string, start, count int64) (res models.SStepLogs) {
+	for line := start; line < start+count; line++ {
+		res = append(res, &models.SStepLog{
+			TaskName:  tName,
+			StepName:  sName,
+			Timestamp: line,
+			Line:      models.Pointer(line),
+			Content:   fmt.Sprintf("line %d", line),
+		})
+	}
+	return
+}
+
+// checkLines 日志的行号依次为 [first, first+count), 内容与行号对应
+func checkLines(t *testing.T, logs models.SStepLogs, first, count int64) {
+	t.Helper()
+	if int64(len(logs)) != count {
+		t.Fatalf("got %d lines, want %d", len(logs), count)
+	}
+	for k, log := range logs {
+		line := first + int64(k)
+		if *log.Line != line || log.Content != fmt.Sprintf("line %d", line) {
+			t.Fatalf("line %d = %d %q", k, *log.Line, log.Content)
+		}
+	}
+}
+
+func TestFileLogWriteRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		batches [][2]int64 // 每批日志的起始行号及行数
+		start   int64
+		end     int64
+		first   int64
+		count   int64
+	}{
+		{
+			name:    "single chunk",
+			batches: [][2]int64{{0, 10}},
+			start:   0,
+			end:     -1,
+			first:   0,
+			count:   10,
+		},
+		{
+			name:    "range across chunks",
+			batches: [][2]int64{{0, 10}, {10, 10}, {20, 10}},
+			start:   5,
+			end:     24,
+			first:   5,
+			count:   20,
+		},
+		{
+			name:    "retry skips written lines",
+			batches: [][2]int64{{0, 10}, {5, 10}, {0, 15}},
+			start:   0,
+			end:     -1,
+			first:   0,
+			count:   15,
+		},
+		{
+			name:    "range after end",
+			batches: [][2]int64{{0, 10}},
+			start:   20,
+			end:     -1,
+			first:   20,
+			count:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &sFileLog{DB: newTestDB(t), dir: t.TempDir()}
+			for _, batch := range tt.batches {
+				if err := f.write(testLogs("task", "step", batch[0], batch[1])); err != nil {
+					t.Fatal(err)
+				}
+			}
+			checkLines(t, f.read(f.path("task", "step"), "task", "step", tt.start, tt.end, 0), tt.first, tt.count)
+
+			var want int64
+			for _, batch := range tt.batches {
+				want = max(want, batch[0]+batch[1])
+			}
+			next, err := f.next("task", "step")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next != want {
+				t.Errorf("next = %d, want %d", next, want)
+			}
+		})
+	}
+}
+
+func TestFileLogWriteSteps(t *testing.T) {
+	f := &sFileLog{DB: newTestDB(t), dir: t.TempDir()}
+	var logs = append(testLogs("task", "a", 0, 3), testLogs("task", "b", 0, 5)...)
+	logs = append(logs, testLogs("task", "a", 3, 2)...)
+	if err := f.write(logs); err != nil {
+		t.Fatal(err)
+	}
+	checkLines(t, f.list("task", "a", nil), 0, 5)
+	checkLines(t, f.list("task", "b", nil), 0, 5)
+	latest := int64(2)
+	checkLines(t, f.list("task", "a", &latest), 3, 2)
+}
+
+func TestFileLogTrim(t *testing.T) {
+	tests := []struct {
+		name      string
+		batches   [][2]int64
+		keep      int64
+		wantLines int64
+	}{
+		{name: "keep all", batches: [][2]int64{{0, 10}}, keep: 20, wantLines: 0},
+		{name: "chunk boundary", batches: [][2]int64{{0, 10}, {10, 10}}, keep: 10, wantLines: 10},
+		{name: "inside chunk", batches: [][2]int64{{0, 10}, {10, 10}}, keep: 15, wantLines: 5},
+		{name: "last lines", batches: [][2]int64{{0, 10}, {10, 10}, {20, 10}}, keep: 3, wantLines: 27},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			f := &sFileLog{DB: db, dir: t.TempDir()}
+			var total int64
+			for _, batch := range tt.batches {
+				if err := f.write(testLogs("task", "step", batch[0], batch[1])); err != nil {
+					t.Fatal(err)
+				}
+				total = batch[0] + batch[1]
+			}
+			before, err := os.Stat(f.path("task", "step") + logDataExt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines, size, err := f.trim("task", "step", tt.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lines != tt.wantLines {
+				t.Errorf("trimmed %d lines, want %d", lines, tt.wantLines)
+			}
+			after, err := os.Stat(f.path("task", "step") + logDataExt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lines != 0 && after.Size() >= before.Size() {
+				t.Errorf("size %d -> %d, want smaller", before.Size(), after.Size())
+			}
+			if lines == 0 && size != 0 {
+				t.Errorf("released %d bytes without trimming", size)
+			}
+			checkLines(t, f.list("task", "step", nil), total-min(tt.keep, total), min(tt.keep, total))
+
+			// 裁剪后继续追加, 行号保持递增
+			if err = f.write(testLogs("task", "step", total, 2)); err != nil {
+				t.Fatal(err)
+			}
+			checkLines(t, f.lines("task", "step", total, total+1), total, 2)
+
+			var meta models.SStepLogFile
+			if err = db.Where("task_name = ? AND step_name = ?", "task", "step").First(&meta).Error; err != nil {
+				t.Fatal(err)
+			}
+			if meta.LineCount != total+2 || meta.FirstLine != total-min(tt.keep, total) {
+				t.Errorf("meta first %d count %d", meta.FirstLine, meta.LineCount)
+			}
+		})
+	}
+}
+
+func TestFileLogArchive(t *testing.T) {
+	f := &sFileLog{DB: newTestDB(t), dir: t.TempDir()}
+	if err := f.write(testLogs("task", "step", 0, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.archive("task", "step", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(f.path("task", "step") + logDataExt); !os.IsNotExist(err) {
+		t.Errorf("log file is not moved: %v", err)
+	}
+	if next, _ := f.next("task", "step"); next != 0 {
+		t.Errorf("next = %d after archive, want 0", next)
+	}
+	checkLines(t, f.history("task", "step", 1, 3, 5), 3, 3)
+
+	size, err := f.removeRuns("task", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size == 0 {
+		t.Error("removeRuns released 0 bytes")
+	}
+	checkLines(t, f.history("task", "step", 1, 0, -1), 0, 0)
+}
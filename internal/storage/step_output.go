@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sStepOutput struct {
+	*gorm.DB
+	tName string
+	sName string
+}
+
+func (o *sStepOutput) List() (res models.SEnvs) {
+	o.Model(&models.SStepOutput{}).
+		Select("name, value").
+		Where(map[string]interface{}{
+			"task_name": o.tName,
+			"step_name": o.sName,
+		}).
+		Order("id ASC").
+		Find(&res)
+	return
+}
+
+func (o *sStepOutput) Insert(outputs ...*models.SEnv) (err error) {
+	if len(outputs) == 0 {
+		return
+	}
+	var _outputs []models.SStepOutput
+	for _, output := range outputs {
+		_outputs = append(_outputs, models.SStepOutput{
+			TaskName: o.tName,
+			StepName: o.sName,
+			Name:     output.Name,
+			Value:    output.Value,
+		})
+	}
+	return o.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "task_name"},
+			{Name: "step_name"},
+			{Name: "name"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"value"}),
+	}).Create(_outputs).Error
+}
+
+func (o *sStepOutput) Update(output *models.SEnv) (err error) {
+	return o.Model(&models.SStepOutput{}).
+		Where(map[string]interface{}{
+			"task_name": o.tName,
+			"step_name": o.sName,
+			"name":      output.Name,
+		}).
+		Update("value", output.Value).Error
+}
+
+func (o *sStepOutput) Get(name string) (res string, err error) {
+	if name == "" {
+		return "", errors.New("name is empty")
+	}
+	err = o.Model(&models.SStepOutput{}).
+		Select("value").
+		Where(map[string]interface{}{
+			"task_name": o.tName,
+			"step_name": o.sName,
+			"name":      name,
+		}).
+		Scan(&res).
+		Error
+	return
+}
+
+func (o *sStepOutput) Remove(name string) (err error) {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	return o.Where(map[string]interface{}{
+		"task_name": o.tName,
+		"step_name": o.sName,
+		"name":      name,
+	}).Delete(&models.SStepOutput{}).Error
+}
+
+func (o *sStepOutput) RemoveAll() (err error) {
+	return o.Where(map[string]interface{}{
+		"task_name": o.tName,
+		"step_name": o.sName,
+	}).Delete(&models.SStepOutput{}).Error
+}
@@ -1,14 +1,21 @@
 package storage
 
 import (
+	"errors"
+	"time"
+
 	"gorm.io/gorm"
 
+	"github.com/busyster996/dagflow/internal/pubsub"
 	"github.com/busyster996/dagflow/internal/storage/models"
 	"github.com/busyster996/dagflow/pkg/logx"
 )
 
 var storage IStorage
 
+// ErrNotFound 记录不存在, 与存储引擎无关
+var ErrNotFound = errors.New("record not found")
+
 const (
 	TypeSqlite    = "sqlite"
 	TypeMysql     = "mysql"
@@ -28,10 +35,19 @@ func New(gdb *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&models.STask{},
 		&models.STaskEnv{},
+		&models.STaskDepend{},
+		&models.STaskRun{},
+		&models.STaskRunStep{},
 		&models.SStep{},
 		&models.SStepEnv{},
+		&models.SStepOutput{},
+		&models.SStepExport{},
 		&models.SStepDepend{},
 		&models.SStepLog{},
+		&models.SStepLogFile{},
+		&models.SStepApproval{},
+		&models.SStepAttempt{},
+		&models.SNode{},
 		&models.SPipeline{},
 		&models.SPipelineBuild{},
 	); err != nil {
@@ -39,6 +55,7 @@ func New(gdb *gorm.DB) error {
 		return err
 	}
 	storage = db
+	logBackend = &sDBLog{DB: gdb}
 	return nil
 }
 
@@ -46,7 +63,7 @@ func Name() string {
 	return storage.Name()
 }
 
-func FixDatabase(node string) (err error) {
+func FixDatabase(node string) (adopted []string, err error) {
 	return storage.FixDatabase(node)
 }
 
@@ -58,6 +75,14 @@ func NodeTasks(node string) []ITask {
 	return storage.NodeTasks(node)
 }
 
+func Node(name string) INode {
+	return storage.Node(name)
+}
+
+func NodeList() models.SNodes {
+	return storage.NodeList()
+}
+
 func TaskCreate(task *models.STask) (err error) {
 	return storage.TaskCreate(task)
 }
@@ -70,6 +95,54 @@ func TaskList(page, pageSize int64, str string) (res []*models.STask, total int6
 	return storage.TaskList(page, pageSize, str)
 }
 
+func GroupTasks(group string) models.STasks {
+	return storage.GroupTasks(group)
+}
+
+func GroupDequeue(group string) (*models.STask, error) {
+	return storage.GroupDequeue(group)
+}
+
+// DispatchGroup 并发组中没有执行中的任务时, 提交最早排队的任务
+func DispatchGroup(group string) {
+	task, err := GroupDequeue(group)
+	if err != nil {
+		logx.Errorln("dispatch group", group, err)
+		return
+	}
+	if task == nil {
+		return
+	}
+	node := task.Node
+	if node == "" {
+		node = "random"
+	}
+	logx.Infoln("dispatch group", group, task.Name)
+	if err = pubsub.PublishTask(node, task.Name); err != nil {
+		logx.Errorln("dispatch group", group, task.Name, err)
+	}
+}
+
+func QueuedGroups() []string {
+	return storage.QueuedGroups()
+}
+
+func DependTasks() []string {
+	return storage.DependTasks()
+}
+
+func ExpiredTasks(before time.Time, limit int) []string {
+	return storage.ExpiredTasks(before, limit)
+}
+
+func OversizedLogs(lines int64) models.SStepLogs {
+	return storage.OversizedLogs(lines)
+}
+
+func ExpiredRuns(keep int) []string {
+	return storage.ExpiredRuns(keep)
+}
+
 func Pipeline(name string) IPipeline {
 	return storage.Pipeline(name)
 }
@@ -22,20 +22,33 @@ func (t *sTask) Name() string {
 }
 
 func (t *sTask) ClearAll() error {
+	if err := t.Reset(); err != nil {
+		return err
+	}
+	if err := t.Run().RemoveAll(); err != nil {
+		return err
+	}
+	// 清理build表
+	t.Where("task_name", t.tName).Delete(&models.SPipelineBuild{})
+	return nil
+}
+
+func (t *sTask) Reset() error {
 	if err := t.Remove(); err != nil {
 		return err
 	}
 	if err := t.Env().RemoveAll(); err != nil {
 		return err
 	}
+	if err := t.Depend().RemoveAll(); err != nil {
+		return err
+	}
 	list := t.StepList(All)
 	for _, v := range list {
 		if err := t.Step(v.Name).ClearAll(); err != nil {
 			return err
 		}
 	}
-	// 清理build表
-	t.Where("task_name", t.tName).Delete(&models.SPipelineBuild{})
 	return nil
 }
 
@@ -67,6 +80,19 @@ func (t *sTask) State() (state models.State, err error) {
 	return
 }
 
+func (t *sTask) FailFast() (enable bool) {
+	if t.Model(&models.STask{}).
+		Select("fail_fast").
+		Where(map[string]interface{}{
+			"name": t.tName,
+		}).
+		Scan(&enable).
+		Error != nil {
+		return
+	}
+	return
+}
+
 func (t *sTask) IsDisable() (disable bool) {
 	if t.Model(&models.STask{}).
 		Select("disable").
@@ -90,6 +116,20 @@ func (t *sTask) Env() IEnv {
 	return t.env
 }
 
+func (t *sTask) Depend() ITaskDepend {
+	return &sTaskDepend{
+		DB:    t.DB,
+		tName: t.tName,
+	}
+}
+
+func (t *sTask) Run() IRun {
+	return &sTaskRun{
+		DB:    t.DB,
+		tName: t.tName,
+	}
+}
+
 func (t *sTask) Timeout() (res time.Duration, err error) {
 	err = t.Model(&models.STask{}).
 		Select("timeout").
@@ -124,6 +164,18 @@ func (t *sTask) UpdateNode(node string) error {
 		Error
 }
 
+func (t *sTask) ResetTime() (err error) {
+	return t.Model(&models.STask{}).
+		Where(map[string]interface{}{
+			"name": t.tName,
+		}).
+		Updates(map[string]interface{}{
+			"s_time": nil,
+			"e_time": nil,
+		}).
+		Error
+}
+
 func (t *sTask) Update(value *models.STaskUpdate) (err error) {
 	if value == nil {
 		return
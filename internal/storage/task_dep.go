@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+type sTaskDepend struct {
+	*gorm.DB
+	tName string
+}
+
+// List 获取当前任务的前置任务
+func (d *sTaskDepend) List() (res models.STaskDepends) {
+	d.Model(&models.STaskDepend{}).
+		Where(map[string]interface{}{
+			"task_name": d.tName,
+		}).
+		Order("id ASC").
+		Find(&res)
+	return
+}
+
+func (d *sTaskDepend) Insert(depends ...*models.STaskDepend) (err error) {
+	if len(depends) == 0 {
+		return
+	}
+	for _, depend := range depends {
+		depend.TaskName = d.tName
+	}
+	return d.Create(&depends).Error
+}
+
+func (d *sTaskDepend) Update(name string, state models.State, message string) (err error) {
+	return d.Model(&models.STaskDepend{}).
+		Where(map[string]interface{}{
+			"task_name": d.tName,
+			"name":      name,
+		}).
+		Updates(map[string]interface{}{
+			"state":   state,
+			"message": message,
+		}).Error
+}
+
+func (d *sTaskDepend) RemoveAll() (err error) {
+	return d.Where(map[string]interface{}{
+		"task_name": d.tName,
+	}).Delete(&models.STaskDepend{}).Error
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"math"
+
+	"gorm.io/gorm"
+
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+// sTaskRun 历史执行记录由 gorm 管理, 两种存储引擎共用
+type sTaskRun struct {
+	*gorm.DB
+	tName string
+}
+
+func (r *sTaskRun) List() (res models.STaskRuns) {
+	r.Model(&models.STaskRun{}).
+		Select("id, task_name, run, count, message, state, old_state, s_time, e_time").
+		Where(map[string]interface{}{
+			"task_name": r.tName,
+		}).
+		Order("run DESC").
+		Find(&res)
+	return
+}
+
+func (r *sTaskRun) Get(run int64) (res *models.STaskRun, err error) {
+	res = new(models.STaskRun)
+	err = r.Model(&models.STaskRun{}).
+		Where(map[string]interface{}{
+			"task_name": r.tName,
+			"run":       run,
+		}).
+		First(res).
+		Error
+	return
+}
+
+func (r *sTaskRun) Steps(run int64) (res models.STaskRunSteps) {
+	r.Model(&models.STaskRunStep{}).
+		Where(map[string]interface{}{
+			"task_name": r.tName,
+			"run":       run,
+		}).
+		Order("seq ASC").
+		Find(&res)
+	return
+}
+
+func (r *sTaskRun) Step(run int64, name string) (res *models.STaskRunStep, err error) {
+	res = new(models.STaskRunStep)
+	err = r.Model(&models.STaskRunStep{}).
+		Where(map[string]interface{}{
+			"task_name": r.tName,
+			"run":       run,
+			"step_name": name,
+		}).
+		First(res).
+		Error
+	return
+}
+
+func (r *sTaskRun) Latest() (run int64) {
+	r.Model(&models.STaskRun{}).
+		Select("COALESCE(MAX(run), 0)").
+		Where(map[string]interface{}{
+			"task_name": r.tName,
+		}).
+		Scan(&run)
+	return
+}
+
+// Insert 保存执行记录及步骤, 序号为已有记录的最大序号加一
+func (r *sTaskRun) Insert(run *models.STaskRun, steps models.STaskRunSteps) (err error) {
+	run.TaskName = r.tName
+	return r.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.STaskRun{}).
+			Select("COALESCE(MAX(run), 0) + 1").
+			Where(map[string]interface{}{
+				"task_name": r.tName,
+			}).
+			Scan(&run.Run).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+		for _, step := range steps {
+			step.TaskName = r.tName
+			step.Run = run.Run
+		}
+		return tx.CreateInBatches(steps, logBatchSize).Error
+	})
+}
+
+func (r *sTaskRun) Trim(keep int) (runs, size int64, err error) {
+	latest := r.Latest()
+	if latest <= int64(keep) {
+		return 0, 0, nil
+	}
+	return r.remove(latest - int64(keep))
+}
+
+func (r *sTaskRun) RemoveAll() (err error) {
+	_, _, err = r.remove(math.MaxInt64)
+	return
+}
+
+// remove 删除序号不大于 upto 的执行记录、步骤及日志, 返回删除的数量及释放的空间
+func (r *sTaskRun) remove(upto int64) (runs, size int64, err error) {
+	length := "LENGTH"
+	if r.Name() == TypeSqlserver {
+		length = "LEN"
+	}
+	err = r.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.STaskRunStep{}, &models.STaskRun{}} {
+			var n int64
+			query := tx.Model(model).
+				Where(map[string]interface{}{
+					"task_name": r.tName,
+				}).
+				Where("run <= ?", upto)
+			if err := query.Session(&gorm.Session{}).
+				Select("COALESCE(SUM(" + length + "(detail)), 0)").
+				Scan(&n).Error; err != nil {
+				return err
+			}
+			size += n
+			res := query.Delete(model)
+			if res.Error != nil {
+				return res.Error
+			}
+			if _, ok := model.(*models.STaskRun); ok {
+				runs = res.RowsAffected
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err := logBackend.removeRuns(r.tName, upto)
+	return runs, size + n, err
+}
@@ -0,0 +1,217 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// budget 节点级步骤并发预算, 所有任务的步骤共享
+var budget = newBudget()
+
+// sBudget 带权重的信号量, 同一互斥组同时只有一个步骤执行
+type sBudget struct {
+	mu      sync.Mutex
+	size    int64 // 0为不限制
+	used    int64
+	holders map[string]*sBudgetWaiter
+	mutexes map[string]string // 互斥组 -> 持有的步骤
+	waiters []*sBudgetWaiter
+	dirty   bool
+}
+
+type sBudgetWaiter struct {
+	models.SBudgetHolder
+	ready chan struct{}
+}
+
+func newBudget() *sBudget {
+	return &sBudget{
+		holders: make(map[string]*sBudgetWaiter),
+		mutexes: make(map[string]string),
+		dirty:   true,
+	}
+}
+
+// SetBudget 设置节点同时执行步骤的总槽位, 0为不限制
+func SetBudget(n int) {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	budget.size = int64(max(n, 0))
+	budget.dirty = true
+	budget.grant()
+}
+
+// acquire 占用weight个槽位及互斥组, 阻塞到满足或ctx结束, 返回释放函数
+func (b *sBudget) acquire(ctx context.Context, taskName, stepName string, weight int64, mutex string, onWait func()) (func(), error) {
+	w := &sBudgetWaiter{
+		SBudgetHolder: models.SBudgetHolder{
+			Task:   taskName,
+			Step:   stepName,
+			Weight: max(weight, 1),
+			Mutex:  mutex,
+			Since:  time.Now(),
+		},
+		ready: make(chan struct{}),
+	}
+	b.mu.Lock()
+	b.waiters = append(b.waiters, w)
+	b.dirty = true
+	b.grant()
+	b.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	default:
+		if onWait != nil {
+			onWait()
+		}
+		select {
+		case <-w.ready:
+		case <-ctx.Done():
+			b.mu.Lock()
+			select {
+			case <-w.ready:
+				// 取消的同时已获得预算, 直接释放
+				b.release(w)
+			default:
+				b.remove(w)
+			}
+			b.mu.Unlock()
+			return nil, context.Cause(ctx)
+		}
+	}
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.release(w)
+	}, nil
+}
+
+// grant 按等待顺序分配预算, 互斥组被占用的步骤不阻塞后面的步骤, 槽位不足时后面的步骤继续等待, 避免大权重步骤饿死
+func (b *sBudget) grant() {
+	var waiters []*sBudgetWaiter
+	var blocked bool
+	for _, w := range b.waiters {
+		if blocked || (w.Mutex != "" && b.mutexes[w.Mutex] != "") {
+			waiters = append(waiters, w)
+			continue
+		}
+		// 超过总槽位的步骤按总槽位计算, 否则永远无法执行
+		weight := w.Weight
+		if b.size > 0 && weight > b.size {
+			weight = b.size
+		}
+		if b.size > 0 && b.used+weight > b.size {
+			blocked = true
+			waiters = append(waiters, w)
+			continue
+		}
+		w.Weight = weight
+		w.Since = time.Now()
+		b.used += weight
+		if w.Mutex != "" {
+			b.mutexes[w.Mutex] = w.Task + "/" + w.Step
+		}
+		b.holders[w.Task+"/"+w.Step] = w
+		b.dirty = true
+		close(w.ready)
+	}
+	b.waiters = waiters
+}
+
+func (b *sBudget) release(w *sBudgetWaiter) {
+	b.used -= w.Weight
+	if w.Mutex != "" {
+		delete(b.mutexes, w.Mutex)
+	}
+	delete(b.holders, w.Task+"/"+w.Step)
+	b.dirty = true
+	b.grant()
+}
+
+func (b *sBudget) remove(w *sBudgetWaiter) {
+	for k, v := range b.waiters {
+		if v == w {
+			b.waiters = append(b.waiters[:k], b.waiters[k+1:]...)
+			break
+		}
+	}
+	b.dirty = true
+	b.grant()
+}
+
+// snapshot 当前预算的快照, 没有变化时返回false
+func (b *sBudget) snapshot() (models.SBudget, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.dirty {
+		return models.SBudget{}, false
+	}
+	b.dirty = false
+	res := models.SBudget{
+		Size: b.size,
+		Used: b.used,
+	}
+	for _, w := range b.holders {
+		holder := w.SBudgetHolder
+		res.Holders = append(res.Holders, &holder)
+	}
+	slices.SortFunc(res.Holders, func(a, b *models.SBudgetHolder) int {
+		return a.Since.Compare(b.Since)
+	})
+	for _, w := range b.waiters {
+		waiter := w.SBudgetHolder
+		res.Waiters = append(res.Waiters, &waiter)
+	}
+	return res, true
+}
+
+// report 定期将预算变化保存到节点信息中, 供接口查询
+func (b *sBudget) report(ctx context.Context, node string) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if res, ok := b.snapshot(); ok {
+			if err := storage.Node(node).UpdateBudget(res); err != nil {
+				logx.Warnln("update budget", node, err)
+				b.mu.Lock()
+				b.dirty = true
+				b.mu.Unlock()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// acquireBudget 占用步骤所需的节点预算, 等待期间步骤保持待执行
+func (s *sStep) acquireBudget(ctx context.Context) (func(), error) {
+	weight, err := s.stg.Weight()
+	if err != nil {
+		return nil, err
+	}
+	mutex, err := s.stg.Mutex()
+	if err != nil {
+		return nil, err
+	}
+	_ctx, cancel := utility.MergerContext(ctx, s.lifecycle())
+	defer cancel()
+	return budget.acquire(_ctx, s.taskName, s.stepName, weight, mutex, func() {
+		logx.Infoln(s.taskName, s.stepName, "waiting for node budget")
+		if _err := s.stg.Update(&models.SStepUpdate{
+			Message: "the step is waiting for node budget",
+		}); _err != nil {
+			logx.Warnln(s.taskName, s.stepName, _err)
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// ShouldRun 根据上游步骤的执行结果评估执行条件
+func (s *sStep) ShouldRun(ctx context.Context, upstream map[string]*dagcuter.Result) (run bool, reason string, err error) {
+	defer func() {
+		if err != nil {
+			logx.Errorln(s.taskName, s.stepName, err)
+			s.stg.Log().Write(err.Error())
+			_ = s.stg.Update(&models.SStepUpdate{
+				State:    models.Pointer(models.StateFailed),
+				OldState: models.Pointer(models.StatePending),
+				Code:     models.Pointer(common.ExecCodeSystemErr),
+				Message:  err.Error(),
+				STime:    models.Pointer(time.Now()),
+				ETime:    models.Pointer(time.Now()),
+			})
+		}
+	}()
+	// 人工跳过的步骤不再评估条件, 由Execute标记为跳过
+	if s.skipReason() != "" {
+		return true, "", nil
+	}
+	when, err := s.stg.When()
+	if err != nil {
+		return false, "", err
+	}
+	// 任务被取消或快速失败后仅执行 always 的步骤
+	if ctx.Err() != nil && common.WhenConvert(when) != common.WhenAlways {
+		return false, fmt.Sprintf("skipped because execution was cancelled: %v", context.Cause(ctx)), nil
+	}
+
+	var failed, unsatisfied []string
+	var steps = make(map[string]any, len(upstream))
+	for name, res := range upstream {
+		if !res.Satisfied() {
+			unsatisfied = append(unsatisfied, name)
+		}
+		if res.Status == dagcuter.StatusFailed && !res.Tolerated {
+			failed = append(failed, name)
+		}
+		steps[name] = map[string]any{
+			"state":   models.StateMap[resultState(res.Status)],
+			"outputs": res.Output,
+		}
+	}
+	slices.Sort(failed)
+	slices.Sort(unsatisfied)
+
+	switch when = common.WhenConvert(when); when {
+	case common.WhenSuccess:
+		if len(unsatisfied) == 0 {
+			return true, "", nil
+		}
+		return false, fmt.Sprintf("skipped because upstream steps did not succeed: %s", strings.Join(unsatisfied, ", ")), nil
+	case common.WhenFailure:
+		if len(failed) != 0 {
+			return true, "", nil
+		}
+		return false, "skipped because no upstream step failed", nil
+	case common.WhenAlways:
+		return true, "", nil
+	}
+
+	program, err := expr.Compile(when, s.exprBuiltins()...)
+	if err != nil {
+		return false, "", fmt.Errorf("invalid when expression: %w", err)
+	}
+	result, err := expr.Run(program, map[string]any{
+		"steps":   steps,
+		"success": len(unsatisfied) == 0,
+		"failure": len(failed) != 0,
+	})
+	if err != nil {
+		return false, "", fmt.Errorf("evaluate when expression: %w", err)
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return false, "", fmt.Errorf("when expression result is not a boolean")
+	}
+	if !matched {
+		return false, fmt.Sprintf("skipped because condition is not met: %s", when), nil
+	}
+	return true, "", nil
+}
+
+// Skip 条件不满足或任务被取消时跳过步骤
+func (s *sStep) Skip(ctx context.Context, reason string) {
+	logx.Infoln(s.taskName, s.stepName, s.workspace, "Skip", reason)
+	if err := s.stg.Update(&models.SStepUpdate{
+		State:    models.Pointer(models.StateSkipped),
+		OldState: models.Pointer(models.StatePending),
+		Code:     models.Pointer(common.ExecCodeSkipped),
+		Message:  reason,
+		STime:    models.Pointer(time.Now()),
+		ETime:    models.Pointer(time.Now()),
+	}); err != nil {
+		logx.Errorln(s.taskName, s.stepName, err)
+	}
+	stepManager.Delete(s.Name())
+}
+
+func resultState(status dagcuter.Status) models.State {
+	switch status {
+	case dagcuter.StatusSucceeded:
+		return models.StateStopped
+	case dagcuter.StatusFailed:
+		return models.StateFailed
+	case dagcuter.StatusSkipped:
+		return models.StateSkipped
+	default:
+		return models.StatePending
+	}
+}
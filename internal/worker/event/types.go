@@ -0,0 +1,62 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type 事件类型
+type Type string
+
+const (
+	// TypeReady 步骤依赖已完成, 开始评估执行条件
+	TypeReady Type = "ready"
+	// TypeStart 步骤开始第Attempt次执行
+	TypeStart Type = "start"
+	// TypeRetry 步骤执行失败, 等待Delay后重试
+	TypeRetry Type = "retry"
+	// TypeSkip 步骤被跳过
+	TypeSkip Type = "skip"
+	// TypeSuccess 步骤执行成功
+	TypeSuccess Type = "success"
+	// TypeFailure 步骤执行失败
+	TypeFailure Type = "failure"
+	// TypeComplete 任务下所有步骤执行结束
+	TypeComplete Type = "complete"
+	// TypeStop 任务或步骤被终止
+	TypeStop Type = "stop"
+	// TypeRerun 人工重新执行失败的步骤
+	TypeRerun Type = "rerun"
+	// TypeWaiting 步骤等待审批
+	TypeWaiting Type = "waiting"
+	// TypeApproved 步骤审批通过
+	TypeApproved Type = "approved"
+	// TypeRejected 步骤审批被拒绝
+	TypeRejected Type = "rejected"
+)
+
+// SEvent 结构化事件, 以JSON发布到事件流
+type SEvent struct {
+	Type     Type          `json:"type"`
+	Task     string        `json:"task"`
+	Step     string        `json:"step,omitempty"`
+	Attempt  int           `json:"attempt,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Delay    time.Duration `json:"delay,omitempty"`
+	Message  string        `json:"message,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Time     time.Time     `json:"time"`
+}
+
+func (e *SEvent) String() string {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+// Emit 发送结构化事件
+func Emit(e *SEvent) {
+	Send(e.String())
+}
@@ -0,0 +1,305 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gorm.io/datatypes"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/internal/utility"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 单个foreach步骤最多生成的子步骤数
+const maxForeachItems = 1024
+
+// 子步骤名称只允许字母,数字及-_.~
+var foreachNameReg = regexp.MustCompile("[^a-zA-Z\\p{Han}0-9\\-_.~]")
+
+// executeForeach 按上游输出的列表生成子步骤并执行, 所有子步骤成功或允许失败时父步骤成功
+func (s *sStep) executeForeach(ctx context.Context, input map[string]any, foreach models.SForeach, res *models.SStepUpdate) (map[string]any, error) {
+	var children []*sStep
+	var restored []string
+	items, err := foreachItems(input, foreach.From)
+	if err == nil {
+		s.stg.Log().Writef("foreach %s: %d items", foreach.From, len(items))
+		children, restored, err = s.generateForeach(items)
+	}
+	if err != nil {
+		logx.Errorln(s.taskName, s.stepName, err)
+		s.stg.Log().Write(err.Error())
+		res.State = models.Pointer(models.StateFailed)
+		res.Code = models.Pointer(common.ExecCodeSystemErr)
+		res.Message = err.Error()
+		return nil, err
+	}
+	s.setChildren(children)
+	defer s.setChildren(nil)
+
+	res.State = models.Pointer(models.StateStopped)
+	res.Code = models.Pointer(common.ExecCodeSuccess)
+	if len(children) == 0 {
+		res.Message = "there are no items to foreach"
+		return s.outputs(), nil
+	}
+
+	var limiter chan struct{}
+	if foreach.MaxParallel > 0 {
+		limiter = make(chan struct{}, foreach.MaxParallel)
+	}
+	var tasks = make(map[string]dagcuter.Task, len(children))
+	for _, child := range children {
+		child.limiter = limiter
+		child.inputs = input
+		tasks[child.stepName] = &sDetachedStep{sStep: child}
+	}
+	_dag, err := dagcuter.New(tasks)
+	if err == nil {
+		_dag.SetObserver(&sDetachedObserver{sObserver{taskName: s.taskName}})
+		for _, name := range restored {
+			var result *dagcuter.Result
+			if result, err = tasks[name].(*sDetachedStep).restoredResult(); err != nil {
+				break
+			}
+			if err = _dag.Restore(name, result); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		logx.Errorln(s.taskName, s.stepName, err)
+		res.State = models.Pointer(models.StateFailed)
+		res.Code = models.Pointer(common.ExecCodeSystemErr)
+		res.Message = err.Error()
+		return nil, err
+	}
+
+	_ctx, cancel := utility.MergerContext(ctx, s.lifecycle())
+	defer cancel()
+	if _, err = _dag.Execute(_ctx); err != nil {
+		res.State = models.Pointer(models.StateFailed)
+		res.Code = models.Pointer(common.ExecCodeFailed)
+		res.Message = err.Error()
+		return nil, err
+	}
+	var tolerated []string
+	results := _dag.Results()
+	for _, child := range children {
+		if result, ok := results[child.stepName]; ok && result.Status == dagcuter.StatusFailed {
+			tolerated = append(tolerated, child.stepName)
+		}
+	}
+	res.Message = fmt.Sprintf("all %d foreach steps succeeded", len(children))
+	if len(tolerated) != 0 {
+		res.Message = fmt.Sprintf("foreach steps finished, allowed failures: %s", strings.Join(tolerated, ", "))
+	}
+	return s.outputs(), nil
+}
+
+// generateForeach 为每个元素生成一个子步骤, 已成功的同名子步骤保留其结果, 不再需要的子步骤被删除
+func (s *sStep) generateForeach(items []string) (children []*sStep, restored []string, err error) {
+	if len(items) > maxForeachItems {
+		return nil, nil, fmt.Errorf("foreach generates more than %d steps", maxForeachItems)
+	}
+	parent, err := s.stg.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	db := storage.Task(s.taskName)
+	var existing = make(map[string]*models.SStep)
+	var taken = make(map[string]bool)
+	for _, step := range db.StepList(storage.All) {
+		if step.Foreach.Data().Parent == s.stepName {
+			existing[step.Name] = step
+			continue
+		}
+		taken[step.Name] = true
+	}
+	envs := s.stg.Env().List()
+
+	for k, item := range items {
+		name := foreachChildName(s.stepName, item, k)
+		if taken[name] {
+			name = foreachChildName(s.stepName, "", k)
+		}
+		if taken[name] {
+			return nil, nil, fmt.Errorf("step %s already exists", name)
+		}
+		taken[name] = true
+
+		if step, ok := existing[name]; ok {
+			delete(existing, name)
+			if step.Foreach.Data().Item == item {
+				if *step.State == models.StateStopped {
+					restored = append(restored, name)
+				} else if err = s.resetForeachChild(name); err != nil {
+					return nil, nil, err
+				}
+				children = append(children, s.task.newStep(name))
+				continue
+			}
+			if err = db.Step(name).ClearAll(); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err = s.createForeachChild(parent, envs, name, k, item); err != nil {
+			_ = db.Step(name).ClearAll()
+			return nil, nil, err
+		}
+		children = append(children, s.task.newStep(name))
+	}
+	for name := range existing {
+		if err = db.Step(name).ClearAll(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var names []string
+	for _, child := range children {
+		names = append(names, child.stepName)
+	}
+	logx.Infoln(s.taskName, s.stepName, "foreach generated steps", names)
+	if len(names) != 0 {
+		s.stg.Log().Writef("generated steps: %s", strings.Join(names, ", "))
+	}
+	return children, restored, nil
+}
+
+// createForeachChild 以父步骤为模板创建子步骤, 附加 FOREACH_ITEM 及 FOREACH_INDEX 环境变量
+func (s *sStep) createForeachChild(parent *models.SStep, envs models.SEnvs, name string, index int, item string) error {
+	db := storage.Task(s.taskName)
+	err := db.StepCreate(&models.SStep{
+		Name:         name,
+		Desc:         parent.Desc,
+		Type:         parent.Type,
+		Content:      parent.Content,
+		Action:       parent.Action,
+		Rule:         parent.Rule,
+		RetryPolicy:  parent.RetryPolicy,
+		SeqNo:        parent.SeqNo,
+		Priority:     parent.Priority,
+		Weight:       parent.Weight,
+		Mutex:        parent.Mutex,
+		Timeout:      parent.Timeout,
+		Disable:      models.Pointer(false),
+		AllowFailure: parent.AllowFailure,
+		Idempotent:   parent.Idempotent,
+		Metadata:     parent.Metadata,
+		Foreach: datatypes.NewJSONType(models.SForeach{
+			Parent: s.stepName,
+			Index:  index,
+			Item:   item,
+		}),
+		SStepUpdate: models.SStepUpdate{
+			Message:  "the step is waiting to be scheduled for execution",
+			Code:     models.Pointer(common.ExecCode(0)),
+			State:    models.Pointer(models.StatePending),
+			OldState: models.Pointer(models.StatePending),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	var childEnvs = make(models.SEnvs, 0, len(envs)+2)
+	for _, env := range envs {
+		childEnvs = append(childEnvs, &models.SEnv{
+			Name:  env.Name,
+			Value: env.Value,
+		})
+	}
+	childEnvs = append(childEnvs,
+		&models.SEnv{Name: "FOREACH_ITEM", Value: item},
+		&models.SEnv{Name: "FOREACH_INDEX", Value: strconv.Itoa(index)},
+	)
+	if err = db.Step(name).Env().Insert(childEnvs...); err != nil {
+		return err
+	}
+	return db.Step(name).Depend().Insert(s.stepName)
+}
+
+// resetForeachChild 未成功的子步骤重新执行
+func (s *sStep) resetForeachChild(name string) error {
+	stg := storage.Task(s.taskName).Step(name)
+	if err := stg.Output().RemoveAll(); err != nil {
+		return err
+	}
+	if err := stg.Export().RemoveAll(); err != nil {
+		return err
+	}
+	return stg.Update(&models.SStepUpdate{
+		Message:  "the step is waiting to be scheduled for execution",
+		Code:     models.Pointer(common.ExecCode(0)),
+		State:    models.Pointer(models.StatePending),
+		OldState: models.Pointer(models.StatePending),
+	})
+}
+
+func (s *sStep) setChildren(children []*sStep) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.children = children
+}
+
+// foreachChildren 正在执行的子步骤
+func (s *sStep) foreachChildren() []*sStep {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.children
+}
+
+// foreachItems 从上游步骤的输出中读取列表, 支持JSON数组或以逗号,换行分隔的文本
+func foreachItems(input map[string]any, from string) ([]string, error) {
+	k := strings.LastIndex(from, ".")
+	if k <= 0 || k == len(from)-1 {
+		return nil, fmt.Errorf("invalid foreach %q, must be <step>.<output>", from)
+	}
+	stepName, name := from[:k], from[k+1:]
+	outputs, _ := input[stepName].(map[string]any)
+	value, ok := outputs[name]
+	if !ok {
+		return nil, fmt.Errorf("output %s of step %s not found", name, stepName)
+	}
+	text := strings.TrimSpace(fmt.Sprint(value))
+	var items []string
+	if strings.HasPrefix(text, "[") {
+		var values []any
+		if err := json.Unmarshal([]byte(text), &values); err != nil {
+			return nil, errors.Wrapf(err, "output %s of step %s is not a valid JSON array", name, stepName)
+		}
+		for _, v := range values {
+			if str, ok := v.(string); ok {
+				items = append(items, str)
+				continue
+			}
+			data, _ := json.Marshal(v)
+			items = append(items, string(data))
+		}
+		return items, nil
+	}
+	for _, item := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// foreachChildName 子步骤名称由父步骤名称及元素组成, 元素不可用时使用序号
+func foreachChildName(parent, item string, index int) string {
+	item = foreachNameReg.ReplaceAllString(item, "")
+	if item == "" {
+		return fmt.Sprintf("%s-%d", parent, index)
+	}
+	return parent + "-" + item
+}
@@ -0,0 +1,18 @@
+package worker
+
+import (
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// releaseGroup 任务结束后调度同一并发组中排队的下一个任务
+func (t *sTask) releaseGroup() {
+	task, err := t.stg.Get()
+	if err != nil {
+		logx.Warnln(t.taskName, err)
+		return
+	}
+	if task.ConcurrencyGroup != "" {
+		storage.DispatchGroup(task.ConcurrencyGroup)
+	}
+}
@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// runHandlers 主流程结束后按顺序执行任务处理步骤, 不受任务终止或超时影响, 每个步骤使用自身的超时时间
+func (t *sTask) runHandlers(cause error) (err error) {
+	var handlers, steps models.SSteps
+	for _, step := range t.stg.StepList("") {
+		if step.Handler != "" {
+			handlers = append(handlers, step)
+			continue
+		}
+		if step.Foreach.Data().Parent == "" {
+			steps = append(steps, step)
+		}
+	}
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	// 主流程的最终状态及各步骤的状态和输出
+	var state = models.StateStopped
+	if cause != nil {
+		state = models.StateFailed
+	}
+	var envs = models.SEnvs{
+		{Name: "TASK_STATE", Value: models.StateMap[state]},
+	}
+	if cause != nil {
+		envs = append(envs, &models.SEnv{Name: "TASK_ERROR", Value: cause.Error()})
+	}
+	var input = make(map[string]any, len(steps))
+	for _, step := range steps {
+		envs = append(envs, &models.SEnv{
+			Name:  stateEnvName(step.Name),
+			Value: models.StateMap[*step.State],
+		})
+		var outputs = make(map[string]any)
+		for _, output := range t.stg.Step(step.Name).Output().List() {
+			outputs[output.Name] = output.Value
+		}
+		input[step.Name] = outputs
+	}
+
+	for _, handler := range handlers {
+		switch handler.Handler {
+		case common.HandlerSuccess, common.HandlerFailure:
+			if (handler.Handler == common.HandlerSuccess) != (cause == nil) {
+				if _err := t.stg.Step(handler.Name).Update(&models.SStepUpdate{
+					State:    models.Pointer(models.StateSkipped),
+					Code:     models.Pointer(common.ExecCodeSkipped),
+					Message:  fmt.Sprintf("the task is %s", models.StateMap[state]),
+					STime:    models.Pointer(time.Now()),
+					ETime:    models.Pointer(time.Now()),
+					OldState: handler.State,
+				}); _err != nil {
+					logx.Warnln(t.taskName, handler.Name, _err)
+				}
+				continue
+			}
+		}
+		if _err := t.runHandler(handler, input, envs); _err != nil {
+			err = multierr.Append(err, _err)
+		}
+	}
+	return err
+}
+
+// runHandler 执行单个处理步骤, 上次执行的结果会被清理
+func (t *sTask) runHandler(handler *models.SStep, input map[string]any, envs models.SEnvs) error {
+	logx.Infoln(t.taskName, handler.Name, "run handler", handler.Handler)
+	stg := t.stg.Step(handler.Name)
+	_ = stg.Log().RemoveAll()
+	_ = stg.Output().RemoveAll()
+	_ = stg.Attempt().RemoveAll()
+	if err := stg.Update(&models.SStepUpdate{
+		State:    models.Pointer(models.StatePending),
+		OldState: handler.State,
+		Code:     models.Pointer(common.ExecCode(0)),
+		Message:  "the step is waiting to be scheduled for execution",
+	}); err != nil {
+		return err
+	}
+
+	step := t.newStep(handler.Name)
+	step.inputs = input
+	step.envs = envs
+	_dag, err := dagcuter.New(map[string]dagcuter.Task{
+		handler.Name: &sDetachedStep{sStep: step},
+	})
+	if err != nil {
+		return err
+	}
+	_dag.SetObserver(&sDetachedObserver{sObserver{taskName: t.taskName}})
+	if _, err = _dag.Execute(context.Background()); err != nil {
+		logx.Errorln(t.taskName, handler.Name, err)
+		return fmt.Errorf("%s step %s failed", handler.Handler, handler.Name)
+	}
+	return nil
+}
+
+// stateEnvName 主流程步骤状态对应的环境变量名称
+func stateEnvName(step string) string {
+	return strings.ToUpper(envNameReg.ReplaceAllString(fmt.Sprintf("STEP_%s_STATE", step), "_"))
+}
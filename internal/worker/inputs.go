@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+)
+
+var (
+	// 引用上游步骤输出, 例如: ${{ steps.build.outputs.version }}
+	outputRefReg = regexp.MustCompile(`\$\{\{\s*steps\.(.+?)\.outputs\.([^\s}]+)\s*}}`)
+	// 环境变量名称只允许字母,数字及下划线
+	envNameReg = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// sInputStep 将上游步骤的输出注入到runner可见的内容模板及环境变量中
+type sInputStep struct {
+	storage.IStep
+	outputs map[string]map[string]any
+	envs    models.SEnvs // 附加的环境变量
+}
+
+func newInputStep(stg storage.IStep, input map[string]any, envs ...*models.SEnv) *sInputStep {
+	s := &sInputStep{
+		IStep:   stg,
+		outputs: make(map[string]map[string]any),
+		envs:    envs,
+	}
+	for name, value := range input {
+		if output, ok := value.(map[string]any); ok {
+			s.outputs[name] = output
+		}
+	}
+	return s
+}
+
+// Content 渲染内容中对上游步骤输出的引用
+func (s *sInputStep) Content() (string, error) {
+	content, err := s.IStep.Content()
+	if err != nil {
+		return "", err
+	}
+	return outputRefReg.ReplaceAllStringFunc(content, func(ref string) string {
+		match := outputRefReg.FindStringSubmatch(ref)
+		value, ok := s.outputs[match[1]][match[2]]
+		if !ok {
+			s.Log().Writef("output %s of step %s not found", match[2], match[1])
+			return ""
+		}
+		return fmt.Sprint(value)
+	}), nil
+}
+
+// Env 步骤环境变量, 附加上游步骤输出 OUTPUT_<STEP>_<NAME>
+func (s *sInputStep) Env() storage.IEnv {
+	var envs = slices.Clone(s.envs)
+	steps := make([]string, 0, len(s.outputs))
+	for name := range s.outputs {
+		steps = append(steps, name)
+	}
+	slices.Sort(steps)
+	for _, step := range steps {
+		names := make([]string, 0, len(s.outputs[step]))
+		for name := range s.outputs[step] {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			envs = append(envs, &models.SEnv{
+				Name:  outputEnvName(step, name),
+				Value: fmt.Sprint(s.outputs[step][name]),
+			})
+		}
+	}
+	return &sInputEnv{
+		IEnv:    s.IStep.Env(),
+		outputs: envs,
+	}
+}
+
+type sInputEnv struct {
+	storage.IEnv
+	outputs models.SEnvs
+}
+
+// List 上游输出在前, 同名时以步骤自身环境变量为准
+func (e *sInputEnv) List() models.SEnvs {
+	return append(slices.Clone(e.outputs), e.IEnv.List()...)
+}
+
+// outputEnvName 上游步骤输出对应的环境变量名称
+func outputEnvName(step, name string) string {
+	return strings.ToUpper(envNameReg.ReplaceAllString(fmt.Sprintf("OUTPUT_%s_%s", step, name), "_"))
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/busyster996/dagflow/internal/janitor"
+	"github.com/busyster996/dagflow/internal/storage"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 残留目录超过该时间未修改才会被删除, 避免删除刚创建的目录
+const orphanAge = time.Hour
+
+// 保留目录的任务状态, 等待执行的任务可能已上传文件到工作目录
+var keepDirStates = []models.State{models.StatePending, models.StateQueued, models.StateRunning, models.StatePaused, models.StateWaiting}
+
+// cleanDirs 删除节点上不属于执行中任务的脚本目录及工作目录, 任务已删除或已结束且目录长时间未修改
+func cleanDirs(ctx context.Context) (res models.SReclaim, err error) {
+	before := time.Now().Add(-orphanAge)
+	for _, root := range []string{viper.GetString("script_dir"), viper.GetString("workspace_dir")} {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			logx.Warnln("janitor", root, err)
+			continue
+		}
+		for _, entry := range entries {
+			if ctx.Err() != nil {
+				return res, nil
+			}
+			// 跳过上传临时目录等隐藏目录
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") {
+				continue
+			}
+			if _, ok := taskManager.Load(name); ok {
+				continue
+			}
+			path := filepath.Join(root, name)
+			if !janitor.Expired(path, before) {
+				continue
+			}
+			if state, err := storage.Task(name).State(); err == nil && slices.Contains(keepDirStates, state) {
+				continue
+			}
+			size, err := janitor.RemoveAll(path)
+			if err != nil {
+				logx.Warnln("janitor", path, err)
+				continue
+			}
+			logx.Infoln("janitor removed orphaned dir", path)
+			res.Dirs++
+			res.Bytes += size
+		}
+	}
+	return res, nil
+}
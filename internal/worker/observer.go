@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"github.com/busyster996/dagflow/internal/worker/event"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
+)
+
+// sObserver 将步骤的生命周期转换为结构化事件
+type sObserver struct {
+	taskName string
+}
+
+func (o *sObserver) OnReady(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeReady, e)
+}
+
+func (o *sObserver) OnStart(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeStart, e)
+}
+
+func (o *sObserver) OnRetry(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeRetry, e)
+}
+
+func (o *sObserver) OnSkip(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeSkip, e)
+}
+
+func (o *sObserver) OnSuccess(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeSuccess, e)
+}
+
+func (o *sObserver) OnFailure(e *dagcuter.TaskEvent) {
+	o.emit(event.TypeFailure, e)
+}
+
+func (o *sObserver) OnComplete(e *dagcuter.ExecutionEvent) {
+	res := &event.SEvent{
+		Type:     event.TypeComplete,
+		Task:     o.taskName,
+		Duration: e.Duration,
+		Time:     e.Time,
+	}
+	if e.Err != nil {
+		res.Error = e.Err.Error()
+	}
+	event.Emit(res)
+}
+
+// sDetachedObserver 独立调度的步骤的生命周期事件, 任务结束由主流程报告
+type sDetachedObserver struct {
+	sObserver
+}
+
+func (o *sDetachedObserver) OnComplete(*dagcuter.ExecutionEvent) {}
+
+func (o *sObserver) emit(typ event.Type, e *dagcuter.TaskEvent) {
+	res := &event.SEvent{
+		Type:     typ,
+		Task:     o.taskName,
+		Step:     e.Task,
+		Attempt:  e.Attempt,
+		Duration: e.Duration,
+		Delay:    e.Delay,
+		Message:  e.Reason,
+		Time:     e.Time,
+	}
+	if e.Err != nil {
+		res.Error = e.Err.Error()
+	}
+	event.Emit(res)
+}
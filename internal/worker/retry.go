@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+
+	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/storage/models"
+	"github.com/busyster996/dagflow/pkg/logx"
+)
+
+// 重试条件默认匹配的日志行数
+const defaultRetryLogLines = 20
+
+// retryIf 根据执行记录判断失败后是否继续重试
+func (s *sStep) retryIf(policy models.SRetryPolicy) func(n int, err error) bool {
+	return func(n int, err error) bool {
+		// 人工终止的步骤不再重试
+		if s.lifecycle().Err() != nil {
+			return false
+		}
+		attempt, _err := s.stg.Attempt().Get(max(n, 1))
+		if _err != nil {
+			logx.Warnln(s.taskName, s.stepName, "attempt not found", n, _err)
+			attempt = &models.SStepAttempt{Attempt: n, Message: err.Error()}
+		}
+		retry, reason := s.matchRetry(policy, attempt, err)
+		if !retry {
+			logx.Infoln(s.taskName, s.stepName, "not retryable", reason)
+			s.stg.Log().Writef("attempt %d is not retried: %s", n, reason)
+			return false
+		}
+		if _err = s.stg.Update(&models.SStepUpdate{
+			Message: fmt.Sprintf("attempt %d failed, waiting to retry: %s", n, attempt.Message),
+		}); _err != nil {
+			logx.Errorln(s.taskName, s.stepName, _err)
+		}
+		return true
+	}
+}
+
+// matchRetry retryOn中任一条件满足且retryIf表达式为真时重试, 未配置条件时重试所有错误
+func (s *sStep) matchRetry(policy models.SRetryPolicy, attempt *models.SStepAttempt, err error) (bool, string) {
+	var code common.ExecCode
+	if attempt.Code != nil {
+		code = *attempt.Code
+	}
+	var lines *string
+	var logs = func() string {
+		if lines == nil {
+			lines = models.Pointer(s.attemptLogs(attempt, policy.RetryOn))
+		}
+		return *lines
+	}
+
+	if on := policy.RetryOn; on != nil && (len(on.ExitCodes) > 0 || on.Timeout || on.Pattern != "") {
+		matched := slices.Contains(on.ExitCodes, code.Int64()) || (on.Timeout && code == common.ExecCodeTimeout)
+		if !matched && on.Pattern != "" {
+			re, _err := regexp.Compile(on.Pattern)
+			if _err != nil {
+				return false, fmt.Sprintf("invalid retryOn pattern: %v", _err)
+			}
+			matched = re.MatchString(err.Error()) || re.MatchString(logs())
+		}
+		if !matched {
+			return false, fmt.Sprintf("exit code %d does not match retryOn", code)
+		}
+	}
+
+	if policy.RetryIf == "" {
+		return true, ""
+	}
+	program, _err := expr.Compile(policy.RetryIf, s.exprBuiltins()...)
+	if _err != nil {
+		return false, fmt.Sprintf("invalid retryIf expression: %v", _err)
+	}
+	result, _err := expr.Run(program, map[string]any{
+		"attempt": attempt.Attempt,
+		"code":    code.Int64(),
+		"timeout": code == common.ExecCodeTimeout,
+		"message": attempt.Message,
+		"error":   err.Error(),
+		"logs":    logs(),
+	})
+	if _err != nil {
+		return false, fmt.Sprintf("evaluate retryIf expression: %v", _err)
+	}
+	if matched, ok := result.(bool); !ok || !matched {
+		return false, fmt.Sprintf("retryIf is not met: %s", policy.RetryIf)
+	}
+	return true, ""
+}
+
+// attemptLogs 本次执行最后几行日志
+func (s *sStep) attemptLogs(attempt *models.SStepAttempt, on *models.SRetryOn) string {
+	if attempt.LogStart == nil || attempt.LogEnd == nil {
+		return ""
+	}
+	var n = defaultRetryLogLines
+	if on != nil && on.LogLines > 0 {
+		n = on.LogLines
+	}
+	var contents []string
+	for _, v := range s.stg.Log().Range(*attempt.LogStart, *attempt.LogEnd) {
+		if v.Content == common.ExecConsoleStart || v.Content == common.ExecConsoleDone {
+			continue
+		}
+		contents = append(contents, v.Content)
+	}
+	if len(contents) > n {
+		contents = contents[len(contents)-n:]
+	}
+	return strings.Join(contents, "\n")
+}
+
+// writeConsole 写入控制台标记, 返回其行号
+func (s *sStep) writeConsole(content string) *int64 {
+	log := &models.SStepLog{
+		Timestamp: time.Now().UnixNano(),
+		Content:   content,
+	}
+	if err := s.stg.Log().Insert(log); err != nil {
+		logx.Warnln(s.taskName, s.stepName, err)
+		return nil
+	}
+	return log.Line
+}
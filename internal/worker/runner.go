@@ -4,10 +4,13 @@ import (
 	"strings"
 
 	"github.com/busyster996/dagflow/internal/runner"
+	_ "github.com/busyster996/dagflow/internal/runner/approval"
+	_ "github.com/busyster996/dagflow/internal/runner/subtask"
+	"github.com/busyster996/dagflow/internal/storage"
 )
 
-func (s *sStep) newExecutorRunner() (runner.IRunner, error) {
-	commandType, err := s.stg.Type()
+func (s *sStep) newExecutorRunner(stg storage.IStep) (runner.IRunner, error) {
+	commandType, err := stg.Type()
 	if err != nil {
 		return nil, err
 	}
@@ -19,5 +22,19 @@ func (s *sStep) newExecutorRunner() (runner.IRunner, error) {
 	if err != nil {
 		return nil, err
 	}
-	return executor(s.stg, subCmd, s.workspace, s.scriptDir)
+	return executor(stg, subCmd, s.workspace, s.scriptDir)
+}
+
+func (s *sStep) setRunner(r runner.IRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runner = r
+}
+
+// pauser 正在执行且支持挂起的runner
+func (s *sStep) pauser() (runner.IPauser, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pauser, ok := s.runner.(runner.IPauser)
+	return pauser, ok
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/expr-lang/expr"
@@ -20,7 +21,7 @@ import (
 )
 
 type sStep struct {
-	// 生命周期控制（强杀）
+	// 生命周期控制（强杀）, 重新执行时替换, 由 mu 保护
 	lcCtx    context.Context
 	lcCancel context.CancelFunc
 
@@ -28,7 +29,16 @@ type sStep struct {
 	ctrlCtx    context.Context
 	ctrlCancel context.CancelFunc
 
+	task      *sTask
 	stg       storage.IStep
+	limiter   chan struct{}  // 矩阵实例及foreach子步骤并行数限制
+	inputs    map[string]any // foreach子步骤沿用父步骤的上游输出
+	children  []*sStep       // 正在执行的foreach子步骤
+	envs      models.SEnvs   // 附加的环境变量, 如任务处理步骤可见的任务状态
+	mu        sync.Mutex
+	runner    runner.IRunner // 正在执行的runner
+	skip      string         // 人工跳过的原因
+	attempts  int            // 重新执行前已有的执行次数, 新的执行记录接续编号
 	kind      string
 	taskName  string
 	stepName  string
@@ -37,6 +47,15 @@ type sStep struct {
 	state     int32 // 0: 正常, 1: 挂起
 }
 
+// sDetachedStep 不参与主流程依赖的步骤, 如foreach子步骤及任务处理步骤, 由父步骤或任务单独调度
+type sDetachedStep struct {
+	*sStep
+}
+
+func (d *sDetachedStep) Dependencies() []string {
+	return nil
+}
+
 func (s *sStep) Name() string {
 	return fmt.Sprintf("%s/%s", s.taskName, s.stepName)
 }
@@ -45,6 +64,25 @@ func (s *sStep) Dependencies() []string {
 	return s.stg.Depend().List()
 }
 
+func (s *sStep) AllowFailure() bool {
+	return s.stg.AllowFailure()
+}
+
+// Throttle 矩阵实例及foreach子步骤的并行数限制, 由调度器在提交执行前占用
+func (s *sStep) Throttle() chan struct{} {
+	return s.limiter
+}
+
+// Priority 多个步骤同时就绪时优先级高的先执行
+func (s *sStep) Priority() int {
+	priority, err := s.stg.Priority()
+	if err != nil {
+		logx.Errorln(s.taskName, s.stepName, "Priority error:", err)
+		return 0
+	}
+	return priority
+}
+
 func (s *sStep) RetryPolicy() *dagcuter.RetryPolicy {
 	retryPolicy, err := s.stg.RetryPolicy()
 	if err != nil {
@@ -56,12 +94,13 @@ func (s *sStep) RetryPolicy() *dagcuter.RetryPolicy {
 		MaxAttempts: retryPolicy.MaxAttempts,
 		MaxInterval: retryPolicy.MaxInterval,
 		Multiplier:  retryPolicy.Multiplier,
+		Jitter:      retryPolicy.Jitter,
+		RetryIf:     s.retryIf(retryPolicy),
 	}
 }
 
 func (s *sStep) PreExecution(ctx context.Context, input map[string]any) error {
 	logx.Infoln(s.taskName, s.stepName, s.workspace, "PreExecution")
-	event.Sendf("%s %s PreExecution", s.taskName, s.stepName)
 	return nil
 }
 
@@ -70,16 +109,61 @@ func (s *sStep) Execute(ctx context.Context, input map[string]any) (map[string]a
 		return nil, err
 	}
 	logx.Infoln(s.taskName, s.stepName, s.workspace, "Execute")
-	event.Sendf("%s %s Execute", s.taskName, s.stepName)
 	var err error
-	if err = s.stg.Update(&models.SStepUpdate{
+	// 人工跳过, 视为成功以便下游步骤继续执行
+	if reason := s.skipReason(); reason != "" {
+		event.Emit(&event.SEvent{Type: event.TypeSkip, Task: s.taskName, Step: s.stepName, Message: reason})
+		if err = s.stg.Update(&models.SStepUpdate{
+			State:    models.Pointer(models.StateSkipped),
+			OldState: models.Pointer(models.StatePending),
+			Code:     models.Pointer(common.ExecCodeSkipped),
+			Message:  reason,
+			STime:    models.Pointer(time.Now()),
+			ETime:    models.Pointer(time.Now()),
+		}); err != nil {
+			logx.Errorln(s.taskName, s.stepName, err)
+		}
+		return nil, nil
+	}
+	for name, value := range s.inputs {
+		if _, ok := input[name]; !ok {
+			input[name] = value
+		}
+	}
+	step, err := s.stg.Get()
+	if err != nil {
+		logx.Errorln(s.taskName, s.stepName, err)
+		return nil, err
+	}
+	// foreach步骤只负责生成及等待子步骤, 审批等执行器只等待外部结果, 均不占用节点预算
+	foreach := step.Foreach.Data()
+	if foreach.From == "" && !runner.IsIdle(step.Type) {
+		var release func()
+		release, err = s.acquireBudget(ctx)
+		if err != nil {
+			logx.Errorln(s.taskName, s.stepName, err)
+			return nil, err
+		}
+		defer release()
+	}
+
+	// 每次执行单独记录, 步骤的开始时间为首次执行的开始时间
+	n, _ := input["attempt"].(int)
+	var attempt = &models.SStepAttempt{
+		Attempt: s.attemptBase() + max(n, 1),
+		State:   models.Pointer(models.StateRunning),
+		STime:   models.Pointer(time.Now()),
+	}
+	var update = &models.SStepUpdate{
 		State:    models.Pointer(models.StateRunning),
 		OldState: models.Pointer(models.StatePending),
 		Message:  "step is running",
-		STime:    models.Pointer(time.Now()),
-	}); err != nil {
+	}
+	if attempt.Attempt == 1 {
+		update.STime = attempt.STime
+	}
+	if err = s.stg.Update(update); err != nil {
 		logx.Errorln(s.taskName, s.stepName, err)
-		event.Sendf("%s %s error %v", s.taskName, s.stepName, err)
 		return nil, err
 	}
 
@@ -97,12 +181,23 @@ func (s *sStep) Execute(ctx context.Context, input map[string]any) (map[string]a
 		if _err := s.stg.Update(res); _err != nil {
 			logx.Errorln(_err)
 		}
-		event.Sendf("%s %s %v", s.taskName, s.stepName, res.Message)
+		attempt.State = res.State
+		attempt.Code = res.Code
+		attempt.Message = res.Message
+		attempt.ETime = res.ETime
+		if _err := s.stg.Attempt().Update(attempt); _err != nil {
+			logx.Errorln(_err)
+		}
 	}()
 
-	// 日志写入
-	s.stg.Log().Write(common.ExecConsoleStart)
-	defer s.stg.Log().Write(common.ExecConsoleDone)
+	// 日志写入, 每次执行的日志区间记录在执行记录中
+	attempt.LogStart = s.writeConsole(common.ExecConsoleStart)
+	if err = s.stg.Attempt().Insert(attempt); err != nil {
+		logx.Warnln(s.taskName, s.stepName, err)
+	}
+	defer func() {
+		attempt.LogEnd = s.writeConsole(common.ExecConsoleDone)
+	}()
 
 	if s.kind == common.KindStrategy {
 		// 评估规则, 使用expr
@@ -132,7 +227,14 @@ func (s *sStep) Execute(ctx context.Context, input map[string]any) (map[string]a
 		}()
 	}
 	var _runner runner.IRunner
-	_runner, err = s.newExecutorRunner()
+	// 清理上一次执行的输出
+	if err = s.stg.Output().RemoveAll(); err != nil {
+		logx.Warnln(s.taskName, s.stepName, err)
+	}
+	if foreach.From != "" {
+		return s.executeForeach(ctx, input, foreach, res)
+	}
+	_runner, err = s.newExecutorRunner(newInputStep(s.stg, input, s.envs...))
 	if err != nil {
 		logx.Errorln(s.taskName, s.stepName, err)
 		res.State = models.Pointer(models.StateFailed)
@@ -146,10 +248,12 @@ func (s *sStep) Execute(ctx context.Context, input map[string]any) (map[string]a
 			logx.Warnln(cErr)
 		}
 	}()
+	s.setRunner(_runner)
+	defer s.setRunner(nil)
 
 	res.Message = "execution succeed"
 	var code common.ExecCode
-	_ctx, cancel := utility.MergerContext(ctx, s.lcCtx)
+	_ctx, cancel := utility.MergerContext(ctx, s.lifecycle())
 	defer cancel()
 	code, err = _runner.Run(_ctx)
 	res.Code = models.Pointer(code)
@@ -164,12 +268,81 @@ func (s *sStep) Execute(ctx context.Context, input map[string]any) (map[string]a
 		res.Message = fmt.Sprintf("execution failed with code: %d", code)
 		return nil, errors.New(res.Message)
 	}
-	return nil, nil
+	return s.outputs(), nil
+}
+
+// restoredResult 上次执行的结果, 恢复执行时直接使用
+func (s *sStep) restoredResult() (*dagcuter.Result, error) {
+	step, err := s.stg.Get()
+	if err != nil {
+		return nil, err
+	}
+	switch *step.State {
+	case models.StateStopped:
+		return &dagcuter.Result{
+			Status: dagcuter.StatusSucceeded,
+			Output: s.outputs(),
+		}, nil
+	case models.StateFailed:
+		return &dagcuter.Result{
+			Status:    dagcuter.StatusFailed,
+			Err:       fmt.Errorf("step %s failed: %s", s.stepName, step.Message),
+			Tolerated: *step.AllowFailure,
+		}, nil
+	default:
+		return &dagcuter.Result{
+			Status: dagcuter.StatusSkipped,
+			Reason: step.Message,
+		}, nil
+	}
+}
+
+// reset 重置步骤的生命周期, 用于重新执行
+func (s *sStep) reset(attempts int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lcCtx, s.lcCancel = context.WithCancel(context.WithValue(context.Background(), "ctx", "step"))
+	s.skip = ""
+	s.attempts = attempts
+	stepManager.Store(s.Name(), s)
+}
+
+func (s *sStep) attemptBase() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+// lifecycle 当前的生命周期上下文, 重新执行时由 reset 替换
+func (s *sStep) lifecycle() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lcCtx
+}
+
+func (s *sStep) setSkip(reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.skip = reason
+}
+
+func (s *sStep) skipReason() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skip
+}
+
+// outputs 当前步骤的输出, 传递给下游步骤
+func (s *sStep) outputs() map[string]any {
+	var res = make(map[string]any)
+	for _, output := range s.stg.Output().List() {
+		res[output.Name] = output.Value
+	}
+	return res
 }
 
 func (s *sStep) PostExecution(ctx context.Context, output map[string]any) error {
 	logx.Infoln(s.taskName, s.stepName, s.workspace, "PostExecution")
-	event.Sendf("%s %s PostExecution", s.taskName, s.stepName)
 	stepManager.Delete(s.Name())
 	return nil
 }
@@ -180,22 +353,36 @@ func (s *sStep) Stop() {
 			logx.Errorln(_err)
 		}
 	}()
-	if s.lcCancel != nil {
+	s.mu.Lock()
+	cancel := s.lcCancel
+	s.mu.Unlock()
+	if cancel != nil {
 		logx.Infoln(s.taskName, s.stepName, s.workspace, "Stop")
-		event.Sendf("%s %s Stop", s.taskName, s.stepName)
-		s.lcCancel()
+		event.Emit(&event.SEvent{Type: event.TypeStop, Task: s.taskName, Step: s.stepName})
+		cancel()
 	}
 	stepManager.Delete(s.Name())
 }
 
 func (s *sStep) checkCtx(ctx context.Context) error {
+	lcCtx := s.lifecycle()
+	// 任务挂起, 则等待任务解挂
+	if s.task != nil && s.task.ctrlCtx != nil {
+		select {
+		case <-s.task.ctrlCtx.Done():
+		case <-lcCtx.Done():
+			return lcCtx.Err()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 	// 挂起, 则等待解挂
 	if s.ctrlCtx != nil {
 		// 等待控制信号
 		select {
 		case <-s.ctrlCtx.Done():
-		case <-s.lcCtx.Done():
-			return s.lcCtx.Err()
+		case <-lcCtx.Done():
+			return lcCtx.Err()
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -204,8 +391,8 @@ func (s *sStep) checkCtx(ctx context.Context) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
-	if s.lcCtx.Err() != nil {
-		return s.lcCtx.Err()
+	if lcCtx.Err() != nil {
+		return lcCtx.Err()
 	}
 	return nil
 }
@@ -2,15 +2,21 @@ package worker
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"go.uber.org/multierr"
 
 	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/runner"
 	"github.com/busyster996/dagflow/internal/storage"
 	"github.com/busyster996/dagflow/internal/storage/models"
 	"github.com/busyster996/dagflow/internal/utility"
@@ -34,6 +40,8 @@ type sTask struct {
 	workspace string
 	scriptDir string
 	dagTasks  map[string]dagcuter.Task
+	restored  []string // 上次执行已结束的步骤, 恢复执行时不再运行
+	dag       *dagcuter.Dagcuter
 	state     int32 // 0: 正常, 1: 挂起
 }
 
@@ -88,7 +96,13 @@ func newTask(taskName string) (*sTask, error) {
 		return nil, err
 	}
 
+	// 同一矩阵步骤的实例共享并行数限制
+	var limiters = make(map[string]chan struct{})
 	for _, s := range t.stg.StepList("") {
+		// foreach生成的子步骤由父步骤执行, 处理步骤在主流程结束后执行
+		if s.Foreach.Data().Parent != "" || s.Handler != "" {
+			continue
+		}
 		if t.stg.Step(s.Name).IsDisable() {
 			logx.Infoln("the step is disabled, no execution required", s.Name)
 			_ = t.stg.Step(s.Name).Update(&models.SStepUpdate{
@@ -100,7 +114,18 @@ func newTask(taskName string) (*sTask, error) {
 			})
 			continue
 		}
-		t.dagTasks[s.Name] = t.newStep(s.Name)
+		step := t.newStep(s.Name)
+		if matrix := s.Matrix.Data(); matrix.Group != "" && matrix.MaxParallel > 0 {
+			if _, ok := limiters[matrix.Group]; !ok {
+				limiters[matrix.Group] = make(chan struct{}, matrix.MaxParallel)
+			}
+			step.limiter = limiters[matrix.Group]
+		}
+		t.dagTasks[s.Name] = step
+		switch *s.State {
+		case models.StateStopped, models.StateFailed, models.StateSkipped:
+			t.restored = append(t.restored, s.Name)
+		}
 	}
 	if dagcuter.HasCycle(t.dagTasks) {
 		err = errors.New("the task has a cycle")
@@ -119,14 +144,22 @@ func (t *sTask) Execute() (err error) {
 	defer func() {
 		// 清理资源
 		t.Stop()
+		// 让出并发组
+		t.releaseGroup()
 	}()
-	// 更新任务状态为运行中
-	if err = t.stg.Update(&models.STaskUpdate{
+	// 更新任务状态为运行中, 重新接管的挂起任务保持挂起, 解挂后恢复为运行中
+	var update = &models.STaskUpdate{
 		State:    models.Pointer(models.StateRunning),
 		OldState: models.Pointer(models.StatePending),
 		STime:    models.Pointer(time.Now()),
 		Message:  "task is running",
-	}); err != nil {
+	}
+	if atomic.LoadInt32(&t.state) == 1 {
+		update.State = models.Pointer(models.StatePaused)
+		update.OldState = models.Pointer(models.StateRunning)
+		update.Message = "has been paused"
+	}
+	if err = t.stg.Update(update); err != nil {
 		logx.Errorln(t.taskName, err)
 		return
 	}
@@ -141,6 +174,8 @@ func (t *sTask) Execute() (err error) {
 		return
 	}
 	res := new(models.STaskUpdate)
+	// 允许失败的步骤
+	var tolerated []string
 	defer func() {
 		if r := recover(); r != nil {
 			stack := debug.Stack()
@@ -149,6 +184,9 @@ func (t *sTask) Execute() (err error) {
 		}
 		res.State = models.Pointer(models.StateStopped)
 		res.Message = "task has stopped"
+		if len(tolerated) != 0 {
+			res.Message = fmt.Sprintf("task has stopped, allowed failures: %s", strings.Join(tolerated, ", "))
+		}
 		res.ETime = models.Pointer(time.Now())
 		res.OldState = models.Pointer(models.StateRunning)
 		if err != nil {
@@ -159,6 +197,13 @@ func (t *sTask) Execute() (err error) {
 			logx.Warnln(t.taskName, updErr)
 		}
 	}()
+	// 处理步骤在清理工作目录前执行, 失败时任务失败
+	defer func() {
+		if hErr := t.runHandlers(err); hErr != nil {
+			logx.Errorln(t.taskName, hErr)
+			err = multierr.Append(err, hErr)
+		}
+	}()
 
 	timeout, err := t.stg.Timeout()
 	if err != nil {
@@ -179,18 +224,42 @@ func (t *sTask) Execute() (err error) {
 		logx.Errorln(t.taskName, err)
 		return
 	}
+	_dag.SetFailFast(t.stg.FailFast())
+	for _, name := range t.restored {
+		logx.Infoln(t.taskName, name, "restored from previous execution")
+		var result *dagcuter.Result
+		result, err = t.dagTasks[name].(*sStep).restoredResult()
+		if err != nil {
+			logx.Errorln(t.taskName, name, err)
+			return
+		}
+		if err = _dag.Restore(name, result); err != nil {
+			logx.Errorln(t.taskName, err)
+			return
+		}
+	}
+	_dag.SetObserver(&sObserver{taskName: t.taskName})
+	t.dag = _dag
 	_, err = _dag.Execute(ctx)
 	if err != nil {
 		logx.Errorln(t.taskName, err)
 		return
 	}
+	for name, result := range _dag.Results() {
+		if result.Status == dagcuter.StatusFailed && result.Tolerated {
+			tolerated = append(tolerated, name)
+		}
+	}
+	slices.Sort(tolerated)
 	// 策略模式下，获取最后一个非待执行状态的步骤状态
 	if t.kind == common.KindStrategy {
 		steps := t.stg.StepList("")
 		for i := len(steps) - 1; i >= 0; i-- {
 			switch *steps[i].State {
 			case models.StateFailed:
-				err = errors.New(steps[i].Message)
+				if !*steps[i].AllowFailure {
+					err = errors.New(steps[i].Message)
+				}
 				return
 			case models.StateStopped:
 				return
@@ -220,17 +289,22 @@ func (t *sTask) checkCtx() error {
 	return nil
 }
 
+// kill 终止主流程, 处理步骤执行完后由Execute清理资源
+func (t *sTask) kill() {
+	if t.lcCancel != nil {
+		logx.Infoln(t.taskName, "Stop")
+		event.Emit(&event.SEvent{Type: event.TypeStop, Task: t.taskName})
+		t.lcCancel()
+	}
+}
+
 func (t *sTask) Stop() {
 	defer func() {
 		if _err := recover(); _err != nil {
 			logx.Errorln(_err)
 		}
 	}()
-	if t.lcCancel != nil {
-		logx.Infoln(t.taskName, "Stop")
-		event.Sendf("%s Stop", t.taskName)
-		t.lcCancel()
-	}
+	t.kill()
 	// 删除manager
 	taskManager.Delete(t.taskName)
 	for _, step := range t.dagTasks {
@@ -239,6 +313,24 @@ func (t *sTask) Stop() {
 	t.clearDir()
 }
 
+// restorePause 重新接管时恢复重启前挂起的任务及步骤, 需手动解挂
+func (t *sTask) restorePause() {
+	if state, err := t.stg.State(); err == nil && state == models.StatePaused {
+		atomic.StoreInt32(&t.state, 1)
+		t.ctrlCtx, t.ctrlCancel = context.WithCancel(context.Background())
+	}
+	for _, task := range t.dagTasks {
+		step, ok := task.(*sStep)
+		if !ok {
+			continue
+		}
+		if state, err := step.stg.State(); err == nil && state == models.StatePaused {
+			atomic.StoreInt32(&step.state, 1)
+			step.ctrlCtx, step.ctrlCancel = context.WithCancel(context.Background())
+		}
+	}
+}
+
 func (t *sTask) initDir() error {
 	if err := utility.EnsureDirExist(t.workspace); err != nil {
 		logx.Errorln(t.taskName, t.workspace, t.scriptDir, err)
@@ -262,6 +354,7 @@ func (t *sTask) clearDir() {
 
 func (t *sTask) newStep(stepName string) *sStep {
 	s := &sStep{
+		task:      t,
 		kind:      t.kind,
 		taskName:  t.taskName,
 		stepName:  stepName,
@@ -273,3 +366,29 @@ func (t *sTask) newStep(stepName string) *sStep {
 	stepManager.Store(s.Name(), s)
 	return s
 }
+
+// pausable 是否有正在执行且支持挂起的步骤
+func (t *sTask) pausable() (ok bool) {
+	t.forEachPauser(func(runner.IPauser) error {
+		ok = true
+		return nil
+	})
+	return
+}
+
+// forEachPauser 对正在执行且支持挂起的步骤执行操作, 如同步挂起子任务
+func (t *sTask) forEachPauser(fn func(pauser runner.IPauser) error) {
+	for _, task := range t.dagTasks {
+		step, ok := task.(*sStep)
+		if !ok {
+			continue
+		}
+		for _, step := range append([]*sStep{step}, step.foreachChildren()...) {
+			if pauser, ok := step.pauser(); ok {
+				if err := fn(pauser); err != nil {
+					logx.Warnln(t.taskName, step.stepName, err)
+				}
+			}
+		}
+	}
+}
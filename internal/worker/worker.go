@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,12 +13,14 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/busyster996/dagflow/internal/common"
+	"github.com/busyster996/dagflow/internal/janitor"
 	"github.com/busyster996/dagflow/internal/pubsub"
 	"github.com/busyster996/dagflow/internal/runner"
 	"github.com/busyster996/dagflow/internal/storage"
 	"github.com/busyster996/dagflow/internal/storage/models"
 	"github.com/busyster996/dagflow/internal/utility"
 	"github.com/busyster996/dagflow/internal/worker/event"
+	"github.com/busyster996/dagflow/pkg/dagcuter"
 	"github.com/busyster996/dagflow/pkg/logx"
 	"github.com/busyster996/dagflow/pkg/tunny"
 )
@@ -30,7 +33,8 @@ var (
 
 func Start(ctx context.Context) error {
 	logx.Infoln("number of workers", GetSize())
-	if err := storage.FixDatabase(viper.GetString("node_name")); err != nil {
+	adopted, err := storage.FixDatabase(viper.GetString("node_name"))
+	if err != nil {
 		return err
 	}
 
@@ -39,14 +43,22 @@ func Start(ctx context.Context) error {
 		// clear old script
 		utility.ClearDir(filepath.Join(viper.GetString("script_dir"), t.Name()))
 
+		// 重新接管的任务保留工作目录
+		if slices.Contains(adopted, t.Name()) {
+			continue
+		}
 		// clear old workspace
 		utility.ClearDir(filepath.Join(viper.GetString("workspace_dir"), t.Name()))
 	}
 
+	// 定期清理残留的脚本及工作目录
+	janitor.Register("dir", cleanDirs)
+	janitor.Start(ctx)
+
 	// 打印当前支持的runner
 	logx.Infoln("runner", runner.ListAvailable())
 	if err := pubsub.SubscribeTask(ctx, viper.GetString("node_name"), func(data string) {
-		if data == "" {
+		if data == "" || !pending(data) {
 			return
 		}
 		t, err := newTask(data)
@@ -62,7 +74,7 @@ func Start(ctx context.Context) error {
 		return err
 	}
 	if err := pubsub.SubscribeTask(ctx, "random", func(data string) {
-		if data == "" {
+		if data == "" || !pending(data) {
 			return
 		}
 		t, err := newTask(data)
@@ -107,6 +119,17 @@ func Start(ctx context.Context) error {
 	}); err != nil {
 		return err
 	}
+	// 重新接管重启前未完成的任务
+	for _, name := range adopted {
+		adoptTask(name)
+	}
+	// 调度重启前排队的任务
+	for _, group := range storage.QueuedGroups() {
+		storage.DispatchGroup(group)
+	}
+
+	go budget.report(ctx, viper.GetString("node_name"))
+
 	_event, id, err := event.Subscribe()
 	if err != nil {
 		return err
@@ -125,6 +148,36 @@ func Start(ctx context.Context) error {
 	return nil
 }
 
+// pending 任务是否仍在等待执行, 提交后被取消的任务不再执行
+func pending(taskName string) bool {
+	state, err := storage.Task(taskName).State()
+	if err != nil {
+		logx.Errorln(taskName, err)
+		return false
+	}
+	if state != models.StatePending {
+		logx.Warnln(taskName, "the task is no longer pending", models.StateMap[state])
+		return false
+	}
+	return true
+}
+
+// adoptTask 继续执行重启前未完成的任务, 已结束的步骤沿用其结果
+func adoptTask(taskName string) {
+	logx.Infoln("adopt task", taskName)
+	t, err := newTask(taskName)
+	if err != nil {
+		logx.Errorln(err)
+		return
+	}
+	t.restorePause()
+	go func() {
+		if err := pool.Submit(t.Execute); err != nil {
+			logx.Errorln(err)
+		}
+	}()
+}
+
 func managerTask(taskName, action, duration string) error {
 	t, err := storage.Task(taskName).Get()
 	if err != nil {
@@ -138,17 +191,22 @@ func managerTask(taskName, action, duration string) error {
 	task, ok := value.(*sTask)
 	switch action {
 	case "kill":
-		task.Stop()
+		task.kill()
 		return storage.Task(taskName).Update(&models.STaskUpdate{
 			State:    models.Pointer(models.StateFailed),
 			OldState: t.State,
 			Message:  "has been killed",
 		})
 	case "pause":
-		if *t.State == models.StateRunning {
+		// 运行中的任务仅在有可挂起的步骤(如子任务)执行时支持挂起,
+		// 挂起后不再调度新的步骤, 运行中的子任务同步挂起
+		if *t.State == models.StateRunning && !task.pausable() {
 			return errors.New("step is running")
 		}
 		if atomic.CompareAndSwapInt32(&task.state, 0, 1) {
+			task.forEachPauser(func(pauser runner.IPauser) error {
+				return pauser.Pause(duration)
+			})
 			var d time.Duration
 			d, err = time.ParseDuration(duration)
 			if err == nil && d > 0 {
@@ -167,6 +225,9 @@ func managerTask(taskName, action, duration string) error {
 			if task.ctrlCancel != nil {
 				task.ctrlCancel()
 			}
+			task.forEachPauser(func(pauser runner.IPauser) error {
+				return pauser.Resume()
+			})
 			return storage.Task(taskName).Update(&models.STaskUpdate{
 				State:    t.OldState,
 				OldState: t.State,
@@ -178,6 +239,12 @@ func managerTask(taskName, action, duration string) error {
 }
 
 func managerStep(taskName, stepName, action, duration string) error {
+	switch action {
+	case "retry":
+		return retryStep(taskName, stepName)
+	case "skip":
+		return skipStep(taskName, stepName)
+	}
 	value, ok := stepManager.Load(fmt.Sprintf("%s/%s", taskName, stepName))
 	if !ok {
 		return errors.New("step not found")
@@ -200,10 +267,18 @@ func managerStep(taskName, stepName, action, duration string) error {
 			Message:  "has been killed",
 		})
 	case "pause":
-		if *s.State == models.StateRunning {
+		// 运行中的步骤仅支持挂起子任务等可挂起的runner
+		pauser, running := step.pauser()
+		if (*s.State == models.StateRunning || *s.State == models.StateWaiting) && !running {
 			return errors.New("step is running")
 		}
 		if atomic.CompareAndSwapInt32(&step.state, 0, 1) {
+			if running {
+				if err = pauser.Pause(duration); err != nil {
+					atomic.StoreInt32(&step.state, 0)
+					return err
+				}
+			}
 			var d time.Duration
 			d, err = time.ParseDuration(duration)
 			if err == nil && d > 0 {
@@ -222,6 +297,11 @@ func managerStep(taskName, stepName, action, duration string) error {
 			if step.ctrlCancel != nil {
 				step.ctrlCancel()
 			}
+			if pauser, ok := step.pauser(); ok {
+				if err = pauser.Resume(); err != nil {
+					logx.Warnln(taskName, stepName, err)
+				}
+			}
 			return storage.Task(taskName).Step(stepName).Update(&models.SStepUpdate{
 				State:    s.OldState,
 				OldState: s.State,
@@ -232,6 +312,111 @@ func managerStep(taskName, stepName, action, duration string) error {
 	return nil
 }
 
+// runningStep 执行中任务的步骤, 包括已结束的步骤
+func runningStep(taskName, stepName string) (*sTask, *sStep, error) {
+	value, ok := taskManager.Load(taskName)
+	if !ok {
+		return nil, nil, errors.New("task not found")
+	}
+	task, ok := value.(*sTask)
+	if !ok {
+		return nil, nil, errors.New("task not found")
+	}
+	step, ok := task.dagTasks[stepName].(*sStep)
+	if ok {
+		return task, step, nil
+	}
+	// foreach生成的子步骤
+	for _, parent := range task.dagTasks {
+		for _, child := range parent.(*sStep).foreachChildren() {
+			if child.stepName == stepName {
+				return task, child, nil
+			}
+		}
+	}
+	return nil, nil, errors.New("step not found")
+}
+
+// retryStep 重新执行执行中任务里已失败的步骤, 完成后继续调度其下游步骤
+func retryStep(taskName, stepName string) error {
+	task, step, err := runningStep(taskName, stepName)
+	if err != nil {
+		return err
+	}
+	if task.dag == nil {
+		return errors.New("task is not running")
+	}
+	stg := storage.Task(taskName).Step(stepName)
+	s, err := stg.Get()
+	if err != nil {
+		return err
+	}
+	if *s.State != models.StateFailed {
+		return errors.New("only failed steps can be retried")
+	}
+	if parent := s.Foreach.Data().Parent; parent != "" {
+		return fmt.Errorf("foreach step is retried with step %s", parent)
+	}
+	// 保留之前的日志及执行记录, 重新执行作为新的执行记录接续
+	var attempts int
+	for _, attempt := range stg.Attempt().List() {
+		attempts = max(attempts, attempt.Attempt)
+	}
+	step.reset(attempts)
+	_ = stg.Output().RemoveAll()
+	_ = stg.Export().RemoveAll()
+	// 重新执行审批步骤需要重新审批
+	_ = stg.Approval().Remove()
+	if err = stg.Update(&models.SStepUpdate{
+		State:    models.Pointer(models.StatePending),
+		OldState: s.State,
+		Code:     models.Pointer(common.ExecCode(0)),
+		Message:  "the step is waiting to be retried",
+	}); err != nil {
+		return err
+	}
+	if err = task.dag.Rerun(stepName); err != nil {
+		_ = stg.Update(&models.SStepUpdate{
+			State:    s.State,
+			OldState: s.State,
+			Code:     s.Code,
+			Message:  s.Message,
+		})
+		if errors.Is(err, dagcuter.ErrNotInProgress) {
+			return errors.New("no steps are in progress, retry the step after the task has finished")
+		}
+		return err
+	}
+	event.Emit(&event.SEvent{Type: event.TypeRerun, Task: taskName, Step: stepName})
+	return nil
+}
+
+// skipStep 跳过执行中任务里待执行的步骤, 下游步骤继续执行
+func skipStep(taskName, stepName string) error {
+	_, step, err := runningStep(taskName, stepName)
+	if err != nil {
+		return err
+	}
+	stg := storage.Task(taskName).Step(stepName)
+	s, err := stg.Get()
+	if err != nil {
+		return err
+	}
+	if *s.State != models.StatePending && !(*s.State == models.StatePaused && *s.OldState == models.StatePending) {
+		return errors.New("only pending steps can be skipped")
+	}
+	step.setSkip("skipped manually")
+	// 挂起中的步骤需解挂后才能标记跳过
+	if atomic.CompareAndSwapInt32(&step.state, 1, 0) && step.ctrlCancel != nil {
+		step.ctrlCancel()
+	}
+	return stg.Update(&models.SStepUpdate{
+		State:    models.Pointer(models.StatePending),
+		OldState: s.State,
+		Message:  "the step will be skipped",
+	})
+}
+
 func SetSize(n int) {
 	pool.SetSize(n)
 }
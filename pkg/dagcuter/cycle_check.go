@@ -19,6 +19,10 @@ func HasCycle(tasks map[string]Task) bool {
 		recStack[taskName] = true
 
 		for _, dep := range tasks[taskName].Dependencies() {
+			if _, ok := tasks[dep]; !ok {
+				// Missing dependency, reported by NewGraph
+				continue
+			}
 			if dfs(dep) {
 				return true
 			}
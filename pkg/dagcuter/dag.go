@@ -1,24 +1,40 @@
 package dagcuter
 
 import (
+	"container/heap"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/busyster996/dagflow/pkg/tunny"
 )
 
+// ErrNotInProgress 没有已调度未结束的任务, 执行即将或已经结束, 无法重新执行任务
+var ErrNotInProgress = errors.New("execution is not in progress")
+
 type Dagcuter struct {
 	Tasks          map[string]Task
 	results        *sync.Map
+	states         map[string]*Result
+	restored       map[string]*Result
 	inDegrees      map[string]int
 	dependents     map[string][]string
 	executionOrder []string
 	worker         *tunny.Pool
+	queue          *readyQueue
+	wake           chan struct{}
+	throttled      []string // 并行数已满等待调度的任务
+	failFast       bool
+	observer       Observer
+	cancel         context.CancelCauseFunc
+	ctx            context.Context
+	done           chan struct{}
+	active         int // 已调度未结束的任务数
 	mu             *sync.Mutex
-	wg             *sync.WaitGroup
 }
 
 func New(tasks map[string]Task) (*Dagcuter, error) {
@@ -26,15 +42,29 @@ func New(tasks map[string]Task) (*Dagcuter, error) {
 }
 
 func NewWithWorkers(tasks map[string]Task, maxWorkers int) (*Dagcuter, error) {
-	if HasCycle(tasks) {
-		return nil, fmt.Errorf("circular dependency detected")
+	// 不存在的依赖(如已禁用的任务)不参与构图及入度计算, 视为已满足
+	var deps = make(map[string][]string, len(tasks))
+	for name, task := range tasks {
+		deps[name] = make([]string, 0, len(task.Dependencies()))
+		for _, dep := range task.Dependencies() {
+			if _, ok := tasks[dep]; ok {
+				deps[name] = append(deps[name], dep)
+			}
+		}
+	}
+	graph, err := NewGraph(deps)
+	if err != nil {
+		return nil, err
 	}
 	dag := &Dagcuter{
 		mu:         new(sync.Mutex),
-		wg:         new(sync.WaitGroup),
 		results:    new(sync.Map),
+		states:     make(map[string]*Result),
+		restored:   make(map[string]*Result),
 		inDegrees:  make(map[string]int),
 		dependents: make(map[string][]string),
+		queue:      newReadyQueue(tasks, graph.Heights()),
+		wake:       make(chan struct{}, 1),
 		Tasks:      tasks,
 	}
 	if maxWorkers <= 0 {
@@ -46,9 +76,9 @@ func NewWithWorkers(tasks map[string]Task, maxWorkers int) (*Dagcuter, error) {
 	}
 	dag.worker = tunny.NewCallback(maxWorkers)
 
-	for name, task := range dag.Tasks {
-		dag.inDegrees[name] = len(task.Dependencies())
-		for _, dep := range task.Dependencies() {
+	for name := range dag.Tasks {
+		dag.inDegrees[name] = len(deps[name])
+		for _, dep := range deps[name] {
 			dag.dependents[dep] = append(dag.dependents[dep], name)
 		}
 	}
@@ -70,25 +100,67 @@ func (d *Dagcuter) ResizeWorker(newSize int) {
 	}
 }
 
+// SetFailFast 开启后任一任务失败(不含允许失败的任务)立即取消其他正在执行的任务
+func (d *Dagcuter) SetFailFast(enable bool) {
+	d.failFast = enable
+}
+
+// Restore 在执行前将任务标记为已完成, 执行时直接使用该结果而不再运行, 用于从失败处恢复执行
+func (d *Dagcuter) Restore(name string, result *Result) error {
+	if _, ok := d.Tasks[name]; !ok {
+		return fmt.Errorf("task %s not found", name)
+	}
+	d.restored[name] = result
+	return nil
+}
+
 func (d *Dagcuter) Execute(ctx context.Context) (map[string]map[string]any, error) {
 	defer d.results.Clear()
 	defer d.worker.Close()
-	errCh := make(chan error, len(d.Tasks)+1)
+	start := time.Now()
+	ctx, d.cancel = context.WithCancelCause(ctx)
+	defer d.cancel(nil)
 
+	d.mu.Lock()
+	d.ctx = ctx
+	d.done = make(chan struct{})
 	for name, deg := range d.inDegrees {
 		if deg == 0 {
-			d.wg.Add(1)
-			go d.runTask(ctx, name, errCh)
+			d.active++
+			heap.Push(d.queue, name)
 		}
 	}
+	if d.active == 0 {
+		close(d.done)
+	}
+	d.mu.Unlock()
 
-	d.wg.Wait()
-	close(errCh)
-	var err error
+	go d.dispatch(ctx)
+	<-d.done
 
-	for _err := range errCh {
-		err = errors.Join(err, _err)
+	// 按名称汇总失败任务的错误, 保证输出稳定
+	var err error
+	var names []string
+	d.mu.Lock()
+	for name := range d.states {
+		names = append(names, name)
 	}
+	slices.Sort(names)
+	for _, name := range names {
+		if result := d.states[name]; result.Status == StatusFailed && !result.Tolerated {
+			err = errors.Join(err, result.Err)
+		}
+	}
+	d.mu.Unlock()
+
+	d.notify(func(observer Observer) {
+		observer.OnComplete(&ExecutionEvent{
+			Time:     time.Now(),
+			Duration: time.Since(start),
+			Err:      err,
+			Results:  d.Results(),
+		})
+	})
 
 	results := make(map[string]map[string]any)
 	d.results.Range(func(key, value any) bool {
@@ -98,54 +170,209 @@ func (d *Dagcuter) Execute(ctx context.Context) (map[string]map[string]any, erro
 	return results, err
 }
 
-func (d *Dagcuter) runTask(ctx context.Context, name string, errCh chan error) {
-	defer d.wg.Done()
+// Rerun 在执行过程中重新执行已失败的任务, 因其失败而被跳过的下游任务会在其完成后重新评估
+func (d *Dagcuter) Rerun(name string) error {
+	d.mu.Lock()
+	if d.done == nil || d.active == 0 {
+		d.mu.Unlock()
+		return ErrNotInProgress
+	}
+	if result, ok := d.states[name]; !ok || result.Status != StatusFailed {
+		d.mu.Unlock()
+		return fmt.Errorf("task %s has not failed", name)
+	}
+
+	// 重置任务及因其失败而跳过的下游任务, 已执行或正在执行的下游任务保持不变
+	delete(d.states, name)
+	reset := []string{name}
+	for i := 0; i < len(reset); i++ {
+		for _, child := range d.dependents[reset[i]] {
+			if result, ok := d.states[child]; ok {
+				if result.Status != StatusSkipped {
+					continue
+				}
+				delete(d.states, child)
+				reset = append(reset, child)
+			} else if d.inDegrees[child] == 0 {
+				continue
+			}
+			d.inDegrees[child]++
+		}
+	}
+	d.active++
+	d.mu.Unlock()
+
+	d.enqueue(name)
+	return nil
+}
+
+func (d *Dagcuter) runTask(ctx context.Context, name string) {
+	defer d.untrack()
+	if result, ok := d.restored[name]; ok {
+		d.complete(ctx, name, result)
+		return
+	}
 	task := d.Tasks[name]
+	start := time.Now()
+	d.notify(func(observer Observer) {
+		observer.OnReady(&TaskEvent{Task: name, Time: start})
+	})
 
 	d.mu.Lock()
 	inputs := d.prepareInputs(task)
+	upstream := d.upstreamResults(task)
 	d.mu.Unlock()
 
-	output, err := d.executeTask(ctx, name, task, inputs)
-	if err != nil {
-		select {
-		case errCh <- err:
-		default:
+	var attempt int
+	result := d.evaluate(ctx, name, task, upstream)
+	if result == nil {
+		var output map[string]any
+		var err error
+		runCtx := ctx
+		if ctx.Err() != nil {
+			// 取消后仍需执行的任务不受取消影响
+			runCtx = context.WithoutCancel(ctx)
 		}
-		return
+		output, attempt, err = d.executeTask(runCtx, name, task, inputs)
+		if err != nil {
+			result = &Result{Status: StatusFailed, Err: err}
+			if tolerant, ok := task.(FailureTolerantTask); ok && tolerant.AllowFailure() {
+				result.Tolerated = true
+			}
+		} else {
+			result = &Result{Status: StatusSucceeded, Output: output}
+		}
+	}
+	event := &TaskEvent{
+		Task:      name,
+		Attempt:   attempt,
+		Time:      time.Now(),
+		Duration:  time.Since(start),
+		Err:       result.Err,
+		Output:    result.Output,
+		Tolerated: result.Tolerated,
 	}
+	switch result.Status {
+	case StatusSucceeded:
+		d.notify(func(observer Observer) { observer.OnSuccess(event) })
+	case StatusFailed:
+		d.notify(func(observer Observer) { observer.OnFailure(event) })
+	}
+	d.complete(ctx, name, result)
+}
 
+// untrack 任务结束, 所有任务结束后通知Execute返回
+func (d *Dagcuter) untrack() {
 	d.mu.Lock()
-	d.executionOrder = append(d.executionOrder, name)
-	d.mu.Unlock()
+	defer d.mu.Unlock()
+	d.active--
+	if d.active == 0 {
+		close(d.done)
+	}
+}
+
+// evaluate 判断任务是否需要执行, 需要执行时返回nil
+func (d *Dagcuter) evaluate(ctx context.Context, name string, task Task, upstream map[string]*Result) *Result {
+	// 条件任务自行决定取消后是否仍需执行, 如清理或通知任务
+	if conditional, ok := task.(ConditionalTask); ok {
+		run, reason, err := conditional.ShouldRun(ctx, upstream)
+		if err != nil {
+			return &Result{Status: StatusFailed, Err: fmt.Errorf("task %s condition failed: %w", name, err)}
+		}
+		if run {
+			return nil
+		}
+		return d.skip(ctx, name, task, reason)
+	}
+	if ctx.Err() != nil {
+		return d.skip(ctx, name, task, cancelledReason(ctx))
+	}
+	// 默认所有依赖成功才执行
+	var unsatisfied []string
+	for dep, res := range upstream {
+		if !res.Satisfied() {
+			unsatisfied = append(unsatisfied, dep)
+		}
+	}
+	if len(unsatisfied) == 0 {
+		return nil
+	}
+	slices.Sort(unsatisfied)
+	return d.skip(ctx, name, task, fmt.Sprintf("skipped because dependencies did not succeed: %s", strings.Join(unsatisfied, ", ")))
+}
+
+// cancelledReason 执行被取消时跳过任务的原因
+func cancelledReason(ctx context.Context) string {
+	return fmt.Sprintf("skipped because execution was cancelled: %v", context.Cause(ctx))
+}
+
+func (d *Dagcuter) skip(ctx context.Context, name string, task Task, reason string) *Result {
+	if skippable, ok := task.(SkippableTask); ok {
+		skippable.Skip(ctx, reason)
+	}
+	d.notify(func(observer Observer) {
+		observer.OnSkip(&TaskEvent{Task: name, Time: time.Now(), Reason: reason})
+	})
+	return &Result{Status: StatusSkipped, Reason: reason}
+}
 
+// complete 记录任务结果, 并调度入度为0的下游任务
+func (d *Dagcuter) complete(ctx context.Context, name string, result *Result) {
+	if result.Status == StatusFailed && !result.Tolerated && d.failFast {
+		d.cancel(fmt.Errorf("fail fast: %w", result.Err))
+	}
+
+	var ready []string
 	d.mu.Lock()
-	d.results.Store(name, output)
+	d.states[name] = result
+	if result.Status == StatusSucceeded {
+		d.executionOrder = append(d.executionOrder, name)
+		d.results.Store(name, result.Output)
+	}
 	for _, child := range d.dependents[name] {
+		// 重新执行时已完成或正在执行的下游任务入度为0, 不再调度
+		if d.inDegrees[child] == 0 {
+			continue
+		}
 		d.inDegrees[child]--
 		if d.inDegrees[child] == 0 {
-			d.wg.Add(1)
-			//go d.runTask(ctx, child, errCh)
-
-			// 使用 tunny 池来执行任务
-			_ = d.worker.Submit(func() error {
-				d.runTask(ctx, child, errCh)
-				return nil
-			})
+			d.active++
+			ready = append(ready, child)
 		}
 	}
 	d.mu.Unlock()
+	d.enqueue(ready...)
 }
 
-func (d *Dagcuter) executeTask(ctx context.Context, name string, task Task, inputs map[string]any) (map[string]any, error) {
+// executeTask 执行任务, 返回输出及最后一次的尝试次数
+func (d *Dagcuter) executeTask(ctx context.Context, name string, task Task, inputs map[string]any) (map[string]any, int, error) {
 	// 获取任务的重试策略
 	retryExecutor := d.newRetryExecutor(task.RetryPolicy())
 
 	var result map[string]any
+	var attempt int
+	var attemptStart time.Time
+	retryExecutor.onRetry = func(n int, err error, delay time.Duration) {
+		d.notify(func(observer Observer) {
+			observer.OnRetry(&TaskEvent{
+				Task:     name,
+				Attempt:  n,
+				Time:     time.Now(),
+				Duration: time.Since(attemptStart),
+				Delay:    delay,
+				Err:      err,
+			})
+		})
+	}
 
 	// 使用重试机制执行任务
 	err := retryExecutor.ExecuteWithRetry(ctx, name, func(n int) error {
 		inputs["attempt"] = n // 将当前尝试次数传递给任务
+		attempt = max(n, 1)
+		attemptStart = time.Now()
+		d.notify(func(observer Observer) {
+			observer.OnStart(&TaskEvent{Task: name, Attempt: attempt, Time: attemptStart})
+		})
 		// PreExecution
 		if err := task.PreExecution(ctx, inputs); err != nil {
 			return fmt.Errorf("pre execution failed: %w", err)
@@ -167,10 +394,32 @@ func (d *Dagcuter) executeTask(ctx context.Context, name string, task Task, inpu
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("task %s failed: %w", name, err)
+		return nil, attempt, fmt.Errorf("task %s failed: %w", name, err)
+	}
+
+	return result, attempt, nil
+}
+
+// upstreamResults 获取依赖任务的执行结果
+func (d *Dagcuter) upstreamResults(task Task) map[string]*Result {
+	upstream := make(map[string]*Result)
+	for _, dep := range task.Dependencies() {
+		if res, ok := d.states[dep]; ok {
+			upstream[dep] = res
+		}
 	}
+	return upstream
+}
 
-	return result, nil
+// Results 获取已完成任务的执行结果
+func (d *Dagcuter) Results() map[string]*Result {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	results := make(map[string]*Result, len(d.states))
+	for name, res := range d.states {
+		results[name] = res
+	}
+	return results
 }
 
 func (d *Dagcuter) prepareInputs(task Task) map[string]any {
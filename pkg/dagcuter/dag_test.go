@@ -0,0 +1,488 @@
+package dagcuter
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testTask struct {
+	name  string
+	deps  []string
+	err   error
+	allow bool
+	run   func(ctx context.Context) error
+	order *testOrder
+}
+
+func (t *testTask) Name() string {
+	return t.name
+}
+
+func (t *testTask) Dependencies() []string {
+	return t.deps
+}
+
+func (t *testTask) RetryPolicy() *RetryPolicy {
+	return nil
+}
+
+func (t *testTask) AllowFailure() bool {
+	return t.allow
+}
+
+func (t *testTask) PreExecution(context.Context, map[string]any) error {
+	return nil
+}
+
+func (t *testTask) PostExecution(context.Context, map[string]any) error {
+	return nil
+}
+
+func (t *testTask) Execute(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	if t.order != nil {
+		t.order.add(t.name)
+	}
+	if t.run != nil {
+		if err := t.run(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+	return map[string]any{"name": t.name}, nil
+}
+
+// alwaysTask 无论依赖结果及是否取消都执行
+type alwaysTask struct {
+	*testTask
+}
+
+func (t *alwaysTask) ShouldRun(context.Context, map[string]*Result) (bool, string, error) {
+	return true, "", nil
+}
+
+type prioritizedTask struct {
+	*testTask
+	priority int
+}
+
+func (t *prioritizedTask) Priority() int {
+	return t.priority
+}
+
+type throttledTask struct {
+	*testTask
+	throttle chan struct{}
+}
+
+func (t *throttledTask) Throttle() chan struct{} {
+	return t.throttle
+}
+
+type testOrder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (o *testOrder) add(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.names = append(o.names, name)
+}
+
+func (o *testOrder) index(name string) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.Index(o.names, name)
+}
+
+func newTestTasks(tasks ...Task) map[string]Task {
+	var res = make(map[string]Task, len(tasks))
+	for _, task := range tasks {
+		res[task.Name()] = task
+	}
+	return res
+}
+
+func TestExecuteOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		deps map[string][]string
+	}{
+		{
+			name: "chain",
+			deps: map[string][]string{"a": nil, "b": {"a"}, "c": {"b"}},
+		},
+		{
+			name: "diamond",
+			deps: map[string][]string{"a": nil, "b": {"a"}, "c": {"a"}, "d": {"b", "c"}},
+		},
+		{
+			name: "independent",
+			deps: map[string][]string{"a": nil, "b": nil, "c": {"a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order = new(testOrder)
+			var tasks = make(map[string]Task)
+			for name, deps := range tt.deps {
+				tasks[name] = &testTask{name: name, deps: deps, order: order}
+			}
+			dag, err := NewWithWorkers(tasks, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = dag.Execute(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			for name, deps := range tt.deps {
+				for _, dep := range deps {
+					if order.index(dep) > order.index(name) {
+						t.Errorf("%s ran before its dependency %s: %v", name, dep, order.names)
+					}
+				}
+			}
+			if len(order.names) != len(tt.deps) {
+				t.Errorf("ran %v, want %d tasks", order.names, len(tt.deps))
+			}
+		})
+	}
+}
+
+func TestPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority map[string]int
+		deps     map[string][]string
+		want     []string
+	}{
+		{
+			name:     "higher priority first",
+			priority: map[string]int{"b": 5, "c": 1},
+			deps:     map[string][]string{"a": nil, "b": nil, "c": nil},
+			want:     []string{"b", "c", "a"},
+		},
+		{
+			name: "longer remaining path first",
+			deps: map[string][]string{"x": nil, "y": nil, "z": {"y"}},
+			want: []string{"y", "x", "z"},
+		},
+		{
+			name:     "priority before remaining path",
+			priority: map[string]int{"x": 1},
+			deps:     map[string][]string{"x": nil, "y": nil, "z": {"y"}},
+			want:     []string{"x", "y", "z"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var order = new(testOrder)
+			var tasks = make(map[string]Task)
+			for name, deps := range tt.deps {
+				tasks[name] = &prioritizedTask{
+					testTask: &testTask{name: name, deps: deps, order: order},
+					priority: tt.priority[name],
+				}
+			}
+			// 单个工作者时按队列顺序依次执行
+			dag, err := NewWithWorkers(tasks, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = dag.Execute(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(order.names, tt.want) {
+				t.Errorf("order = %v, want %v", order.names, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkipPropagation(t *testing.T) {
+	failed := errors.New("failed")
+	tests := []struct {
+		name    string
+		tasks   []Task
+		want    map[string]Status
+		wantErr bool
+	}{
+		{
+			name: "failure skips dependents",
+			tasks: []Task{
+				&testTask{name: "a", err: failed},
+				&testTask{name: "b", deps: []string{"a"}},
+				&testTask{name: "c", deps: []string{"b"}},
+				&testTask{name: "d"},
+			},
+			want: map[string]Status{
+				"a": StatusFailed,
+				"b": StatusSkipped,
+				"c": StatusSkipped,
+				"d": StatusSucceeded,
+			},
+			wantErr: true,
+		},
+		{
+			name: "tolerated failure satisfies dependents",
+			tasks: []Task{
+				&testTask{name: "a", err: failed, allow: true},
+				&testTask{name: "b", deps: []string{"a"}},
+			},
+			want: map[string]Status{
+				"a": StatusFailed,
+				"b": StatusSucceeded,
+			},
+		},
+		{
+			name: "always task runs after a failure",
+			tasks: []Task{
+				&testTask{name: "a", err: failed},
+				&testTask{name: "b", deps: []string{"a"}},
+				&alwaysTask{&testTask{name: "c", deps: []string{"b"}}},
+			},
+			want: map[string]Status{
+				"a": StatusFailed,
+				"b": StatusSkipped,
+				"c": StatusSucceeded,
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing dependency is satisfied",
+			tasks: []Task{
+				&testTask{name: "a"},
+				&testTask{name: "b", deps: []string{"a", "disabled"}},
+				&testTask{name: "c", deps: []string{"disabled"}},
+			},
+			want: map[string]Status{
+				"a": StatusSucceeded,
+				"b": StatusSucceeded,
+				"c": StatusSucceeded,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dag, err := New(newTestTasks(tt.tasks...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = dag.Execute(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			results := dag.Results()
+			if len(results) != len(tt.want) {
+				t.Errorf("got %d results, want %d", len(results), len(tt.want))
+			}
+			for name, want := range tt.want {
+				if res, ok := results[name]; !ok || res.Status != want {
+					t.Errorf("task %s status = %v, want %v", name, res, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCancelRunsAlwaysTasks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	tasks := newTestTasks(
+		&testTask{name: "a", run: func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		&testTask{name: "b", deps: []string{"a"}},
+		&alwaysTask{&testTask{name: "cleanup", deps: []string{"a"}, run: func(ctx context.Context) error {
+			// 取消后执行的任务使用不受取消影响的上下文
+			return ctx.Err()
+		}}},
+	)
+	dag, err := New(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		<-started
+		cancel()
+	}()
+	if _, err = dag.Execute(ctx); err == nil {
+		t.Error("Execute() error = nil, want cancelled")
+	}
+	want := map[string]Status{
+		"a":       StatusFailed,
+		"b":       StatusSkipped,
+		"cleanup": StatusSucceeded,
+	}
+	for name, status := range want {
+		if res := dag.Results()[name]; res == nil || res.Status != status {
+			t.Errorf("task %s status = %v, want %v", name, res, status)
+		}
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	tests := []struct {
+		name     string
+		failFast bool
+		allow    bool
+		want     Status
+	}{
+		{name: "cancels running tasks", failFast: true, want: StatusFailed},
+		{name: "disabled", want: StatusSucceeded},
+		{name: "tolerated failure", failFast: true, allow: true, want: StatusSucceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := make(chan struct{})
+			tasks := newTestTasks(
+				&testTask{name: "a", err: errors.New("failed"), allow: tt.allow, run: func(context.Context) error {
+					<-started
+					return nil
+				}},
+				&testTask{name: "b", run: func(ctx context.Context) error {
+					close(started)
+					select {
+					case <-ctx.Done():
+						return ctx.Err()
+					case <-time.After(100 * time.Millisecond):
+						return nil
+					}
+				}},
+			)
+			dag, err := New(tasks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dag.SetFailFast(tt.failFast)
+			_, err = dag.Execute(context.Background())
+			// 允许失败的任务不使执行失败
+			if wantErr := !tt.allow; (err != nil) != wantErr {
+				t.Errorf("Execute() error = %v, wantErr %v", err, wantErr)
+			}
+			if res := dag.Results()["b"]; res == nil || res.Status != tt.want {
+				t.Errorf("task b status = %v, want %v", res, tt.want)
+			}
+		})
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		tasks int
+	}{
+		{name: "serial", limit: 1, tasks: 4},
+		{name: "pair", limit: 2, tasks: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak atomic.Int32
+			throttle := make(chan struct{}, tt.limit)
+			var tasks []Task
+			for i := 0; i < tt.tasks; i++ {
+				tasks = append(tasks, &throttledTask{
+					testTask: &testTask{
+						name: string(rune('a' + i)),
+						run: func(context.Context) error {
+							n := running.Add(1)
+							defer running.Add(-1)
+							for {
+								old := peak.Load()
+								if n <= old || peak.CompareAndSwap(old, n) {
+									break
+								}
+							}
+							time.Sleep(20 * time.Millisecond)
+							return nil
+						},
+					},
+					throttle: throttle,
+				})
+			}
+			// 不受限的任务不被阻塞
+			tasks = append(tasks, &testTask{name: "free"})
+			dag, err := NewWithWorkers(newTestTasks(tasks...), tt.tasks+1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = dag.Execute(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if got := int(peak.Load()); got != tt.limit {
+				t.Errorf("peak concurrency = %d, want %d", got, tt.limit)
+			}
+			if got := len(dag.Results()); got != tt.tasks+1 {
+				t.Errorf("got %d results, want %d", got, tt.tasks+1)
+			}
+		})
+	}
+}
+
+// waitStatus 等待任务达到指定状态
+func waitStatus(t *testing.T, dag *Dagcuter, name string, status Status) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		if res := dag.Results()[name]; res != nil && res.Status == status {
+			return
+		}
+	}
+	t.Fatalf("task %s did not reach %v", name, status)
+}
+
+func TestRerun(t *testing.T) {
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	tasks := newTestTasks(
+		&testTask{name: "a", run: func(context.Context) error {
+			if attempts.Add(1) == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		}},
+		&testTask{name: "b", deps: []string{"a"}},
+		// c 保持执行进行中, 以便重新执行失败的任务
+		&testTask{name: "c", run: func(context.Context) error {
+			<-release
+			return nil
+		}},
+	)
+	dag, err := New(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		_, err := dag.Execute(context.Background())
+		done <- err
+	}()
+	waitStatus(t, dag, "b", StatusSkipped)
+
+	if err = dag.Rerun("c"); err == nil {
+		t.Error("Rerun() of a running task error = nil")
+	}
+	if err = dag.Rerun("a"); err != nil {
+		t.Fatal(err)
+	}
+	waitStatus(t, dag, "b", StatusSucceeded)
+	close(release)
+	if err = <-done; err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+	if res := dag.Results()["a"]; res == nil || res.Status != StatusSucceeded {
+		t.Errorf("task a status = %v, want %v", res, StatusSucceeded)
+	}
+	if err = dag.Rerun("a"); !errors.Is(err, ErrNotInProgress) {
+		t.Errorf("Rerun() after execution error = %v, want %v", err, ErrNotInProgress)
+	}
+}
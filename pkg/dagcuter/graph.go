@@ -0,0 +1,211 @@
+package dagcuter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Graph 静态依赖图, 不执行任务, 用于分析拓扑层级及关键路径
+type Graph struct {
+	nodes      []string
+	deps       map[string][]string
+	dependents map[string][]string
+	levels     [][]string
+}
+
+// MissingDependencyError 任务依赖了不存在的任务
+type MissingDependencyError struct {
+	Task       string
+	Dependency string
+}
+
+func (e *MissingDependencyError) Error() string {
+	return fmt.Sprintf("task %s depends on unknown task %s", e.Task, e.Dependency)
+}
+
+// CycleError 循环依赖, Path为首尾相同的依赖链, 前一个任务依赖后一个任务
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("circular dependency detected: %s", strings.Join(e.Path, " -> "))
+}
+
+// NewGraph 根据任务名称及其依赖构建依赖图, 返回所有不存在的依赖及发现的循环依赖
+func NewGraph(deps map[string][]string) (*Graph, error) {
+	g := &Graph{
+		deps:       make(map[string][]string, len(deps)),
+		dependents: make(map[string][]string),
+	}
+	for name := range deps {
+		g.nodes = append(g.nodes, name)
+	}
+	slices.Sort(g.nodes)
+
+	var errs []error
+	for _, name := range g.nodes {
+		for _, dep := range deps[name] {
+			if _, ok := deps[dep]; !ok {
+				errs = append(errs, &MissingDependencyError{Task: name, Dependency: dep})
+				continue
+			}
+			g.deps[name] = append(g.deps[name], dep)
+			g.dependents[dep] = append(g.dependents[dep], name)
+		}
+	}
+	if path := g.cycle(); path != nil {
+		errs = append(errs, &CycleError{Path: path})
+	}
+	if errs != nil {
+		return nil, errors.Join(errs...)
+	}
+
+	// 按入度逐层剥离, 同一层的任务相互独立可并行执行
+	inDegrees := make(map[string]int, len(g.nodes))
+	var level []string
+	for _, name := range g.nodes {
+		inDegrees[name] = len(g.deps[name])
+		if inDegrees[name] == 0 {
+			level = append(level, name)
+		}
+	}
+	for len(level) > 0 {
+		g.levels = append(g.levels, level)
+		var next []string
+		for _, name := range level {
+			for _, child := range g.dependents[name] {
+				inDegrees[child]--
+				if inDegrees[child] == 0 {
+					next = append(next, child)
+				}
+			}
+		}
+		slices.Sort(next)
+		level = next
+	}
+	return g, nil
+}
+
+// cycle 查找一条循环依赖链, 不存在时返回nil
+func (g *Graph) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	var marks = make(map[string]int, len(g.nodes))
+	var stack []string
+	var dfs func(name string) []string
+	dfs = func(name string) []string {
+		marks[name] = visiting
+		stack = append(stack, name)
+		for _, dep := range g.deps[name] {
+			switch marks[dep] {
+			case visiting:
+				path := slices.Clone(stack[slices.Index(stack, dep):])
+				return append(path, dep)
+			case unvisited:
+				if path := dfs(dep); path != nil {
+					return path
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		marks[name] = visited
+		return nil
+	}
+	for _, name := range g.nodes {
+		if marks[name] == unvisited {
+			if path := dfs(name); path != nil {
+				return path
+			}
+		}
+	}
+	return nil
+}
+
+// Nodes 所有任务名称, 按名称排序
+func (g *Graph) Nodes() []string {
+	return slices.Clone(g.nodes)
+}
+
+// Dependencies 任务的直接依赖
+func (g *Graph) Dependencies(name string) []string {
+	return slices.Clone(g.deps[name])
+}
+
+// Edges 所有依赖边, [0]为上游任务, [1]为下游任务
+func (g *Graph) Edges() [][2]string {
+	var edges [][2]string
+	for _, name := range g.nodes {
+		for _, child := range g.dependents[name] {
+			edges = append(edges, [2]string{name, child})
+		}
+	}
+	return edges
+}
+
+// Levels 拓扑层级, 每层任务仅依赖之前层级的任务
+func (g *Graph) Levels() [][]string {
+	levels := make([][]string, len(g.levels))
+	for k, level := range g.levels {
+		levels[k] = slices.Clone(level)
+	}
+	return levels
+}
+
+// Heights 各任务到末端任务的最长依赖链上的任务数(含自身), 即任务阻塞的剩余路径长度
+func (g *Graph) Heights() map[string]int {
+	heights := make(map[string]int, len(g.nodes))
+	for k := len(g.levels) - 1; k >= 0; k-- {
+		for _, name := range g.levels[k] {
+			var height int
+			for _, child := range g.dependents[name] {
+				height = max(height, heights[child])
+			}
+			heights[name] = height + 1
+		}
+	}
+	return heights
+}
+
+// CriticalPath 按任务耗时计算耗时最长的依赖链, 即决定整体执行时间的路径
+func (g *Graph) CriticalPath(duration func(name string) time.Duration) ([]string, time.Duration) {
+	var total = make(map[string]time.Duration, len(g.nodes))
+	var prev = make(map[string]string, len(g.nodes))
+	var last string
+	for _, level := range g.levels {
+		for _, name := range level {
+			var longest time.Duration
+			for _, dep := range g.deps[name] {
+				if _, ok := prev[name]; !ok || total[dep] > longest {
+					longest = total[dep]
+					prev[name] = dep
+				}
+			}
+			total[name] = longest + max(duration(name), 0)
+			if last == "" || total[name] > total[last] {
+				last = name
+			}
+		}
+	}
+	if last == "" {
+		return nil, 0
+	}
+
+	var path []string
+	for name := last; ; {
+		path = append(path, name)
+		dep, ok := prev[name]
+		if !ok {
+			break
+		}
+		name = dep
+	}
+	slices.Reverse(path)
+	return path, total[last]
+}
@@ -0,0 +1,77 @@
+package dagcuter
+
+import "time"
+
+// Observer receives the lifecycle of every task and of the whole execution.
+// Callbacks are invoked synchronously from the goroutine running the task,
+// implementations should return quickly and must be safe for concurrent use.
+type Observer interface {
+	// OnReady is called when all dependencies of the task are completed and it is scheduled
+	OnReady(event *TaskEvent)
+	// OnStart is called before each attempt of the task
+	OnStart(event *TaskEvent)
+	// OnRetry is called when an attempt failed and the task is retried after Delay
+	OnRetry(event *TaskEvent)
+	// OnSkip is called when the task is skipped, Reason holds why
+	OnSkip(event *TaskEvent)
+	// OnSuccess is called when the task succeeded
+	OnSuccess(event *TaskEvent)
+	// OnFailure is called when the task failed after all attempts
+	OnFailure(event *TaskEvent)
+	// OnComplete is called once all tasks of the execution are completed
+	OnComplete(event *ExecutionEvent)
+}
+
+// TaskEvent 任务生命周期事件
+type TaskEvent struct {
+	Task string
+	// Attempt 当前尝试次数, 从1开始
+	Attempt int
+	// Time 事件发生时间
+	Time time.Time
+	// Duration OnRetry为本次尝试的耗时, OnSuccess/OnFailure为包含所有重试的总耗时
+	Duration time.Duration
+	// Delay 距下次重试的等待时间, 仅OnRetry有值
+	Delay time.Duration
+	// Err 失败原因, OnRetry/OnFailure有值
+	Err error
+	// Reason 跳过原因, 仅OnSkip有值
+	Reason string
+	// Output 任务输出, 仅OnSuccess有值
+	Output map[string]any
+	// Tolerated 失败被容忍, 仅OnFailure有值
+	Tolerated bool
+}
+
+// ExecutionEvent 整体执行结束事件
+type ExecutionEvent struct {
+	Time     time.Time
+	Duration time.Duration
+	// Err 所有未被容忍的任务失败原因
+	Err error
+	// Results 各任务的执行结果
+	Results map[string]*Result
+}
+
+// NopObserver 空实现, 嵌入后只需实现关心的回调
+type NopObserver struct{}
+
+func (NopObserver) OnReady(*TaskEvent)         {}
+func (NopObserver) OnStart(*TaskEvent)         {}
+func (NopObserver) OnRetry(*TaskEvent)         {}
+func (NopObserver) OnSkip(*TaskEvent)          {}
+func (NopObserver) OnSuccess(*TaskEvent)       {}
+func (NopObserver) OnFailure(*TaskEvent)       {}
+func (NopObserver) OnComplete(*ExecutionEvent) {}
+
+// SetObserver 设置生命周期观察者, 需在Execute之前调用
+func (d *Dagcuter) SetObserver(observer Observer) {
+	d.observer = observer
+}
+
+// notify 调用观察者回调, 未设置观察者时忽略
+func (d *Dagcuter) notify(fn func(observer Observer)) {
+	if d.observer != nil {
+		fn(d.observer)
+	}
+}
@@ -0,0 +1,134 @@
+package dagcuter
+
+import (
+	"container/heap"
+	"context"
+)
+
+// readyQueue 就绪任务队列, 优先级高的先调度, 同优先级时阻塞剩余路径更长的先调度
+type readyQueue struct {
+	names    []string
+	priority map[string]int
+	height   map[string]int
+}
+
+func newReadyQueue(tasks map[string]Task, heights map[string]int) *readyQueue {
+	q := &readyQueue{
+		priority: make(map[string]int, len(tasks)),
+		height:   heights,
+	}
+	for name, task := range tasks {
+		if prioritized, ok := task.(PrioritizedTask); ok {
+			q.priority[name] = prioritized.Priority()
+		}
+	}
+	return q
+}
+
+func (q *readyQueue) Len() int {
+	return len(q.names)
+}
+
+func (q *readyQueue) Less(i, j int) bool {
+	a, b := q.names[i], q.names[j]
+	if q.priority[a] != q.priority[b] {
+		return q.priority[a] > q.priority[b]
+	}
+	if q.height[a] != q.height[b] {
+		return q.height[a] > q.height[b]
+	}
+	return a < b
+}
+
+func (q *readyQueue) Swap(i, j int) {
+	q.names[i], q.names[j] = q.names[j], q.names[i]
+}
+
+func (q *readyQueue) Push(x any) {
+	q.names = append(q.names, x.(string))
+}
+
+func (q *readyQueue) Pop() any {
+	n := len(q.names)
+	name := q.names[n-1]
+	q.names = q.names[:n-1]
+	return name
+}
+
+// enqueue 将就绪任务加入队列并唤醒调度
+func (d *Dagcuter) enqueue(names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	d.mu.Lock()
+	for _, name := range names {
+		heap.Push(d.queue, name)
+	}
+	d.mu.Unlock()
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch 按队列顺序将就绪任务提交到执行池, 所有任务结束后退出
+func (d *Dagcuter) dispatch(ctx context.Context) {
+	for {
+		var name string
+		d.mu.Lock()
+		ok := d.queue.Len() > 0
+		if ok {
+			name = heap.Pop(d.queue).(string)
+		}
+		d.mu.Unlock()
+		if !ok {
+			select {
+			case <-d.wake:
+			case <-d.done:
+				return
+			}
+			continue
+		}
+		throttle, ok := d.acquire(name)
+		if !ok {
+			continue
+		}
+		// 提交阻塞到有空闲的工作者, 期间新就绪的任务在队列中按顺序等待
+		_ = d.worker.Submit(func() error {
+			defer d.release(throttle)
+			d.runTask(ctx, name)
+			return nil
+		})
+	}
+}
+
+// acquire 占用任务的并行数, 已满时任务暂存, 同组任务结束后重新入队
+func (d *Dagcuter) acquire(name string) (chan struct{}, bool) {
+	throttled, ok := d.Tasks[name].(ThrottledTask)
+	if !ok || throttled.Throttle() == nil {
+		return nil, true
+	}
+	throttle := throttled.Throttle()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case throttle <- struct{}{}:
+		return throttle, true
+	default:
+		d.throttled = append(d.throttled, name)
+		return nil, false
+	}
+}
+
+// release 释放并行数, 暂存的任务重新入队等待调度
+func (d *Dagcuter) release(throttle chan struct{}) {
+	if throttle == nil {
+		return
+	}
+	d.mu.Lock()
+	<-throttle
+	names := d.throttled
+	d.throttled = nil
+	d.mu.Unlock()
+	d.enqueue(names...)
+}
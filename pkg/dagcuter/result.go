@@ -0,0 +1,44 @@
+package dagcuter
+
+// Status 任务执行状态
+type Status int
+
+const (
+	StatusPending Status = iota
+	StatusSucceeded
+	StatusFailed
+	StatusSkipped
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPending:
+		return "pending"
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// Result 任务执行结果
+type Result struct {
+	Status Status
+	// Output 任务输出, 仅成功时有值
+	Output map[string]any
+	// Err 失败原因
+	Err error
+	// Reason 跳过原因
+	Reason string
+	// Tolerated 失败被容忍, 不影响下游任务及整体结果
+	Tolerated bool
+}
+
+// Satisfied 下游任务是否可视为该依赖已满足
+func (r *Result) Satisfied() bool {
+	return r.Status == StatusSucceeded || (r.Status == StatusFailed && r.Tolerated)
+}
@@ -4,18 +4,31 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"time"
 )
 
+const (
+	// JitterFull 等待时间在 [0, backoff] 之间随机
+	JitterFull = "full"
+	// JitterEqual 等待时间在 [backoff/2, backoff] 之间随机
+	JitterEqual = "equal"
+)
+
 type RetryPolicy struct {
 	Interval    time.Duration `json:"interval" yaml:"interval"`
 	MaxInterval time.Duration `json:"maxInterval" yaml:"maxInterval"`
 	MaxAttempts int           `json:"maxAttempts" yaml:"maxAttempts"`
 	Multiplier  float64       `json:"multiplier" yaml:"multiplier"`
+	Jitter      string        `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	// RetryIf 第attempt次执行失败后判断是否继续重试, 为nil时重试所有错误
+	RetryIf func(attempt int, err error) bool `json:"-" yaml:"-"`
 }
 
 type RetryExecutor struct {
 	policy *RetryPolicy
+	// onRetry 决定重试后、等待前调用
+	onRetry func(attempt int, err error, delay time.Duration)
 }
 
 func (d *Dagcuter) newRetryExecutor(policy *RetryPolicy) *RetryExecutor {
@@ -67,8 +80,17 @@ func (r *RetryExecutor) ExecuteWithRetry(ctx context.Context, taskName string, f
 			break
 		}
 
+		// 不满足重试条件的错误直接返回
+		if r.policy.RetryIf != nil && !r.policy.RetryIf(attempt, lastErr) {
+			return fmt.Errorf("task %s failed at attempt %d, not retryable: %w",
+				taskName, attempt, lastErr)
+		}
+
 		// 计算等待时间（指数退避）
 		waitTime := r.calculateBackoff(attempt, r.policy.MaxInterval)
+		if r.onRetry != nil {
+			r.onRetry(attempt, lastErr, waitTime)
+		}
 
 		// 等待重试
 		select {
@@ -93,7 +115,15 @@ func (r *RetryExecutor) calculateBackoff(attempt int, maxInterval time.Duration)
 
 	// 确保不超过最大间隔
 	if result > maxInterval {
-		return maxInterval
+		result = maxInterval
+	}
+
+	// 加入随机抖动, 避免大量任务同时重试
+	switch r.policy.Jitter {
+	case JitterFull:
+		result = time.Duration(rand.Int64N(int64(result) + 1))
+	case JitterEqual:
+		result = result/2 + time.Duration(rand.Int64N(int64(result/2)+1))
 	}
 
 	return result
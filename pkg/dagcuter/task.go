@@ -14,3 +14,47 @@ type Task interface {
 	// PostExecution is called after Execute
 	PostExecution(ctx context.Context, output map[string]any) error
 }
+
+// ConditionalTask is an optional interface, a task implementing it decides
+// whether to run from the results of its dependencies. Tasks that do not
+// implement it only run when all dependencies succeeded.
+// ShouldRun is also consulted once the execution is cancelled, a task that
+// still runs then executes with a context detached from the cancellation.
+type ConditionalTask interface {
+	Task
+	// ShouldRun returns whether the task should run, and the reason when it should not
+	ShouldRun(ctx context.Context, upstream map[string]*Result) (run bool, reason string, err error)
+}
+
+// FailureTolerantTask is an optional interface, a failed task whose
+// AllowFailure returns true is recorded as failed, but satisfies its
+// dependents and does not fail the execution
+type FailureTolerantTask interface {
+	Task
+	AllowFailure() bool
+}
+
+// PrioritizedTask is an optional interface, when more tasks are ready than
+// the pool has workers, tasks with a higher Priority are dispatched first.
+// Tasks that do not implement it have priority 0.
+type PrioritizedTask interface {
+	Task
+	Priority() int
+}
+
+// ThrottledTask is an optional interface, tasks returning the same Throttle
+// channel run at most cap(channel) at a time. The slot is taken before the
+// task is submitted to the pool, a throttled task waits without holding a
+// worker and the tasks queued after it are dispatched meanwhile.
+type ThrottledTask interface {
+	Task
+	Throttle() chan struct{}
+}
+
+// SkippableTask is an optional interface, Skip is called when the task is
+// skipped because its condition is not met or the execution was cancelled
+type SkippableTask interface {
+	Task
+	// Skip is called instead of PreExecution/Execute/PostExecution
+	Skip(ctx context.Context, reason string)
+}
@@ -27,6 +27,8 @@ var (
 const (
 	// 最大缓冲区大小 100MB
 	maxCapacity = 100 * 1024 * 1024
+	// 进程退出后等待读取剩余输出的最长时间
+	drainTimeout = 5 * time.Second
 )
 
 // Logger 日志接口，允许自定义日志实现
@@ -216,14 +218,19 @@ func (s *script) exec(command string, args ...string) (code int, err error) {
 	if s.stdin != nil {
 		s.cmd.Stdin = s.stdin
 	}
-	stdout, err := s.cmd.StdoutPipe()
+	// 使用自建管道, 进程退出后继续读取管道中剩余的输出, StdoutPipe会在Wait时关闭管道导致输出丢失
+	stdout, stdoutWriter, err := os.Pipe()
 	if err != nil {
 		return 255, err
 	}
-	stderr, err := s.cmd.StderrPipe()
+	stderr, stderrWriter, err := os.Pipe()
 	if err != nil {
+		_ = stdout.Close()
+		_ = stdoutWriter.Close()
 		return 255, err
 	}
+	s.cmd.Stdout = stdoutWriter
+	s.cmd.Stderr = stderrWriter
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
@@ -235,7 +242,9 @@ func (s *script) exec(command string, args ...string) (code int, err error) {
 		s.consoleOutput("STDERR", stderr)
 	}()
 	err = s.cmd.Run()
-	wg.Wait()
+	_ = stdoutWriter.Close()
+	_ = stderrWriter.Close()
+	s.drainOutput(&wg, stdout, stderr)
 
 	// 僵尸进程收割会触发no child processes
 	if err != nil && strings.Contains(err.Error(), "waitid: no child processes") {
@@ -251,6 +260,24 @@ func (s *script) exec(command string, args ...string) (code int, err error) {
 	return code, err
 }
 
+// drainOutput 等待输出读取完毕, 后台子进程仍持有管道时超时后强制关闭
+func (s *script) drainOutput(wg *sync.WaitGroup, readers ...io.Closer) {
+	var done = make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(drainTimeout):
+		s.logger.Errorf("[SYSTEM] the output is still held by background processes after %s", drainTimeout)
+	}
+	for _, reader := range readers {
+		_ = reader.Close()
+	}
+	<-done
+}
+
 func (s *script) consoleOutput(title string, reader io.ReadCloser) {
 	defer func() {
 		if r := recover(); r != nil {